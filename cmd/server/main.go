@@ -2,60 +2,423 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"reflect"
+	"sort"
 	"syscall"
 	"time"
 
 	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/clock"
 	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/grpcserver"
+	"exchange-rate-service/internal/lifecycle"
 	"exchange-rate-service/internal/repository"
 	"exchange-rate-service/internal/service"
+	"exchange-rate-service/internal/telemetry"
+	"exchange-rate-service/internal/utils"
 	"exchange-rate-service/pkg/logger"
+
+	"go.uber.org/zap"
 )
 
+// cacheJanitorInterval is how often expired cache items are evicted.
+const cacheJanitorInterval = 5 * time.Minute
+
+// apiKeyUsageRetention is how long per-key daily usage is kept for reports.
+const apiKeyUsageRetention = 32 * 24 * time.Hour
+
 func main() {
-	cfg, err := config.Load()
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatal("Failed to load config:", err)
+		log.Fatal("Failed to load config: ", err)
 	}
 
-	logger := logger.NewLogger(cfg.LogLevel)
+	logLevel := zap.NewAtomicLevelAt(logger.ParseLevel(cfg.LogLevel))
+	logger := logger.NewLoggerAt(logLevel)
 	defer logger.Sync()
 
+	if err := utils.SetEnabledCurrencies(cfg.EnabledCurrencies); err != nil {
+		logger.Fatal("Invalid enabled currencies: " + err.Error())
+	}
+
+	shutdownTracing, err := telemetry.Setup(context.Background(), cfg.OTLPEndpoint, cfg.ServiceName)
+	if err != nil {
+		logger.Fatal("Failed to set up tracing: " + err.Error())
+	}
+
+	// Workers start in the order they are added and stop in reverse, so the
+	// servers, added last, stop first.
+	workers := lifecycle.NewManager(logger)
+
 	cacheRepo := repository.NewCacheRepository()
-	apiRepo := repository.NewExchangeAPIRepository(cfg.APIKey, cfg.BaseURL)
+	workers.Go("cache janitor", func(ctx context.Context) {
+		cacheRepo.StartJanitor(ctx, cacheJanitorInterval)
+	})
+	// Buckets are kept per process unless RATE_LIMIT_DIR names a directory
+	// the replicas share.
+	var rateLimitCache domain.CacheRepository
+	if cfg.RateLimitDir != "" {
+		sharedCache, err := repository.NewSharedCacheRepository(cfg.RateLimitDir)
+		if err != nil {
+			logger.Fatal("Failed to open shared rate limit store: " + err.Error())
+		}
+		workers.Go("rate limit janitor", func(ctx context.Context) {
+			sharedCache.StartJanitor(ctx, cacheJanitorInterval)
+		})
+		rateLimitCache = sharedCache
+	} else {
+		localCache := repository.NewCacheRepository()
+		workers.Go("rate limit janitor", func(ctx context.Context) {
+			localCache.StartJanitor(ctx, cacheJanitorInterval)
+		})
+		rateLimitCache = localCache
+	}
+
+	// Snapshots are the whole rate source with RATE_PROVIDER=file and the
+	// fallback for exchangerate.host otherwise.
+	var snapshotRepo domain.ExchangeRepository
+	fileRepo, err := repository.NewFileRatesRepository(cfg.RateSnapshotDir, logger)
+	if err != nil {
+		logger.Warn("Rate snapshots unavailable: " + err.Error())
+	} else {
+		snapshotRepo = fileRepo
+		workers.Go("snapshot watcher", func(ctx context.Context) {
+			fileRepo.Watch(ctx, time.Duration(cfg.SnapshotPoll)*time.Second)
+		})
+	}
+
+	var apiRepo domain.ExchangeRepository
+	if cfg.RateProvider == "consensus" {
+		apiRepo, err = newConsensusProvider(cfg, snapshotRepo, logger)
+	} else {
+		apiRepo, err = newRateProvider(cfg.RateProvider, cfg, snapshotRepo, true, logger)
+	}
+	if err != nil {
+		logger.Fatal("Failed to set up rate provider: " + err.Error())
+	}
+
+	overrideRepo, err := repository.NewOverrideRepository(cfg.OverrideStorePath)
+	if err != nil {
+		logger.Fatal("Failed to load override store: " + err.Error())
+	}
+	overrideService := service.NewOverrideService(overrideRepo, logger)
+
+	markupProfiles, err := repository.LoadMarkupProfiles(cfg.MarkupProfilePath)
+	if err != nil {
+		logger.Fatal("Failed to load markup profiles: " + err.Error())
+	}
+	markupService, err := service.NewMarkupService(markupProfiles, cfg.MarkupDefaultProfile, logger)
+	if err != nil {
+		logger.Fatal("Invalid markup profiles: " + err.Error())
+	}
+
+	anomalyRepo, err := repository.NewAnomalyRepository(cfg.AnomalyStorePath, cfg.AnomalyRetention)
+	if err != nil {
+		logger.Fatal("Failed to load anomaly store: " + err.Error())
+	}
+	rateGuard := service.NewRateGuard(anomalyRepo, clock.System{}, service.GuardOptions{
+		InverseTolerance: cfg.GuardInverseTolerance / 100,
+		MaxSigma:         cfg.GuardMaxSigma,
+		MinHistory:       cfg.GuardMinHistory,
+		ConfirmAfter:     cfg.GuardConfirmAfter,
+	}, logger)
+
+	fixingRepo, err := repository.NewFixingRepository(cfg.FixingStorePath)
+	if err != nil {
+		logger.Fatal("Failed to load fixing store: " + err.Error())
+	}
+	fixingSchedule, err := service.ParseFixingSchedule(cfg.FixingTime, cfg.FixingTimezone)
+	if err != nil {
+		logger.Fatal("Invalid fixing schedule: " + err.Error())
+	}
+
+	rateHistory, err := repository.NewObservationRepository(cfg.RateHistoryStorePath, time.Duration(cfg.RateHistoryRetention)*24*time.Hour)
+	if err != nil {
+		logger.Fatal("Failed to load rate history: " + err.Error())
+	}
+
+	var demand *service.DemandTracker
+	if cfg.DemandTopPairs > 0 || cfg.DemandTopBases > 0 {
+		demand = service.NewDemandTracker(clock.System{}, service.DemandOptions{
+			TopPairs: cfg.DemandTopPairs,
+			TopBases: cfg.DemandTopBases,
+			HalfLife: time.Duration(cfg.DemandHalfLife) * time.Second,
+			MinScore: cfg.DemandMinScore,
+		})
+	}
+	updateGroups, updateSchedules, err := loadUpdateSchedules(cfg, demand != nil)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	updateRuns, err := repository.NewUpdateRunRepository(cfg.UpdateStatePath)
+	if err != nil {
+		logger.Fatal("Failed to load updater state: " + err.Error())
+	}
 
-	exchangeService := service.NewExchangeService(cacheRepo, apiRepo, logger)
+	exchangeService := service.NewExchangeService(cacheRepo, apiRepo, logger,
+		service.WithOverrides(overrideService),
+		service.WithMarkups(markupService),
+		service.WithRateGuard(rateGuard),
+		service.WithFixings(fixingRepo, fixingSchedule),
+		service.WithRateHistory(rateHistory),
+		service.WithUpdater(service.UpdaterOptions{
+			Schedules: updateSchedules,
+			Runs:      updateRuns,
+			Jitter:    time.Duration(cfg.UpdateJitter) * time.Second,
+			Clock:     clock.System{},
+		}),
+		service.WithDemand(demand),
+		service.WithCacheTTLs(service.CacheTTLs{
+			Latest:     time.Duration(cfg.CacheExpiration) * time.Second,
+			Historical: time.Duration(cfg.CacheHistoricalExpiration) * time.Second,
+		}),
+	)
 
-	go exchangeService.StartRateUpdater(context.Background())
+	// Only the settings config.Watcher treats as reloadable are applied;
+	// the watcher logs the rest as waiting for a restart.
+	applied := cfg
+	watcher := config.NewWatcher(cfg, os.Args[1:], logger, func(next *config.Config) error {
+		if err := utils.SetEnabledCurrencies(next.EnabledCurrencies); err != nil {
+			return err
+		}
+		groups, schedules, err := loadUpdateSchedules(next, demand != nil)
+		if err != nil {
+			utils.SetEnabledCurrencies(applied.EnabledCurrencies)
+			return err
+		}
 
-	router := api.NewRouter(exchangeService, logger)
+		if err := logLevel.UnmarshalText([]byte(next.LogLevel)); err != nil {
+			return err
+		}
+		exchangeService.SetCacheTTLs(service.CacheTTLs{
+			Latest:     time.Duration(next.CacheExpiration) * time.Second,
+			Historical: time.Duration(next.CacheHistoricalExpiration) * time.Second,
+		})
+		if !reflect.DeepEqual(groups, updateGroups) {
+			exchangeService.SetUpdateSchedules(schedules)
+			updateGroups = groups
+		}
+		applied = next
+		return nil
+	})
+	workers.Go("config watcher", watcher.Run)
+
+	webhookRepo, err := repository.NewWebhookRepository(cfg.WebhookStorePath, cfg.WebhookDeadLetters)
+	if err != nil {
+		logger.Fatal("Failed to load webhook store: " + err.Error())
+	}
+	webhookService := service.NewWebhookService(webhookRepo, logger, cfg.WebhookMaxAttempts, time.Duration(cfg.WebhookBackoff)*time.Second)
+
+	rateUpdates, unsubscribe := exchangeService.SubscribeAllUpdates()
+	defer unsubscribe()
+	workers.Go("webhooks", func(ctx context.Context) {
+		webhookService.Run(ctx, rateUpdates)
+		webhookService.Wait()
+	})
+	workers.Go("rate updater", exchangeService.StartRateUpdater)
+	workers.Go("fixing scheduler", exchangeService.StartFixingScheduler)
+
+	apiKeyRepo, err := repository.NewAPIKeyRepository(cfg.APIKeyStorePath)
+	if err != nil {
+		logger.Fatal("Failed to load API key store: " + err.Error())
+	}
+
+	apiKeyUsageRepo, err := repository.NewAPIKeyUsageRepository(cfg.APIKeyUsagePath, apiKeyUsageRetention)
+	if err != nil {
+		logger.Fatal("Failed to load API key usage store: " + err.Error())
+	}
+
+	rateLimiter := service.NewRateLimiter(rateLimitCache)
+
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, apiKeyUsageRepo, rateLimiter, logger, cfg.APIKeyRateLimit, cfg.APIKeyDailyQuota)
+
+	quoteRepo, err := repository.NewQuoteRepository(cfg.QuoteStoreDir)
+	if err != nil {
+		logger.Fatal("Failed to load quote store: " + err.Error())
+	}
+
+	streams := lifecycle.NewConnections()
+	router := api.NewRouter(exchangeService, logger,
+		api.WithWebSocketLimits(cfg.WSMaxConns, cfg.WSSendBuffer),
+		api.WithCORSOrigins(cfg.CORSAllowedOrigins),
+		api.WithConnections(streams),
+		api.WithWebhooks(webhookService),
+		api.WithAPIKeys(apiKeyService, cfg.AuthEnabled),
+		api.WithAdminToken(cfg.AdminToken),
+		api.WithRateLimit(rateLimiter, cfg.RateLimit, time.Duration(cfg.RateLimitWindow)*time.Second, cfg.RateLimitCosts),
+		api.WithGraphQLLimits(cfg.GraphQLMaxDepth, cfg.GraphQLMaxComplexity),
+		api.WithCacheAdmin(service.NewCacheAdminService(cacheRepo, exchangeService, logger)),
+		api.WithOverrides(overrideService),
+		api.WithMarkups(markupService),
+		api.WithRateGuard(rateGuard),
+		api.WithDemand(demand),
+		api.WithQuotes(service.NewQuoteService(exchangeService, quoteRepo, clock.System{}, time.Duration(cfg.QuoteTTL)*time.Second, logger)),
+	)
+
+	var apiKeyAuth *service.APIKeyService
+	if cfg.AuthEnabled {
+		apiKeyAuth = apiKeyService
+	}
+	grpcServer := grpcserver.New(exchangeService, logger, apiKeyAuth, grpcserver.RateLimit{
+		Limiter: rateLimiter,
+		Limit:   cfg.RateLimit,
+		Window:  time.Duration(cfg.RateLimitWindow) * time.Second,
+		Costs:   cfg.RateLimitCosts,
+	})
+
+	if cfg.GRPCPort != "" {
+		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			logger.Fatal("Failed to listen for gRPC: " + err.Error())
+		}
+
+		workers.Add(lifecycle.Worker{
+			Name: "gRPC server",
+			Run: func(ctx context.Context) error {
+				logger.Info("gRPC server starting on port " + cfg.GRPCPort)
+				return grpcServer.Serve(lis)
+			},
+			Stop: func(ctx context.Context) error {
+				stopped := make(chan struct{})
+				go func() {
+					grpcServer.GracefulStop()
+					close(stopped)
+				}()
+				select {
+				case <-stopped:
+				case <-ctx.Done():
+					grpcServer.Stop()
+				}
+				return nil
+			},
+		})
+	}
 
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
 		Handler: router,
 	}
 
-	go func() {
-		logger.Info("Server starting on port " + cfg.Port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("Failed to start server: " + err.Error())
-		}
-	}()
+	// Added before the HTTP server so it stops after it: no new streams are
+	// accepted while the open ones are closed.
+	workers.Add(streams.Worker("WebSocket streams"))
+
+	workers.Add(lifecycle.Worker{
+		Name: "HTTP server",
+		Run: func(ctx context.Context) error {
+			logger.Info("Server starting on port " + cfg.Port)
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				return err
+			}
+			return nil
+		},
+		Stop: srv.Shutdown,
+	})
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	logger.Info("Shutting down server...")
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	runErr := workers.Run(ctx, time.Duration(cfg.ShutdownTimeout)*time.Second)
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	if err := srv.Shutdown(ctx); err != nil {
-		logger.Fatal("Server forced to shutdown: " + err.Error())
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Warn("Failed to flush traces: " + err.Error())
 	}
 
+	if runErr != nil {
+		logger.Error("Server exited with errors", zap.Error(runErr))
+		logger.Sync()
+		os.Exit(1)
+	}
 	logger.Info("Server exited")
 }
+
+// loadUpdateSchedules returns the update groups in UPDATE_SCHEDULE_PATH and
+// their parsed schedules. With demand tracking on, a demand group on
+// DEMAND_CRON keeps the hot set warm on top of them; without a schedule
+// file it is the only group, so cold currencies are fetched on demand
+// rather than refreshed. With tracking off the default group is used.
+func loadUpdateSchedules(cfg *config.Config, demand bool) ([]domain.UpdateGroup, []service.UpdateSchedule, error) {
+	groups, err := repository.LoadUpdateGroups(cfg.UpdateSchedulePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load update schedule: %w", err)
+	}
+	if len(groups) == 0 && !demand {
+		groups = []domain.UpdateGroup{service.DefaultUpdateGroup(cfg.UpdateCron, cfg.UpdateTimezone)}
+	}
+	if demand {
+		groups = service.AddDemandGroup(groups, cfg.DemandCron, cfg.UpdateTimezone)
+	}
+
+	schedules, err := service.ParseUpdateGroups(groups)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid update schedule: %w", err)
+	}
+	return groups, schedules, nil
+}
+
+// newRateProvider builds the named provider on top of the loaded snapshots.
+// With fallback set, exchangerate.host serves snapshot rates when it fails.
+func newRateProvider(name string, cfg *config.Config, snapshots domain.ExchangeRepository, fallback bool, logger *zap.Logger) (domain.ExchangeRepository, error) {
+	switch name {
+	case "exchangerate_host":
+		if !fallback {
+			snapshots = nil
+		}
+		repo := repository.NewExchangeAPIRepository(cfg.APIKey, cfg.BaseURL, snapshots, logger)
+		repo.SetFallbackMaxAge(time.Duration(cfg.FallbackMaxAge) * time.Second)
+		return repo, nil
+	case "file":
+		if snapshots == nil {
+			return nil, fmt.Errorf("no rate snapshots loaded from %s", cfg.RateSnapshotDir)
+		}
+		return snapshots, nil
+	case "ecb":
+		return repository.NewECBRepository(cfg.ECBBaseURL, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown rate provider: %s", name)
+	}
+}
+
+// newConsensusProvider combines the providers in CONSENSUS_PROVIDERS. They
+// run without fallback rates so a failing provider drops out of the vote
+// instead of voting with demo data.
+func newConsensusProvider(cfg *config.Config, snapshots domain.ExchangeRepository, logger *zap.Logger) (domain.ExchangeRepository, error) {
+	names := make([]string, 0, len(cfg.ConsensusProviders))
+	for name := range cfg.ConsensusProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sources := make([]repository.ConsensusSource, 0, len(names))
+	for _, name := range names {
+		repo, err := newRateProvider(name, cfg, snapshots, false, logger)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, repository.ConsensusSource{
+			Name:   name,
+			Repo:   repo,
+			Weight: float64(cfg.ConsensusProviders[name]),
+		})
+	}
+
+	return repository.NewConsensusRepository(sources, repository.ConsensusOptions{
+		Method:       cfg.ConsensusMethod,
+		MaxDeviation: cfg.ConsensusMaxDeviation / 100,
+		MinSources:   cfg.ConsensusMinSources,
+	}, logger)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AnomalyHandler struct {
+	service *service.RateGuard
+	logger  *zap.Logger
+}
+
+func NewAnomalyHandler(service *service.RateGuard, logger *zap.Logger) *AnomalyHandler {
+	return &AnomalyHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *AnomalyHandler) List(c *gin.Context) {
+	anomalies, err := h.service.List(c.Query("pair"), c.Query("unreviewed") == "true")
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"anomalies": anomalies,
+		"count":     len(anomalies),
+	})
+}
+
+func (h *AnomalyHandler) Review(c *gin.Context) {
+	anomaly, err := h.service.Review(c.Param("id"), c.GetHeader(actorHeader), c.Query("accept") == "true")
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, anomaly)
+}
+
+func (h *AnomalyHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(c, http.StatusNotFound, domain.CodeNotFound, "rate anomaly not found")
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), h.logger).Warn("Anomaly request failed", zap.Error(err))
+	problem.Error(c, err)
+}
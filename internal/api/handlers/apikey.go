@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type APIKeyHandler struct {
+	service *service.APIKeyService
+	logger  *zap.Logger
+}
+
+func NewAPIKeyHandler(service *service.APIKeyService, logger *zap.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req domain.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, domain.ValidationErrorf("%s", err))
+		return
+	}
+
+	created, err := h.service.Create(&req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	created.Hash = ""
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.service.List()
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	for i := range keys {
+		keys[i].Hash = ""
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"keys":  keys,
+		"count": len(keys),
+	})
+}
+
+func (h *APIKeyHandler) Get(c *gin.Context) {
+	key, err := h.service.Get(c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	key.Hash = ""
+	c.JSON(http.StatusOK, key)
+}
+
+func (h *APIKeyHandler) Update(c *gin.Context) {
+	var req domain.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, domain.ValidationErrorf("%s", err))
+		return
+	}
+
+	key, err := h.service.Update(c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	key.Hash = ""
+	c.JSON(http.StatusOK, key)
+}
+
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	if err := h.service.Revoke(c.Param("id")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *APIKeyHandler) Usage(c *gin.Context) {
+	days := 7
+	if daysStr := c.Query("days"); daysStr != "" {
+		var err error
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days < 1 || days > 31 {
+			problem.Error(c, domain.ValidationErrorf("days must be a number between 1 and 31"))
+			return
+		}
+	}
+
+	report, err := h.service.Usage(c.Param("id"), days)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *APIKeyHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(c, http.StatusNotFound, domain.CodeNotFound, "api key not found")
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), h.logger).Warn("API key request failed", zap.Error(err))
+	problem.Error(c, err)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type CacheHandler struct {
+	service *service.CacheAdminService
+	logger  *zap.Logger
+}
+
+func NewCacheHandler(service *service.CacheAdminService, logger *zap.Logger) *CacheHandler {
+	return &CacheHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *CacheHandler) List(c *gin.Context) {
+	entries := h.service.List(c.Query("prefix"))
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+func (h *CacheHandler) Get(c *gin.Context) {
+	entry, err := h.service.Get(c.Param("key"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+func (h *CacheHandler) Delete(c *gin.Context) {
+	if err := h.service.Invalidate(c.Param("key")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CacheHandler) DeletePrefix(c *gin.Context) {
+	deleted, err := h.service.InvalidatePrefix(c.Query("prefix"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
+
+func (h *CacheHandler) Refresh(c *gin.Context) {
+	result, err := h.service.Refresh(c.Request.Context(), c.Query("base"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *CacheHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(c, http.StatusNotFound, domain.CodeNotFound, "cache entry not found")
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), h.logger).Warn("Cache admin request failed", zap.Error(err))
+	problem.Error(c, err)
+}
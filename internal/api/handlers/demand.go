@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DemandHandler struct {
+	demand   *service.DemandTracker
+	exchange *service.ExchangeService
+}
+
+func NewDemandHandler(demand *service.DemandTracker, exchange *service.ExchangeService) *DemandHandler {
+	return &DemandHandler{
+		demand:   demand,
+		exchange: exchange,
+	}
+}
+
+// Get reports the hot set together with the update groups that keep it, and
+// any fixed bases and pairs, warm.
+func (h *DemandHandler) Get(c *gin.Context) {
+	hot := h.demand.HotSet()
+
+	c.JSON(http.StatusOK, gin.H{
+		"hot_pairs": hot.Pairs,
+		"hot_bases": hot.Bases,
+		"schedules": h.exchange.UpdateStatus(),
+	})
+}
@@ -3,14 +3,21 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"time"
 
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/api/render"
 	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
 	"exchange-rate-service/internal/service"
+	"exchange-rate-service/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+const maxBatchConversions = 100
+
 type ExchangeHandler struct {
 	service *service.ExchangeService
 	logger  *zap.Logger
@@ -30,11 +37,7 @@ func (h *ExchangeHandler) Convert(c *gin.Context) {
 	date := c.Query("date")
 
 	if from == "" || to == "" {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "missing_parameters",
-			Code:    400,
-			Message: "from and to currencies are required",
-		})
+		problem.Error(c, domain.ValidationErrorf("from and to currencies are required"))
 		return
 	}
 
@@ -43,36 +46,71 @@ func (h *ExchangeHandler) Convert(c *gin.Context) {
 		var err error
 		amount, err = strconv.ParseFloat(amountStr, 64)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-				Error:   "invalid_amount",
-				Code:    400,
-				Message: "amount must be a valid number",
-			})
+			problem.Error(c, domain.ValidationErrorf("amount must be a valid number"))
 			return
 		}
 	}
 
+	asOf, ok := parseAsOf(c)
+	if !ok {
+		return
+	}
+
 	req := &domain.ConversionRequest{
 		From:   from,
 		To:     to,
 		Amount: amount,
 		Date:   date,
+		AsOf:   asOf,
 	}
 
 	result, err := h.service.ConvertCurrency(c.Request.Context(), req)
 	if err != nil {
-		h.logger.Error("Conversion failed", zap.Error(err))
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "conversion_failed",
-			Code:    400,
-			Message: err.Error(),
-		})
+		requestid.Logger(c.Request.Context(), h.logger).Error("Conversion failed", zap.Error(err))
+		problem.Error(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
+func (h *ExchangeHandler) BatchConvert(c *gin.Context) {
+	var req domain.BatchConversionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, domain.ValidationErrorf("%s", err))
+		return
+	}
+
+	if len(req.Conversions) == 0 || len(req.Conversions) > maxBatchConversions {
+		problem.Error(c, domain.ValidationErrorf("between 1 and %d conversions are required", maxBatchConversions))
+		return
+	}
+
+	for i := range req.Conversions {
+		if req.Conversions[i].Amount == 0 {
+			req.Conversions[i].Amount = 1
+		}
+	}
+
+	results, errs := h.service.BatchConvert(c.Request.Context(), req.Conversions)
+
+	resp := &domain.BatchConversionResponse{
+		Results: make([]domain.BatchConversionResult, len(req.Conversions)),
+		Count:   len(req.Conversions),
+	}
+	for i := range req.Conversions {
+		resp.Results[i] = domain.BatchConversionResult{
+			Request: req.Conversions[i],
+			Result:  results[i],
+		}
+		if errs[i] != nil {
+			resp.Results[i].Error = problem.Response(c.Request.Context(), errs[i])
+		}
+	}
+
+	render.Negotiated(c, http.StatusOK, resp)
+}
+
 func (h *ExchangeHandler) GetLatestRates(c *gin.Context) {
 	baseCurrency := c.Query("base")
 	if baseCurrency == "" {
@@ -81,16 +119,12 @@ func (h *ExchangeHandler) GetLatestRates(c *gin.Context) {
 
 	result, err := h.service.GetLatestRates(c.Request.Context(), baseCurrency)
 	if err != nil {
-		h.logger.Error("Failed to get latest rates", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
-			Error:   "fetch_failed",
-			Code:    500,
-			Message: err.Error(),
-		})
+		requestid.Logger(c.Request.Context(), h.logger).Error("Failed to get latest rates", zap.Error(err))
+		problem.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	render.Negotiated(c, http.StatusOK, result)
 }
 
 func (h *ExchangeHandler) GetHistoricalRates(c *gin.Context) {
@@ -100,36 +134,46 @@ func (h *ExchangeHandler) GetHistoricalRates(c *gin.Context) {
 	endDate := c.Query("end_date")
 
 	if from == "" || to == "" || startDate == "" || endDate == "" {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "missing_parameters",
-			Code:    400,
-			Message: "from, to, start_date, and end_date are required",
-		})
+		problem.Error(c, domain.ValidationErrorf("from, to, start_date, and end_date are required"))
+		return
+	}
+
+	asOf, ok := parseAsOf(c)
+	if !ok {
 		return
 	}
 
-	result, err := h.service.GetHistoricalRates(c.Request.Context(), from, to, startDate, endDate)
+	result, err := h.service.GetHistoricalRatesAsOf(c.Request.Context(), from, to, startDate, endDate, asOf)
 	if err != nil {
-		h.logger.Error("Failed to get historical rates", zap.Error(err))
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "fetch_failed",
-			Code:    400,
-			Message: err.Error(),
-		})
+		requestid.Logger(c.Request.Context(), h.logger).Error("Failed to get historical rates", zap.Error(err))
+		problem.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	render.Negotiated(c, http.StatusOK, result)
 }
 
 func (h *ExchangeHandler) GetSupportedCurrencies(c *gin.Context) {
-	currencies := make([]string, 0, len(domain.SupportedCurrencies))
-	for currency := range domain.SupportedCurrencies {
-		currencies = append(currencies, currency)
-	}
+	currencies := utils.EnabledCurrencies()
 
 	c.JSON(http.StatusOK, gin.H{
 		"currencies": currencies,
 		"count":      len(currencies),
 	})
 }
+
+// parseAsOf reads the optional as_of query parameter, writing a 400 and
+// returning false when it is not an RFC 3339 timestamp.
+func parseAsOf(c *gin.Context) (*time.Time, bool) {
+	value := c.Query("as_of")
+	if value == "" {
+		return nil, true
+	}
+
+	asOf, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		problem.Error(c, domain.ValidationErrorf("as_of must be an RFC 3339 timestamp"))
+		return nil, false
+	}
+	return &asOf, true
+}
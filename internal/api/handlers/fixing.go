@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type FixingHandler struct {
+	service *service.ExchangeService
+	logger  *zap.Logger
+}
+
+func NewFixingHandler(service *service.ExchangeService, logger *zap.Logger) *FixingHandler {
+	return &FixingHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *FixingHandler) Get(c *gin.Context) {
+	version := 0
+	if v := c.Query("version"); v != "" {
+		var err error
+		version, err = strconv.Atoi(v)
+		if err != nil || version < 1 {
+			problem.Error(c, domain.ValidationErrorf("version must be a positive integer"))
+			return
+		}
+	}
+
+	fixing, err := h.service.GetFixing(c.Param("date"), version)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fixing)
+}
+
+func (h *FixingHandler) History(c *gin.Context) {
+	versions, err := h.service.FixingHistory(c.Param("date"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"versions": versions,
+		"count":    len(versions),
+	})
+}
+
+func (h *FixingHandler) Correct(c *gin.Context) {
+	var req domain.FixingCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, domain.ValidationErrorf("%s", err))
+		return
+	}
+
+	fixing, err := h.service.CorrectFixing(c.Param("date"), &req, c.GetHeader(actorHeader))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, fixing)
+}
+
+func (h *FixingHandler) Capture(c *gin.Context) {
+	var req domain.FixingCaptureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, domain.ValidationErrorf("%s", err))
+		return
+	}
+
+	fixing, err := h.service.CaptureMissedFixing(c.Request.Context(), c.Param("date"), &req, c.GetHeader(actorHeader))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, fixing)
+}
+
+func (h *FixingHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(c, http.StatusNotFound, domain.CodeNotFound, "fixing not found")
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), h.logger).Warn("Fixing request failed", zap.Error(err))
+	problem.Error(c, err)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/graphqlapi"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type GraphQLHandler struct {
+	executor *graphqlapi.Executor
+	logger   *zap.Logger
+}
+
+func NewGraphQLHandler(service *service.ExchangeService, logger *zap.Logger, limits graphqlapi.Limits) *GraphQLHandler {
+	executor, err := graphqlapi.New(service, logger, limits)
+	if err != nil {
+		panic("graphql: invalid schema: " + err.Error())
+	}
+
+	return &GraphQLHandler{
+		executor: executor,
+		logger:   logger,
+	}
+}
+
+// Cost returns the rate-limit cost of the request's query. It reads the
+// body and puts it back for Query.
+func (h *GraphQLHandler) Cost(c *gin.Context) int {
+	body, err := c.GetRawData()
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 1
+	}
+
+	var req graphqlapi.Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return 1
+	}
+	return h.executor.Cost(req)
+}
+
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graphqlapi.Request
+	if err := c.ShouldBindJSON(&req); err != nil || req.Query == "" {
+		problem.Error(c, domain.ValidationErrorf("body must be a JSON object with a query"))
+		return
+	}
+
+	c.JSON(http.StatusOK, h.executor.Execute(c.Request.Context(), req))
+}
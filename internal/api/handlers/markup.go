@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MarkupHandler struct {
+	service *service.MarkupService
+}
+
+func NewMarkupHandler(service *service.MarkupService) *MarkupHandler {
+	return &MarkupHandler{service: service}
+}
+
+func (h *MarkupHandler) List(c *gin.Context) {
+	profiles := h.service.Profiles()
+
+	c.JSON(http.StatusOK, gin.H{
+		"profiles": profiles,
+		"default":  h.service.DefaultProfile(),
+		"count":    len(profiles),
+	})
+}
+
+func (h *MarkupHandler) Get(c *gin.Context) {
+	profile, err := h.service.Profile(c.Param("name"))
+	if err != nil {
+		problem.Write(c, http.StatusNotFound, domain.CodeNotFound, "markup profile not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
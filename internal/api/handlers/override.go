@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// actorHeader names the person making an override change for the audit
+// trail; the shared admin token alone cannot tell operators apart.
+const actorHeader = "X-Admin-User"
+
+type OverrideHandler struct {
+	service *service.OverrideService
+	logger  *zap.Logger
+}
+
+func NewOverrideHandler(service *service.OverrideService, logger *zap.Logger) *OverrideHandler {
+	return &OverrideHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *OverrideHandler) Create(c *gin.Context) {
+	var req domain.RateOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, domain.ValidationErrorf("%s", err))
+		return
+	}
+
+	override, err := h.service.Create(&req, c.GetHeader(actorHeader))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, override)
+}
+
+func (h *OverrideHandler) List(c *gin.Context) {
+	overrides, err := h.service.List(c.Query("pair"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"overrides": overrides,
+		"count":     len(overrides),
+	})
+}
+
+func (h *OverrideHandler) Get(c *gin.Context) {
+	override, err := h.service.Get(c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, override)
+}
+
+func (h *OverrideHandler) Update(c *gin.Context) {
+	var req domain.RateOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, domain.ValidationErrorf("%s", err))
+		return
+	}
+
+	override, err := h.service.Update(c.Param("id"), &req, c.GetHeader(actorHeader))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, override)
+}
+
+func (h *OverrideHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Param("id"), c.GetHeader(actorHeader), c.Query("reason")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *OverrideHandler) Audit(c *gin.Context) {
+	entries, err := h.service.Audit(c.Query("override_id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audit": entries,
+		"count": len(entries),
+	})
+}
+
+func (h *OverrideHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(c, http.StatusNotFound, domain.CodeNotFound, "rate override not found")
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), h.logger).Warn("Override request failed", zap.Error(err))
+	problem.Error(c, err)
+}
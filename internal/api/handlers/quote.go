@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type QuoteHandler struct {
+	service *service.QuoteService
+	logger  *zap.Logger
+}
+
+func NewQuoteHandler(service *service.QuoteService, logger *zap.Logger) *QuoteHandler {
+	return &QuoteHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *QuoteHandler) Create(c *gin.Context) {
+	var req domain.QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, domain.ValidationErrorf("%s", err))
+		return
+	}
+
+	quote, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, quote)
+}
+
+func (h *QuoteHandler) Get(c *gin.Context) {
+	quote, err := h.service.Get(c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func (h *QuoteHandler) Execute(c *gin.Context) {
+	quote, err := h.service.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func (h *QuoteHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(c, http.StatusNotFound, domain.CodeNotFound, "quote not found")
+		return
+	}
+
+	problem.Error(c, err)
+}
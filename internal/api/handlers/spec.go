@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"exchange-rate-service/internal/api/openapi"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+type SpecHandler struct {
+	spec []byte
+}
+
+func NewSpecHandler(doc *openapi3.T) *SpecHandler {
+	spec, err := openapi.JSON(doc)
+	if err != nil {
+		panic("openapi: cannot encode specification: " + err.Error())
+	}
+
+	return &SpecHandler{spec: spec}
+}
+
+func (h *SpecHandler) Spec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", h.spec)
+}
+
+func (h *SpecHandler) Docs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.SwaggerUI)
+}
+
+// Asset serves the bundled Swagger UI files.
+func (h *SpecHandler) Asset(c *gin.Context) {
+	name := strings.TrimPrefix(c.Param("filepath"), "/")
+	if !strings.HasPrefix(name, "swagger-ui") {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	c.FileFromFS(name, http.FS(openapi.SwaggerAssets))
+}
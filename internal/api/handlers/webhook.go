@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type WebhookHandler struct {
+	service *service.WebhookService
+	logger  *zap.Logger
+}
+
+func NewWebhookHandler(service *service.WebhookService, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var sub domain.WebhookSubscription
+	if err := c.ShouldBindJSON(&sub); err != nil {
+		problem.Error(c, domain.ValidationErrorf("%s", err))
+		return
+	}
+
+	result, err := h.service.Create(&sub)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, redactSecret(*result))
+}
+
+func (h *WebhookHandler) List(c *gin.Context) {
+	subs, err := h.service.List()
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	for i := range subs {
+		subs[i] = redactSecret(subs[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": subs,
+		"count":    len(subs),
+	})
+}
+
+func (h *WebhookHandler) Get(c *gin.Context) {
+	sub, err := h.service.Get(c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, redactSecret(*sub))
+}
+
+func (h *WebhookHandler) Update(c *gin.Context) {
+	var sub domain.WebhookSubscription
+	if err := c.ShouldBindJSON(&sub); err != nil {
+		problem.Error(c, domain.ValidationErrorf("%s", err))
+		return
+	}
+
+	result, err := h.service.Update(c.Param("id"), &sub)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, redactSecret(*result))
+}
+
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Param("id")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	letters, err := h.service.ListDeadLetters()
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dead_letters": letters,
+		"count":        len(letters),
+	})
+}
+
+func (h *WebhookHandler) ReplayDeadLetter(c *gin.Context) {
+	letter, err := h.service.ReplayDeadLetter(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			problem.Error(c, err)
+			return
+		}
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, letter)
+}
+
+func (h *WebhookHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		problem.Write(c, http.StatusNotFound, domain.CodeNotFound, "webhook subscription not found")
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), h.logger).Warn("Webhook request failed", zap.Error(err))
+	problem.Error(c, err)
+}
+
+func redactSecret(sub domain.WebhookSubscription) domain.WebhookSubscription {
+	sub.Secret = ""
+	return sub
+}
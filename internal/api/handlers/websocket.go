@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/lifecycle"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	wsWriteWait      = 10 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsPingPeriod     = (wsPongWait * 9) / 10
+	wsMaxMessageSize = 4096
+)
+
+type WebSocketHandler struct {
+	service     *service.ExchangeService
+	logger      *zap.Logger
+	upgrader    websocket.Upgrader
+	maxConns    int64
+	sendBuffer  int
+	activeConns atomic.Int64
+	conns       *lifecycle.Connections
+}
+
+// NewWebSocketHandler streams rates to clients whose origin checkOrigin
+// accepts. Streams are tracked in conns, when set, so shutdown closes them.
+func NewWebSocketHandler(service *service.ExchangeService, logger *zap.Logger, maxConns, sendBuffer int, checkOrigin func(r *http.Request) bool, conns *lifecycle.Connections) *WebSocketHandler {
+	if sendBuffer <= 0 {
+		sendBuffer = 64
+	}
+
+	return &WebSocketHandler{
+		service:    service,
+		logger:     logger,
+		maxConns:   int64(maxConns),
+		sendBuffer: sendBuffer,
+		conns:      conns,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     checkOrigin,
+		},
+	}
+}
+
+type wsClient struct {
+	conn   *websocket.Conn
+	send   chan domain.StreamMessage
+	done   chan struct{}
+	once   sync.Once
+	mu     sync.RWMutex
+	pairs  map[string]bool
+	logger *zap.Logger
+}
+
+func (h *WebSocketHandler) Stream(c *gin.Context) {
+	active := h.activeConns.Add(1)
+	defer h.activeConns.Add(-1)
+
+	if h.maxConns > 0 && active > h.maxConns {
+		problem.Error(c, domain.Errorf(domain.ErrOverloaded, "websocket connection limit reached"))
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		requestid.Logger(c.Request.Context(), h.logger).Warn("WebSocket upgrade failed", zap.Error(err))
+		return
+	}
+
+	client := &wsClient{
+		conn:   conn,
+		send:   make(chan domain.StreamMessage, h.sendBuffer),
+		done:   make(chan struct{}),
+		pairs:  make(map[string]bool),
+		logger: requestid.Logger(c.Request.Context(), h.logger),
+	}
+
+	if h.conns != nil {
+		done, ok := h.conns.Track(func() { client.close(websocket.CloseGoingAway, "server shutting down") })
+		defer done()
+		if !ok {
+			client.close(websocket.CloseGoingAway, "server shutting down")
+			return
+		}
+	}
+
+	updates, unsubscribe := h.service.SubscribeUpdates(h.sendBuffer)
+	defer unsubscribe()
+
+	go client.writeLoop()
+	go h.forwardUpdates(client, updates)
+
+	h.readLoop(c.Request.Context(), client)
+	client.close(websocket.CloseNormalClosure, "")
+}
+
+func (h *WebSocketHandler) ActiveConnections() int {
+	return int(h.activeConns.Load())
+}
+
+func (h *WebSocketHandler) readLoop(ctx context.Context, client *wsClient) {
+	client.conn.SetReadLimit(wsMaxMessageSize)
+	client.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	client.conn.SetPongHandler(func(string) error {
+		return client.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := client.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req domain.StreamRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			client.enqueue(errorMessage("invalid_message", "message must be valid JSON"))
+			continue
+		}
+
+		h.handleRequest(ctx, client, req)
+	}
+}
+
+func (h *WebSocketHandler) handleRequest(ctx context.Context, client *wsClient, req domain.StreamRequest) {
+	switch req.Action {
+	case "subscribe":
+		pairs, err := normalizePairs(req.Pairs)
+		if err != nil {
+			client.enqueue(errorMessage("invalid_pair", err.Error()))
+			return
+		}
+		client.mu.Lock()
+		for _, pair := range pairs {
+			client.pairs[pair] = true
+		}
+		client.mu.Unlock()
+		client.enqueue(domain.StreamMessage{Type: "subscribed", Pairs: pairs, Timestamp: time.Now()})
+
+	case "unsubscribe":
+		pairs, err := normalizePairs(req.Pairs)
+		if err != nil {
+			client.enqueue(errorMessage("invalid_pair", err.Error()))
+			return
+		}
+		client.mu.Lock()
+		for _, pair := range pairs {
+			delete(client.pairs, pair)
+		}
+		client.mu.Unlock()
+		client.enqueue(domain.StreamMessage{Type: "unsubscribed", Pairs: pairs, Timestamp: time.Now()})
+
+	case "snapshot":
+		pairs := req.Pairs
+		if len(pairs) == 0 {
+			pairs = client.subscribedPairs()
+		}
+		pairs, err := normalizePairs(pairs)
+		if err != nil {
+			client.enqueue(errorMessage("invalid_pair", err.Error()))
+			return
+		}
+		rates, err := h.snapshot(ctx, pairs)
+		if err != nil {
+			client.enqueue(errorMessage("snapshot_failed", err.Error()))
+			return
+		}
+		client.enqueue(domain.StreamMessage{Type: "snapshot", Rates: rates, Timestamp: time.Now()})
+
+	default:
+		client.enqueue(errorMessage("unknown_action", "action must be one of subscribe, unsubscribe, snapshot"))
+	}
+}
+
+func (h *WebSocketHandler) snapshot(ctx context.Context, pairs []string) ([]domain.PairRate, error) {
+	latest := make(map[string]map[string]float64)
+	rates := make([]domain.PairRate, 0, len(pairs))
+
+	for _, pair := range pairs {
+		from, to, _ := utils.ParsePair(pair)
+		if _, exists := latest[from]; !exists {
+			result, err := h.service.GetLatestRates(ctx, from)
+			if err != nil {
+				return nil, err
+			}
+			latest[from] = result.Rates
+		}
+
+		rate, exists := latest[from][to]
+		if !exists {
+			continue
+		}
+		rates = append(rates, domain.PairRate{Pair: pair, FromCurrency: from, ToCurrency: to, Rate: rate})
+	}
+
+	return rates, nil
+}
+
+func (h *WebSocketHandler) forwardUpdates(client *wsClient, updates <-chan domain.RateUpdate) {
+	for {
+		select {
+		case <-client.done:
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+
+			var rates []domain.PairRate
+			client.mu.RLock()
+			for to, rate := range update.Changed {
+				pair := update.BaseCurrency + to
+				if client.pairs[pair] {
+					rates = append(rates, domain.PairRate{Pair: pair, FromCurrency: update.BaseCurrency, ToCurrency: to, Rate: rate})
+				}
+			}
+			client.mu.RUnlock()
+
+			if len(rates) == 0 {
+				continue
+			}
+
+			if !client.enqueue(domain.StreamMessage{Type: "update", Rates: rates, Timestamp: update.Timestamp}) {
+				client.logger.Warn("Closing slow WebSocket consumer")
+				client.close(websocket.ClosePolicyViolation, "slow consumer")
+				return
+			}
+		}
+	}
+}
+
+// enqueue queues a message without blocking. It reports false when the
+// client's send buffer is full.
+func (c *wsClient) enqueue(msg domain.StreamMessage) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *wsClient) subscribedPairs() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	pairs := make([]string, 0, len(c.pairs))
+	for pair := range c.pairs {
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+func (c *wsClient) writeLoop() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		}
+	}
+}
+
+func (c *wsClient) close(code int, reason string) {
+	c.once.Do(func() {
+		close(c.done)
+		if code != websocket.CloseAbnormalClosure {
+			c.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(code, reason),
+				time.Now().Add(wsWriteWait))
+		}
+		c.conn.Close()
+	})
+}
+
+func normalizePairs(pairs []string) ([]string, error) {
+	normalized := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		from, to, err := utils.ParsePair(pair)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, from+to)
+	}
+	return normalized, nil
+}
+
+func errorMessage(code, message string) domain.StreamMessage {
+	return domain.StreamMessage{
+		Type:      "error",
+		Error:     code,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const apiKeyContextKey = "api_key"
+
+func APIKeyAuth(keys *service.APIKeyService, cost CostFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, err := keys.Authenticate(extractAPIKey(c.Request))
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="exchange-rate-service"`)
+			problem.Write(c, http.StatusUnauthorized, domain.CodeUnauthorized, "a valid API key is required in the X-API-Key header or as a bearer token")
+			return
+		}
+
+		result, err := keys.Allow(key, cost(c))
+		writeRateLimitHeaders(c, result)
+		if err != nil {
+			abortRateLimited(c, result, err)
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		if key.MarkupProfile != "" {
+			c.Request = c.Request.WithContext(service.WithMarkupProfile(c.Request.Context(), key.MarkupProfile))
+		}
+		c.Next()
+	}
+}
+
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader("X-Admin-Token")
+		if provided == "" {
+			provided = bearerToken(c.Request)
+		}
+
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			problem.Write(c, http.StatusUnauthorized, domain.CodeUnauthorized, "a valid admin token is required")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func APIKeyFromContext(c *gin.Context) (*domain.APIKey, bool) {
+	value, exists := c.Get(apiKeyContextKey)
+	if !exists {
+		return nil, false
+	}
+	key, ok := value.(*domain.APIKey)
+	return key, ok
+}
+
+func extractAPIKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	return bearerToken(r)
+}
+
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
@@ -1,14 +1,32 @@
 package middleware
 
 import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 )
 
-func CORS() gin.HandlerFunc {
+// CORS answers cross-origin requests from the allowed origins, such as
+// https://app.example.com. "*" allows any origin; with no origins listed,
+// browsers only get same-origin access.
+func CORS(allowed []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		origin := c.GetHeader("Origin")
+		c.Header("Vary", "Origin")
+
+		if origin != "" && originListed(allowed, origin) {
+			if slices.Contains(allowed, "*") {
+				c.Header("Access-Control-Allow-Origin", "*")
+			} else {
+				c.Header("Access-Control-Allow-Origin", origin)
+			}
+			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, X-Request-ID, traceparent, tracestate")
+			c.Header("Access-Control-Expose-Headers", "X-Request-ID, Retry-After, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset")
+		}
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -18,3 +36,28 @@ func CORS() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// OriginAllowed returns a check, for the WebSocket upgrader, that accepts
+// requests without an Origin header, which browsers always send, same-origin
+// requests and requests from the origins CORS allows.
+func OriginAllowed(allowed []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		return originListed(allowed, origin)
+	}
+}
+
+func originListed(allowed []string, origin string) bool {
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
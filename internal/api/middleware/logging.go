@@ -3,6 +3,8 @@ package middleware
 import (
 	"time"
 
+	"exchange-rate-service/internal/requestid"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -18,7 +20,7 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 		method := c.Request.Method
 		path := c.Request.URL.Path
 
-		logger.Info("HTTP Request",
+		requestid.Logger(c.Request.Context(), logger).Info("HTTP Request",
 			zap.String("method", method),
 			zap.String("path", path),
 			zap.Int("status", statusCode),
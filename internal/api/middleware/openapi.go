@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIValidator rejects requests whose parameters or body do not match
+// the operation declared in doc. Requests to paths the document does not
+// describe pass through untouched. Authentication is enforced by its own
+// middleware, so security requirements are accepted as-is here.
+func OpenAPIValidator(doc *openapi3.T) gin.HandlerFunc {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		panic("openapi: cannot build router: " + err.Error())
+	}
+
+	options := &openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			problem.Error(c, domain.ValidationErrorf("%s", validationMessage(err)))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func validationMessage(err error) string {
+	switch e := err.(type) {
+	case *openapi3filter.RequestError:
+		if e.Parameter != nil {
+			return "invalid parameter " + e.Parameter.Name + ": " + reason(e)
+		}
+		if e.RequestBody != nil {
+			return "invalid request body: " + reason(e)
+		}
+		return e.Error()
+	default:
+		return err.Error()
+	}
+}
+
+func reason(e *openapi3filter.RequestError) string {
+	if e.Err != nil {
+		if schemaErr, ok := e.Err.(*openapi3.SchemaError); ok {
+			return schemaErr.Reason
+		}
+		return e.Err.Error()
+	}
+	return e.Reason
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CostFunc returns how many tokens a request consumes.
+type CostFunc func(c *gin.Context) int
+
+// RouteCost weighs requests by their matched route, see service.RouteCost.
+func RouteCost(weights map[string]int) CostFunc {
+	return func(c *gin.Context) int {
+		return service.RouteCost(weights, c.FullPath(), c.Query("start_date"), c.Query("end_date"))
+	}
+}
+
+// RateLimit applies a token bucket per client IP. Requests already
+// identified by an API key are limited by APIKeyAuth and pass through.
+func RateLimit(limiter *service.RateLimiter, limit int, window time.Duration, cost CostFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := APIKeyFromContext(c); ok {
+			c.Next()
+			return
+		}
+
+		result := limiter.Take("ip_"+c.ClientIP(), cost(c), limit, window)
+		writeRateLimitHeaders(c, result)
+
+		if !result.Allowed {
+			abortRateLimited(c, result, domain.ErrRateLimited)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func writeRateLimitHeaders(c *gin.Context, result domain.RateLimitResult) {
+	if result.Limit <= 0 {
+		return
+	}
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+}
+
+func abortRateLimited(c *gin.Context, result domain.RateLimitResult, err error) {
+	c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+	problem.Error(c, err)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
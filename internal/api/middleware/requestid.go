@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"exchange-rate-service/internal/requestid"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequestID tags every request with an ID, reusing a well-formed
+// X-Request-ID from the client, and echoes it on the response so it can be
+// quoted in support tickets. Handlers further down the chain log through the
+// child logger it stores, so every line for the request carries request_id.
+func RequestID(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Header(requestid.Header, id)
+		c.Request = c.Request.WithContext(requestid.Scope(c.Request.Context(), id, logger))
+
+		c.Next()
+	}
+}
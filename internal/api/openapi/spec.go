@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"io/fs"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SwaggerUIVersion is the Swagger UI release /docs renders the document
+// with.
+const SwaggerUIVersion = "5.17.14"
+
+//go:generate sh -c "curl -fsSL -o swaggerui/swagger-ui.css https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui.css && curl -fsSL -o swaggerui/swagger-ui-bundle.js https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui-bundle.js"
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+//go:embed swagger.html
+var swaggerPage []byte
+
+//go:embed swaggerui
+var swaggerFiles embed.FS
+
+// SwaggerAssets holds the Swagger UI files served under /docs/assets.
+var SwaggerAssets, _ = fs.Sub(swaggerFiles, "swaggerui")
+
+// SwaggerUI is the /docs page. It loads Swagger UI from SwaggerAssets when
+// the release is bundled, and from unpkg otherwise.
+var SwaggerUI = bytes.ReplaceAll(swaggerPage, []byte("{{ASSETS}}"), []byte(swaggerAssetBase()))
+
+// SwaggerAssetsBundled reports whether the Swagger UI files are embedded in
+// the binary.
+func SwaggerAssetsBundled() bool {
+	for _, name := range []string{"swagger-ui.css", "swagger-ui-bundle.js"} {
+		if _, err := fs.Stat(SwaggerAssets, name); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func swaggerAssetBase() string {
+	if SwaggerAssetsBundled() {
+		return "/docs/assets"
+	}
+	return "https://unpkg.com/swagger-ui-dist@" + SwaggerUIVersion
+}
+
+// Load parses and validates the embedded OpenAPI document.
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(specYAML)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func MustLoad() *openapi3.T {
+	doc, err := Load()
+	if err != nil {
+		panic("openapi: invalid embedded specification: " + err.Error())
+	}
+	return doc
+}
+
+func JSON(doc *openapi3.T) ([]byte, error) {
+	return json.Marshal(doc)
+}
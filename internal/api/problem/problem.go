@@ -0,0 +1,122 @@
+package problem
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// ContentType is the RFC 7807 media type. Clients that list it in Accept get
+// problem documents instead of the default error body.
+const ContentType = "application/problem+json"
+
+const typePrefix = "urn:exchange-rate-service:problem:"
+
+// Details is an RFC 7807 problem document; Code and RequestID are extension
+// members.
+type Details struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Status maps a domain error onto its HTTP status.
+func Status(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidAPIKey):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrExpired):
+		return http.StatusGone
+	case errors.Is(err, domain.ErrRateLimited), errors.Is(err, domain.ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	case errors.Is(err, domain.ErrUpstreamUnavailable), errors.Is(err, domain.ErrStaleData), errors.Is(err, domain.ErrOverloaded):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Message returns the client-facing text for err. Server-side failures are
+// described generically so upstream details never leak to clients.
+func Message(err error) string {
+	if Status(err) < http.StatusInternalServerError {
+		return err.Error()
+	}
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrUpstreamUnavailable):
+		return domain.ErrUpstreamUnavailable.Error()
+	case errors.Is(err, domain.ErrStaleData):
+		return domain.ErrStaleData.Error()
+	case errors.Is(err, context.DeadlineExceeded):
+		return "request timed out"
+	default:
+		return "internal server error"
+	}
+}
+
+// Response builds the error body for err, e.g. for per-item batch results.
+func Response(ctx context.Context, err error) *domain.ErrorResponse {
+	return &domain.ErrorResponse{
+		Error:     domain.ErrorCode(err),
+		Status:    Status(err),
+		Message:   Message(err),
+		RequestID: requestid.FromContext(ctx),
+	}
+}
+
+// Error writes err with the status and code of its domain kind and aborts
+// the handler chain.
+func Error(c *gin.Context, err error) {
+	Write(c, Status(err), domain.ErrorCode(err), Message(err))
+}
+
+// Write writes an error response with an explicit status and code and aborts
+// the handler chain.
+func Write(c *gin.Context, status int, code, message string) {
+	requestID := requestid.FromContext(c.Request.Context())
+
+	if c.NegotiateFormat(binding.MIMEJSON, ContentType) == ContentType {
+		c.Abort()
+		c.Render(status, problemRender{Details{
+			Type:      typePrefix + code,
+			Title:     http.StatusText(status),
+			Status:    status,
+			Detail:    message,
+			Instance:  c.Request.URL.Path,
+			Code:      code,
+			RequestID: requestID,
+		}})
+		return
+	}
+
+	c.AbortWithStatusJSON(status, domain.ErrorResponse{
+		Error:     code,
+		Status:    status,
+		Message:   message,
+		RequestID: requestID,
+	})
+}
@@ -0,0 +1,19 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type problemRender struct {
+	details Details
+}
+
+func (r problemRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return json.NewEncoder(w).Encode(r.details)
+}
+
+func (r problemRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ContentType)
+}
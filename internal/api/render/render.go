@@ -0,0 +1,129 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXML  = "xml"
+
+	MIMECSV = "text/csv"
+)
+
+// Format picks the response format for a request. The format query
+// parameter takes precedence over the Accept header; JSON is the default.
+// ok is false when format names an unsupported format.
+func Format(c *gin.Context) (format string, ok bool) {
+	if format := strings.ToLower(c.Query("format")); format != "" {
+		switch format {
+		case FormatJSON, FormatCSV, FormatXML:
+			return format, true
+		}
+		return "", false
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, MIMECSV, binding.MIMEXML, binding.MIMEXML2) {
+	case MIMECSV:
+		return FormatCSV, true
+	case binding.MIMEXML, binding.MIMEXML2:
+		return FormatXML, true
+	}
+	return FormatJSON, true
+}
+
+// Negotiated writes v in the format the client asked for. Values without a
+// tabular form are always written as JSON.
+func Negotiated(c *gin.Context, status int, v interface{}) {
+	c.Header("Vary", "Accept")
+
+	format, ok := Format(c)
+	if !ok {
+		problem.Error(c, domain.ValidationErrorf("format must be one of json, csv, xml"))
+		return
+	}
+
+	table, tabular := TableOf(v)
+	if format == FormatJSON || !tabular {
+		c.JSON(status, v)
+		return
+	}
+
+	var buf bytes.Buffer
+	var err error
+	var contentType string
+	switch format {
+	case FormatCSV:
+		contentType = MIMECSV + "; charset=utf-8"
+		err = writeCSV(&buf, table)
+	case FormatXML:
+		contentType = binding.MIMEXML + "; charset=utf-8"
+		err = writeXML(&buf, table)
+	}
+
+	if err != nil {
+		problem.Error(c, fmt.Errorf("encoding %s response: %w", format, err))
+		return
+	}
+
+	c.Data(status, contentType, buf.Bytes())
+}
+
+func writeCSV(buf *bytes.Buffer, table *Table) error {
+	w := csv.NewWriter(buf)
+	if err := w.Write(table.Columns); err != nil {
+		return err
+	}
+	if err := w.WriteAll(table.Rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+func writeXML(buf *bytes.Buffer, table *Table) error {
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+
+	root := xml.StartElement{Name: xml.Name{Local: table.Name}}
+	if err := enc.EncodeToken(root); err != nil {
+		return err
+	}
+
+	for _, row := range table.Rows {
+		element := xml.StartElement{Name: xml.Name{Local: table.Row}}
+		if err := enc.EncodeToken(element); err != nil {
+			return err
+		}
+		for i, column := range table.Columns {
+			if err := enc.EncodeElement(row[i], xml.StartElement{Name: xml.Name{Local: column}}); err != nil {
+				return err
+			}
+		}
+		if err := enc.EncodeToken(element.End()); err != nil {
+			return err
+		}
+	}
+
+	if err := enc.EncodeToken(root.End()); err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+
+	buf.WriteString("\n")
+	return nil
+}
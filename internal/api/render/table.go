@@ -0,0 +1,123 @@
+package render
+
+import (
+	"sort"
+	"strconv"
+
+	"exchange-rate-service/internal/domain"
+)
+
+// Table is the flat form of a response used by the CSV and XML encoders.
+// Name and Row are the XML root and per-row element names; Columns doubles
+// as the CSV header row and the XML field element names.
+type Table struct {
+	Name    string
+	Row     string
+	Columns []string
+	Rows    [][]string
+}
+
+// TableOf flattens responses that have a tabular form. Rows are ordered so
+// that repeated requests produce identical output.
+func TableOf(v interface{}) (*Table, bool) {
+	switch v := v.(type) {
+	case *domain.LatestRatesResponse:
+		return latestTable(v), true
+	case *domain.HistoricalRatesResponse:
+		return historicalTable(v), true
+	case *domain.BatchConversionResponse:
+		return batchTable(v), true
+	}
+	return nil, false
+}
+
+// latestTable always has bid, ask and source columns, so a client sees the
+// same columns whether or not the response is priced or overridden; they
+// are empty when they do not apply.
+func latestTable(resp *domain.LatestRatesResponse) *Table {
+	currencies := make([]string, 0, len(resp.Rates))
+	for currency := range resp.Rates {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	table := &Table{
+		Name:    "latest_rates",
+		Row:     "rate",
+		Columns: []string{"base_currency", "currency", "rate", "date", "bid", "ask", "source"},
+	}
+	for _, currency := range currencies {
+		table.Rows = append(table.Rows, []string{
+			resp.BaseCurrency,
+			currency,
+			decimal(resp.Rates[currency]),
+			resp.Date,
+			optionalDecimal(resp.Bid, currency),
+			optionalDecimal(resp.Ask, currency),
+			resp.Sources[currency],
+		})
+	}
+	return table
+}
+
+func historicalTable(resp *domain.HistoricalRatesResponse) *Table {
+	dates := make([]string, 0, len(resp.Rates))
+	for date := range resp.Rates {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	table := &Table{
+		Name:    "historical_rates",
+		Row:     "rate",
+		Columns: []string{"date", "from_currency", "to_currency", "rate", "source"},
+	}
+	for _, date := range dates {
+		rate := resp.Rates[date]
+		table.Rows = append(table.Rows, []string{date, resp.FromCurrency, resp.ToCurrency, decimal(rate.Rate), rate.Source})
+	}
+	return table
+}
+
+// batchTable has the pricing of each conversion in bid_rate, fee and
+// profile, empty when it is not priced with a markup profile. rate is
+// always the mid rate.
+func batchTable(resp *domain.BatchConversionResponse) *Table {
+	table := &Table{
+		Name:    "conversions",
+		Row:     "conversion",
+		Columns: []string{"from_currency", "to_currency", "amount", "rate", "converted_amount", "date", "bid_rate", "fee", "profile", "error"},
+	}
+	for _, result := range resp.Results {
+		row := []string{result.Request.From, result.Request.To, decimal(result.Request.Amount), "", "", result.Request.Date, "", "", "", ""}
+		if result.Result != nil {
+			row[3] = decimal(result.Result.Rate)
+			row[4] = decimal(result.Result.Amount)
+			row[5] = result.Result.Date
+			if p := result.Result.Pricing; p != nil {
+				row[6] = decimal(p.BidRate)
+				row[7] = decimal(p.Fee)
+				row[8] = p.Profile
+			}
+		}
+		if result.Error != nil {
+			row[9] = result.Error.Message
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// optionalDecimal renders values[key], or "" when it is missing.
+func optionalDecimal(values map[string]float64, key string) string {
+	if v, ok := values[key]; ok {
+		return decimal(v)
+	}
+	return ""
+}
+
+// decimal renders a number in plain decimal notation, never with an
+// exponent, so spreadsheets and ERPs parse it as-is.
+func decimal(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
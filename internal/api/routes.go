@@ -1,35 +1,321 @@
 package api
 
 import (
+	"time"
+
 	"exchange-rate-service/internal/api/handlers"
 	"exchange-rate-service/internal/api/middleware"
+	"exchange-rate-service/internal/api/openapi"
+	"exchange-rate-service/internal/graphqlapi"
+	"exchange-rate-service/internal/lifecycle"
 	"exchange-rate-service/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-func NewRouter(exchangeService *service.ExchangeService, logger *zap.Logger) *gin.Engine {
+type routerOptions struct {
+	wsMaxConns     int
+	wsSendBuffer   int
+	webhookService *service.WebhookService
+	apiKeyService  *service.APIKeyService
+	requireAPIKey  bool
+	adminToken     string
+	rateLimiter    *service.RateLimiter
+	rateLimit      int
+	rateWindow     time.Duration
+	routeCosts     map[string]int
+	graphqlLimits  graphqlapi.Limits
+	cacheAdmin     *service.CacheAdminService
+	overrides      *service.OverrideService
+	quotes         *service.QuoteService
+	markups        *service.MarkupService
+	rateGuard      *service.RateGuard
+	demand         *service.DemandTracker
+	corsOrigins    []string
+	connections    *lifecycle.Connections
+}
+
+type Option func(*routerOptions)
+
+func WithWebSocketLimits(maxConns, sendBuffer int) Option {
+	return func(o *routerOptions) {
+		o.wsMaxConns = maxConns
+		o.wsSendBuffer = sendBuffer
+	}
+}
+
+// WithCORSOrigins lets browsers on origins call the API and open WebSocket
+// streams. "*" allows any origin.
+func WithCORSOrigins(origins []string) Option {
+	return func(o *routerOptions) {
+		o.corsOrigins = origins
+	}
+}
+
+// WithConnections tracks WebSocket streams in connections, so shutdown
+// closes them.
+func WithConnections(connections *lifecycle.Connections) Option {
+	return func(o *routerOptions) {
+		o.connections = connections
+	}
+}
+
+// WithWebhooks exposes webhook management under /admin/webhooks.
+func WithWebhooks(webhookService *service.WebhookService) Option {
+	return func(o *routerOptions) {
+		o.webhookService = webhookService
+	}
+}
+
+// WithAPIKeys exposes key management under /admin/keys. When required is
+// true every /api/v1 route needs a valid key and is subject to that key's
+// rate limit and daily quota.
+func WithAPIKeys(apiKeyService *service.APIKeyService, required bool) Option {
+	return func(o *routerOptions) {
+		o.apiKeyService = apiKeyService
+		o.requireAPIKey = required
+	}
+}
+
+// WithAdminToken enables the /admin route group, guarded by token.
+func WithAdminToken(token string) Option {
+	return func(o *routerOptions) {
+		o.adminToken = token
+	}
+}
+
+// WithRateLimit limits anonymous clients to limit tokens per window per IP.
+// routeCosts weighs routes by their full path; unlisted routes cost one
+// token. The same weights apply to API key rate limits.
+func WithRateLimit(limiter *service.RateLimiter, limit int, window time.Duration, routeCosts map[string]int) Option {
+	return func(o *routerOptions) {
+		o.rateLimiter = limiter
+		o.rateLimit = limit
+		o.rateWindow = window
+		o.routeCosts = routeCosts
+	}
+}
+
+// WithCacheAdmin exposes cache inspection, invalidation and forced refreshes
+// under /admin/cache.
+func WithCacheAdmin(cacheAdmin *service.CacheAdminService) Option {
+	return func(o *routerOptions) {
+		o.cacheAdmin = cacheAdmin
+	}
+}
+
+// WithOverrides exposes rate override management under /admin/overrides.
+func WithOverrides(overrides *service.OverrideService) Option {
+	return func(o *routerOptions) {
+		o.overrides = overrides
+	}
+}
+
+// WithQuotes enables rate quotes under /api/v1/quotes.
+func WithQuotes(quotes *service.QuoteService) Option {
+	return func(o *routerOptions) {
+		o.quotes = quotes
+	}
+}
+
+// WithMarkups exposes the configured markup profiles under /admin/markups.
+func WithMarkups(markups *service.MarkupService) Option {
+	return func(o *routerOptions) {
+		o.markups = markups
+	}
+}
+
+// WithRateGuard exposes rejected rates for review under /admin/anomalies.
+func WithRateGuard(guard *service.RateGuard) Option {
+	return func(o *routerOptions) {
+		o.rateGuard = guard
+	}
+}
+
+// WithDemand exposes the hot set and the refresh schedule under
+// /admin/demand.
+func WithDemand(demand *service.DemandTracker) Option {
+	return func(o *routerOptions) {
+		o.demand = demand
+	}
+}
+
+// WithGraphQLLimits bounds the selection depth and complexity of /graphql
+// queries.
+func WithGraphQLLimits(maxDepth, maxComplexity int) Option {
+	return func(o *routerOptions) {
+		o.graphqlLimits = graphqlapi.Limits{MaxDepth: maxDepth, MaxComplexity: maxComplexity}
+	}
+}
+
+func NewRouter(exchangeService *service.ExchangeService, logger *zap.Logger, opts ...Option) *gin.Engine {
+	options := &routerOptions{
+		wsMaxConns:    1000,
+		wsSendBuffer:  64,
+		graphqlLimits: graphqlapi.Limits{MaxDepth: 8, MaxComplexity: 200},
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	router := gin.New()
 
+	router.Use(middleware.RequestID(logger))
+	router.Use(middleware.Tracing())
 	router.Use(middleware.Logger(logger))
-	router.Use(middleware.CORS())
+	router.Use(middleware.CORS(options.corsOrigins))
 	router.Use(gin.Recovery())
 
 	exchangeHandler := handlers.NewExchangeHandler(exchangeService, logger)
 	healthHandler := handlers.NewHealthHandler()
+	wsHandler := handlers.NewWebSocketHandler(exchangeService, logger, options.wsMaxConns, options.wsSendBuffer,
+		middleware.OriginAllowed(options.corsOrigins), options.connections)
+	graphqlHandler := handlers.NewGraphQLHandler(exchangeService, logger, options.graphqlLimits)
+	fixingHandler := handlers.NewFixingHandler(exchangeService, logger)
+
+	spec := openapi.MustLoad()
+	specHandler := handlers.NewSpecHandler(spec)
 
 	router.GET("/health", healthHandler.Health)
+	router.GET("/openapi.json", specHandler.Spec)
+	router.GET("/docs", specHandler.Docs)
+	router.GET("/docs/assets/*filepath", specHandler.Asset)
 
-	v1 := router.Group("/api/v1")
+	// authMiddleware authenticates API keys and rate limits, charging each
+	// request what cost says.
+	authMiddleware := func(cost middleware.CostFunc) []gin.HandlerFunc {
+		var chain []gin.HandlerFunc
+		if options.apiKeyService != nil && options.requireAPIKey {
+			chain = append(chain, middleware.APIKeyAuth(options.apiKeyService, cost))
+		}
+		if options.rateLimiter != nil {
+			chain = append(chain, middleware.RateLimit(options.rateLimiter, options.rateLimit, options.rateWindow, cost))
+		}
+		return chain
+	}
+
+	router.POST("/graphql", append(authMiddleware(graphqlHandler.Cost), graphqlHandler.Query)...)
+
+	v1Middleware := append(authMiddleware(middleware.RouteCost(options.routeCosts)), middleware.OpenAPIValidator(spec))
+
+	v1 := router.Group("/api/v1", v1Middleware...)
 	{
 		v1.GET("/convert", exchangeHandler.Convert)
+		v1.POST("/convert/batch", exchangeHandler.BatchConvert)
 		v1.GET("/latest", exchangeHandler.GetLatestRates)
 		v1.GET("/historical", exchangeHandler.GetHistoricalRates)
 		v1.GET("/currencies", exchangeHandler.GetSupportedCurrencies)
+		v1.GET("/fixings/:date", fixingHandler.Get)
+		v1.GET("/ws", wsHandler.Stream)
+	}
+
+	if options.quotes != nil {
+		quoteHandler := handlers.NewQuoteHandler(options.quotes, logger)
+
+		quotes := v1.Group("/quotes")
+		{
+			quotes.POST("", quoteHandler.Create)
+			quotes.GET("/:id", quoteHandler.Get)
+			quotes.POST("/:id/execute", quoteHandler.Execute)
+		}
+	}
+
+	if options.adminToken != "" {
+		admin := router.Group("/admin", middleware.AdminAuth(options.adminToken))
+
+		if options.apiKeyService != nil {
+			apiKeyHandler := handlers.NewAPIKeyHandler(options.apiKeyService, logger)
+
+			keys := admin.Group("/keys")
+			{
+				keys.POST("", apiKeyHandler.Create)
+				keys.GET("", apiKeyHandler.List)
+				keys.GET("/:id", apiKeyHandler.Get)
+				keys.PUT("/:id", apiKeyHandler.Update)
+				keys.DELETE("/:id", apiKeyHandler.Revoke)
+				keys.GET("/:id/usage", apiKeyHandler.Usage)
+			}
+		}
+
+		if options.webhookService != nil {
+			webhookHandler := handlers.NewWebhookHandler(options.webhookService, logger)
+
+			webhooks := admin.Group("/webhooks")
+			{
+				webhooks.POST("", webhookHandler.Create)
+				webhooks.GET("", webhookHandler.List)
+				webhooks.GET("/dead-letters", webhookHandler.ListDeadLetters)
+				webhooks.POST("/dead-letters/:id/replay", webhookHandler.ReplayDeadLetter)
+				webhooks.GET("/:id", webhookHandler.Get)
+				webhooks.PUT("/:id", webhookHandler.Update)
+				webhooks.DELETE("/:id", webhookHandler.Delete)
+			}
+		}
+
+		if options.cacheAdmin != nil {
+			cacheHandler := handlers.NewCacheHandler(options.cacheAdmin, logger)
+
+			cache := admin.Group("/cache")
+			{
+				cache.GET("", cacheHandler.List)
+				cache.DELETE("", cacheHandler.DeletePrefix)
+				cache.POST("/refresh", cacheHandler.Refresh)
+				cache.GET("/:key", cacheHandler.Get)
+				cache.DELETE("/:key", cacheHandler.Delete)
+			}
+		}
+
+		if options.overrides != nil {
+			overrideHandler := handlers.NewOverrideHandler(options.overrides, logger)
+
+			overrides := admin.Group("/overrides")
+			{
+				overrides.POST("", overrideHandler.Create)
+				overrides.GET("", overrideHandler.List)
+				overrides.GET("/audit", overrideHandler.Audit)
+				overrides.GET("/:id", overrideHandler.Get)
+				overrides.PUT("/:id", overrideHandler.Update)
+				overrides.DELETE("/:id", overrideHandler.Delete)
+			}
+		}
+
+		if options.markups != nil {
+			markupHandler := handlers.NewMarkupHandler(options.markups)
+
+			markups := admin.Group("/markups")
+			{
+				markups.GET("", markupHandler.List)
+				markups.GET("/:name", markupHandler.Get)
+			}
+		}
+
+		if exchangeService.FixingsEnabled() {
+			fixings := admin.Group("/fixings")
+			{
+				fixings.GET("/:date/history", fixingHandler.History)
+				fixings.POST("/:date/capture", fixingHandler.Capture)
+				fixings.POST("/:date/corrections", fixingHandler.Correct)
+			}
+		}
+
+		if options.rateGuard != nil {
+			anomalyHandler := handlers.NewAnomalyHandler(options.rateGuard, logger)
+
+			anomalies := admin.Group("/anomalies")
+			{
+				anomalies.GET("", anomalyHandler.List)
+				anomalies.POST("/:id/review", anomalyHandler.Review)
+			}
+		}
+
+		if options.demand != nil {
+			demandHandler := handlers.NewDemandHandler(options.demand, exchangeService)
+			admin.GET("/demand", demandHandler.Get)
+		}
 	}
 
-	router.GET("/convert", exchangeHandler.Convert)
+	router.GET("/convert", append(v1Middleware, exchangeHandler.Convert)...)
 
 	return router
 }
@@ -1,50 +1,233 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
-	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
+// Config is the service configuration. Each setting is named by its env tag:
+// the environment variable is the tag itself, the config file key is the
+// tag in lower case and the command-line flag is the file key with dashes,
+// so RATE_LIMIT is rate_limit in a file and --rate-limit on the command
+// line.
 type Config struct {
-	Port            string
-	APIKey          string
-	BaseURL         string
-	LogLevel        string
-	CacheExpiration int
-	UpdateInterval  int
-	MaxHistoryDays  int
-}
+	// Path is the config file the settings were read from, if any.
+	Path string `env:"-"`
 
-func Load() (*Config, error) {
-	godotenv.Load()
+	Port            string `env:"PORT"`
+	GRPCPort        string `env:"GRPC_PORT"`
+	APIKey          string `env:"EXCHANGE_API_KEY"`
+	BaseURL         string `env:"EXCHANGE_BASE_URL"`
+	RateProvider    string `env:"RATE_PROVIDER"`
+	ECBBaseURL      string `env:"ECB_BASE_URL"`
+	RateSnapshotDir string `env:"RATE_SNAPSHOT_DIR"`
+	FallbackMaxAge  int    `env:"FALLBACK_MAX_AGE"`
+	SnapshotPoll    int    `env:"RATE_SNAPSHOT_POLL"`
 
-	config := &Config{
-		Port:            getEnv("PORT", "8080"),
-		APIKey:          getEnv("EXCHANGE_API_KEY", "73fda159574e487335c01f410f990937"),
-		BaseURL:         getEnv("EXCHANGE_BASE_URL", "https://api.exchangerate.host"),
-		LogLevel:        getEnv("LOG_LEVEL", "info"),
-		CacheExpiration: getEnvAsInt("CACHE_EXPIRATION", 3600),
-		UpdateInterval:  getEnvAsInt("UPDATE_INTERVAL", 14400),
-		MaxHistoryDays:  getEnvAsInt("MAX_HISTORY_DAYS", 90),
-	}
+	ConsensusProviders        map[string]int `env:"CONSENSUS_PROVIDERS"`
+	ConsensusMethod           string         `env:"CONSENSUS_METHOD"`
+	ConsensusMaxDeviation     float64        `env:"CONSENSUS_MAX_DEVIATION"`
+	ConsensusMinSources       int            `env:"CONSENSUS_MIN_SOURCES"`
+	LogLevel                  string         `env:"LOG_LEVEL"`
+	CacheExpiration           int            `env:"CACHE_EXPIRATION"`
+	CacheHistoricalExpiration int            `env:"CACHE_HISTORICAL_EXPIRATION"`
+	EnabledCurrencies         []string       `env:"ENABLED_CURRENCIES"`
+	UpdateSchedulePath        string         `env:"UPDATE_SCHEDULE_PATH"`
+	UpdateCron                string         `env:"UPDATE_CRON"`
+	UpdateTimezone            string         `env:"UPDATE_TIMEZONE"`
+	UpdateJitter              int            `env:"UPDATE_JITTER"`
+	UpdateStatePath           string         `env:"UPDATE_STATE_PATH"`
+	DemandCron                string         `env:"DEMAND_CRON"`
+	DemandTopPairs            int            `env:"DEMAND_TOP_PAIRS"`
+	DemandTopBases            int            `env:"DEMAND_TOP_BASES"`
+	DemandHalfLife            int            `env:"DEMAND_HALF_LIFE"`
+	DemandMinScore            float64        `env:"DEMAND_MIN_SCORE"`
+	ShutdownTimeout           int            `env:"SHUTDOWN_TIMEOUT"`
+	MaxHistoryDays            int            `env:"MAX_HISTORY_DAYS"`
+	WSMaxConns                int            `env:"WS_MAX_CONNECTIONS"`
+	WSSendBuffer              int            `env:"WS_SEND_BUFFER"`
+	CORSAllowedOrigins        []string       `env:"CORS_ALLOWED_ORIGINS"`
 
-	return config, nil
+	WebhookStorePath   string `env:"WEBHOOK_STORE_PATH"`
+	WebhookMaxAttempts int    `env:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookBackoff     int    `env:"WEBHOOK_BACKOFF"`
+	WebhookDeadLetters int    `env:"WEBHOOK_DEAD_LETTERS"`
+
+	AuthEnabled      bool   `env:"AUTH_ENABLED"`
+	AdminToken       string `env:"ADMIN_TOKEN"`
+	APIKeyStorePath  string `env:"API_KEY_STORE_PATH"`
+	APIKeyUsagePath  string `env:"API_KEY_USAGE_PATH"`
+	APIKeyRateLimit  int    `env:"API_KEY_RATE_LIMIT"`
+	APIKeyDailyQuota int    `env:"API_KEY_DAILY_QUOTA"`
+
+	RateLimit       int            `env:"RATE_LIMIT"`
+	RateLimitWindow int            `env:"RATE_LIMIT_WINDOW"`
+	RateLimitDir    string         `env:"RATE_LIMIT_DIR"`
+	RateLimitCosts  map[string]int `env:"RATE_LIMIT_COSTS"`
+
+	GraphQLMaxDepth      int `env:"GRAPHQL_MAX_DEPTH"`
+	GraphQLMaxComplexity int `env:"GRAPHQL_MAX_COMPLEXITY"`
+
+	OverrideStorePath string `env:"OVERRIDE_STORE_PATH"`
+	QuoteStoreDir     string `env:"QUOTE_STORE_DIR"`
+	QuoteTTL          int    `env:"QUOTE_TTL"`
+
+	MarkupProfilePath    string `env:"MARKUP_PROFILE_PATH"`
+	MarkupDefaultProfile string `env:"MARKUP_DEFAULT_PROFILE"`
+
+	AnomalyStorePath      string  `env:"ANOMALY_STORE_PATH"`
+	AnomalyRetention      int     `env:"ANOMALY_RETENTION"`
+	GuardInverseTolerance float64 `env:"GUARD_INVERSE_TOLERANCE"`
+	GuardMaxSigma         float64 `env:"GUARD_MAX_SIGMA"`
+	GuardMinHistory       int     `env:"GUARD_MIN_HISTORY"`
+	GuardConfirmAfter     int     `env:"GUARD_CONFIRM_AFTER"`
+
+	FixingStorePath string `env:"FIXING_STORE_PATH"`
+	FixingTime      string `env:"FIXING_TIME"`
+	FixingTimezone  string `env:"FIXING_TIMEZONE"`
+
+	RateHistoryStorePath string `env:"RATE_HISTORY_STORE_PATH"`
+	RateHistoryRetention int    `env:"RATE_HISTORY_RETENTION"`
+
+	ConfigPoll int `env:"CONFIG_POLL"`
+
+	OTLPEndpoint string `env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	ServiceName  string `env:"OTEL_SERVICE_NAME"`
 }
 
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// Default returns the configuration used for settings that are not set
+// anywhere else.
+func Default() *Config {
+	return &Config{
+		Port:            "8080",
+		GRPCPort:        "9090",
+		APIKey:          "73fda159574e487335c01f410f990937",
+		BaseURL:         "https://api.exchangerate.host",
+		RateProvider:    "exchangerate_host",
+		ECBBaseURL:      "https://www.ecb.europa.eu/stats/eurofxref",
+		RateSnapshotDir: "fixtures/rates",
+		FallbackMaxAge:  0,
+		SnapshotPoll:    30,
+
+		ConsensusProviders:        map[string]int{"exchangerate_host": 1, "ecb": 1},
+		ConsensusMethod:           "median",
+		ConsensusMaxDeviation:     2,
+		ConsensusMinSources:       1,
+		LogLevel:                  "info",
+		CacheExpiration:           3600,
+		CacheHistoricalExpiration: 86400,
+		UpdateSchedulePath:        "",
+		UpdateCron:                "0 */4 * * *",
+		UpdateTimezone:            "UTC",
+		UpdateJitter:              30,
+		UpdateStatePath:           "data/updater_state.json",
+		DemandCron:                "0 * * * *",
+		DemandTopPairs:            5,
+		DemandTopBases:            2,
+		DemandHalfLife:            3600,
+		DemandMinScore:            1,
+		ShutdownTimeout:           30,
+		MaxHistoryDays:            90,
+		WSMaxConns:                1000,
+		WSSendBuffer:              64,
+		CORSAllowedOrigins:        []string{},
+
+		WebhookStorePath:   "data/webhooks.json",
+		WebhookMaxAttempts: 5,
+		WebhookBackoff:     2,
+		WebhookDeadLetters: 1000,
+
+		AuthEnabled:      false,
+		AdminToken:       "",
+		APIKeyStorePath:  "data/api_keys.json",
+		APIKeyUsagePath:  "data/api_key_usage.jsonl",
+		APIKeyRateLimit:  60,
+		APIKeyDailyQuota: 10000,
+
+		RateLimit:       120,
+		RateLimitWindow: 60,
+		RateLimitCosts:  map[string]int{},
+
+		GraphQLMaxDepth:      8,
+		GraphQLMaxComplexity: 200,
+
+		OverrideStorePath: "data/overrides.json",
+		QuoteStoreDir:     "data/quotes",
+		QuoteTTL:          900,
+
+		MarkupProfilePath:    "",
+		MarkupDefaultProfile: "",
+
+		AnomalyStorePath:      "data/anomalies.json",
+		AnomalyRetention:      1000,
+		GuardInverseTolerance: 5,
+		GuardMaxSigma:         6,
+		GuardMinHistory:       5,
+		GuardConfirmAfter:     3,
+
+		FixingStorePath: "data/fixings.json",
+		FixingTime:      "16:00",
+		FixingTimezone:  "Europe/Paris",
+
+		RateHistoryStorePath: "data/rate_history.jsonl",
+		RateHistoryRetention: 365,
+
+		ConfigPoll: 5,
+
+		OTLPEndpoint: "",
+		ServiceName:  "exchange-rate-service",
 	}
-	return defaultValue
 }
 
-func getEnvAsInt(key string, defaultValue int) int {
-	if value, exists := os.LookupEnv(key); exists {
-		if intVal, err := strconv.Atoi(value); err == nil {
-			return intVal
+// Load builds the configuration from, in increasing order of precedence,
+// the defaults, the config file named by --config or CONFIG_FILE, the
+// environment (including a .env file) and the command-line flags in args.
+// It returns an error naming every setting that could not be parsed or is
+// invalid.
+func Load(args []string) (*Config, error) {
+	godotenv.Load()
+
+	flags, path, err := parseFlags(args)
+	if err != nil {
+		return nil, err
+	}
+
+	config := Default()
+	if path != "" {
+		if err := loadFile(path, config); err != nil {
+			return nil, err
 		}
+		config.Path = path
 	}
-	return defaultValue
+
+	var errs []error
+	for _, s := range settings(config) {
+		raw, source := "", ""
+		if value, ok := flags[s.flag]; ok {
+			raw, source = value, "flag --"+s.flag
+		} else if value, ok := os.LookupEnv(s.env); ok {
+			raw, source = value, "environment variable "+s.env
+		} else {
+			continue
+		}
+		if err := s.set(raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	for i, currency := range config.EnabledCurrencies {
+		config.EnabledCurrencies[i] = strings.ToUpper(currency)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
 }
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// setting is one field of a Config, addressable by its file key,
+// environment variable and flag.
+type setting struct {
+	key   string
+	env   string
+	flag  string
+	field reflect.Value
+}
+
+func settings(config *Config) []setting {
+	v := reflect.ValueOf(config).Elem()
+	t := v.Type()
+
+	result := make([]setting, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		env := t.Field(i).Tag.Get("env")
+		if env == "" || env == "-" {
+			continue
+		}
+		key := strings.ToLower(env)
+		result = append(result, setting{
+			key:   key,
+			env:   env,
+			flag:  strings.ReplaceAll(key, "_", "-"),
+			field: v.Field(i),
+		})
+	}
+	return result
+}
+
+// set parses raw as the setting's type. Lists are comma-separated and maps
+// are comma-separated key=value pairs.
+func (s setting) set(raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch s.field.Kind() {
+	case reflect.String:
+		s.field.SetString(raw)
+	case reflect.Int:
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		s.field.SetInt(int64(value))
+	case reflect.Float64:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		s.field.SetFloat(value)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		s.field.SetBool(value)
+	case reflect.Slice:
+		values := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		s.field.Set(reflect.ValueOf(values))
+	case reflect.Map:
+		values := make(map[string]int)
+		for _, pair := range strings.Split(raw, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			k, v, found := strings.Cut(pair, "=")
+			if !found {
+				return fmt.Errorf("invalid entry %q, expected key=value", strings.TrimSpace(pair))
+			}
+			value, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("invalid integer %q for %s", strings.TrimSpace(v), strings.TrimSpace(k))
+			}
+			values[strings.TrimSpace(k)] = value
+		}
+		s.field.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported setting type %s", s.field.Type())
+	}
+	return nil
+}
+
+// setValue sets the setting from a decoded config file value.
+func (s setting) setValue(value any) error {
+	switch value := value.(type) {
+	case nil:
+		return s.set("")
+	case []any:
+		if s.field.Kind() != reflect.Slice {
+			return fmt.Errorf("expected a single value, got a list")
+		}
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, fmt.Sprint(item))
+		}
+		return s.set(strings.Join(items, ","))
+	case map[string]any:
+		if s.field.Kind() != reflect.Map {
+			return fmt.Errorf("expected a single value, got a table")
+		}
+		pairs := make([]string, 0, len(value))
+		for k, v := range value {
+			pairs = append(pairs, k+"="+fmt.Sprint(v))
+		}
+		return s.set(strings.Join(pairs, ","))
+	default:
+		return s.set(fmt.Sprint(value))
+	}
+}
+
+// loadFile applies the settings in a YAML or TOML file, chosen by its
+// extension, to config.
+func loadFile(path string, config *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+
+	values := make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return fmt.Errorf("config file %s: unsupported format %q, use .yaml, .yml or .toml", path, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	byKey := make(map[string]setting)
+	for _, s := range settings(config) {
+		byKey[s.key] = s
+	}
+
+	var errs []error
+	for key, value := range values {
+		s, ok := byKey[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("config file %s: unknown setting %q", path, key))
+			continue
+		}
+		if err := s.setValue(value); err != nil {
+			errs = append(errs, fmt.Errorf("config file %s: %s: %w", path, key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// flagValue records a flag's raw value so it can be applied after the file
+// and environment.
+type flagValue struct {
+	value   string
+	boolean bool
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *flagValue) Set(value string) error {
+	f.value = value
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.boolean
+}
+
+// parseFlags returns the settings given in args, by flag name, and the
+// config file path from --config or CONFIG_FILE.
+func parseFlags(args []string) (map[string]string, string, error) {
+	fs := flag.NewFlagSet("exchange-rate-service", flag.ContinueOnError)
+	path := fs.String("config", "", "YAML or TOML config file (env CONFIG_FILE)")
+
+	values := make(map[string]*flagValue)
+	for _, s := range settings(Default()) {
+		value := &flagValue{boolean: s.field.Kind() == reflect.Bool}
+		values[s.flag] = value
+		fs.Var(value, s.flag, "overrides "+s.env)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, "", err
+	}
+	if fs.NArg() > 0 {
+		return nil, "", fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	set := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		if value, ok := values[f.Name]; ok {
+			set[f.Name] = value.value
+		}
+	})
+
+	if *path == "" {
+		*path, _ = os.LookupEnv("CONFIG_FILE")
+	}
+	return set, *path, nil
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+)
+
+var (
+	logLevels        = []string{"debug", "info", "warn", "error"}
+	rateProviders    = []string{"exchangerate_host", "ecb", "file", "consensus"}
+	consensusMethods = []string{"median", "weighted_mean"}
+)
+
+// Validate checks every setting and returns an error listing each invalid
+// one by its file key.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.port("port", c.Port, false)
+	v.port("grpc_port", c.GRPCPort, true)
+	v.oneOf("rate_provider", c.RateProvider, rateProviders)
+	v.positive("rate_snapshot_poll", c.SnapshotPoll)
+	v.nonNegative("fallback_max_age", c.FallbackMaxAge)
+
+	for name, weight := range c.ConsensusProviders {
+		if name == "consensus" || !contains(rateProviders, name) {
+			v.fail("consensus_providers", "unknown provider %q", name)
+		}
+		if weight <= 0 {
+			v.fail("consensus_providers", "weight for %s must be positive", name)
+		}
+	}
+	if c.RateProvider == "consensus" && len(c.ConsensusProviders) == 0 {
+		v.fail("consensus_providers", "required when rate_provider is consensus")
+	}
+	v.oneOf("consensus_method", c.ConsensusMethod, consensusMethods)
+	v.nonNegativeFloat("consensus_max_deviation", c.ConsensusMaxDeviation)
+	v.positive("consensus_min_sources", c.ConsensusMinSources)
+
+	v.oneOf("log_level", c.LogLevel, logLevels)
+	v.positive("cache_expiration", c.CacheExpiration)
+	v.positive("cache_historical_expiration", c.CacheHistoricalExpiration)
+	for _, currency := range c.EnabledCurrencies {
+		if !domain.SupportedCurrencies[currency] {
+			v.fail("enabled_currencies", "unsupported currency %q", currency)
+		}
+	}
+
+	v.notEmpty("update_cron", c.UpdateCron)
+	v.timezone("update_timezone", c.UpdateTimezone)
+	v.nonNegative("update_jitter", c.UpdateJitter)
+	v.notEmpty("demand_cron", c.DemandCron)
+	v.nonNegative("demand_top_pairs", c.DemandTopPairs)
+	v.nonNegative("demand_top_bases", c.DemandTopBases)
+	v.positive("demand_half_life", c.DemandHalfLife)
+	v.nonNegativeFloat("demand_min_score", c.DemandMinScore)
+	v.positive("shutdown_timeout", c.ShutdownTimeout)
+	v.positive("max_history_days", c.MaxHistoryDays)
+	v.positive("ws_max_connections", c.WSMaxConns)
+	v.positive("ws_send_buffer", c.WSSendBuffer)
+
+	v.positive("webhook_max_attempts", c.WebhookMaxAttempts)
+	v.nonNegative("webhook_backoff", c.WebhookBackoff)
+	v.nonNegative("webhook_dead_letters", c.WebhookDeadLetters)
+
+	v.positive("api_key_rate_limit", c.APIKeyRateLimit)
+	v.positive("api_key_daily_quota", c.APIKeyDailyQuota)
+	v.positive("rate_limit", c.RateLimit)
+	v.positive("rate_limit_window", c.RateLimitWindow)
+	for route, cost := range c.RateLimitCosts {
+		if cost < 0 {
+			v.fail("rate_limit_costs", "cost for %s must not be negative", route)
+		}
+	}
+
+	v.positive("graphql_max_depth", c.GraphQLMaxDepth)
+	v.positive("graphql_max_complexity", c.GraphQLMaxComplexity)
+	v.positive("quote_ttl", c.QuoteTTL)
+
+	v.nonNegative("anomaly_retention", c.AnomalyRetention)
+	v.nonNegativeFloat("guard_inverse_tolerance", c.GuardInverseTolerance)
+	v.nonNegativeFloat("guard_max_sigma", c.GuardMaxSigma)
+	v.nonNegative("guard_min_history", c.GuardMinHistory)
+	v.nonNegative("guard_confirm_after", c.GuardConfirmAfter)
+	v.nonNegative("rate_history_retention", c.RateHistoryRetention)
+
+	if _, err := time.Parse("15:04", c.FixingTime); err != nil {
+		v.fail("fixing_time", "must be HH:MM, got %q", c.FixingTime)
+	}
+	v.timezone("fixing_timezone", c.FixingTimezone)
+
+	v.nonNegative("config_poll", c.ConfigPoll)
+	v.notEmpty("otel_service_name", c.ServiceName)
+
+	if err := errors.Join(v.errs...); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return nil
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(key, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) positive(key string, value int) {
+	if value <= 0 {
+		v.fail(key, "must be positive, got %d", value)
+	}
+}
+
+func (v *validator) nonNegative(key string, value int) {
+	if value < 0 {
+		v.fail(key, "must not be negative, got %d", value)
+	}
+}
+
+func (v *validator) nonNegativeFloat(key string, value float64) {
+	if value < 0 {
+		v.fail(key, "must not be negative, got %g", value)
+	}
+}
+
+func (v *validator) notEmpty(key, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.fail(key, "must not be empty")
+	}
+}
+
+func (v *validator) oneOf(key, value string, allowed []string) {
+	if !contains(allowed, value) {
+		v.fail(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+	}
+}
+
+// port checks value is a TCP port number, or empty when optional.
+func (v *validator) port(key, value string, optional bool) {
+	if value == "" && optional {
+		return
+	}
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		v.fail(key, "must be a port number, got %q", value)
+	}
+}
+
+func (v *validator) timezone(key, value string) {
+	if _, err := time.LoadLocation(value); err != nil {
+		v.fail(key, "unknown timezone %q", value)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
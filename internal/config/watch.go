@@ -0,0 +1,151 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// reloadable are the settings a running service picks up on reload. Any
+// other change is logged and waits for a restart.
+var reloadable = map[string]bool{
+	"log_level":                   true,
+	"cache_expiration":            true,
+	"cache_historical_expiration": true,
+	"enabled_currencies":          true,
+	"update_schedule_path":        true,
+	"update_cron":                 true,
+	"update_timezone":             true,
+	"demand_cron":                 true,
+	"config_poll":                 true,
+}
+
+// Changed returns the file keys of the settings that differ between old and
+// current.
+func Changed(old, current *Config) []string {
+	oldSettings := settings(old)
+	currentSettings := settings(current)
+
+	var changed []string
+	for i, s := range currentSettings {
+		if !reflect.DeepEqual(oldSettings[i].field.Interface(), s.field.Interface()) {
+			changed = append(changed, s.key)
+		}
+	}
+	return changed
+}
+
+// Watcher reloads the configuration, from the same args as the first Load,
+// when the process receives SIGHUP or the config file changes.
+type Watcher struct {
+	args   []string
+	logger *zap.Logger
+	apply  func(*Config) error
+
+	current *Config
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher watches the configuration config was loaded with. apply is
+// called with every valid reloaded configuration, including on SIGHUP when
+// nothing in it changed, so it can re-read files the configuration points
+// to.
+func NewWatcher(config *Config, args []string, logger *zap.Logger, apply func(*Config) error) *Watcher {
+	w := &Watcher{args: args, logger: logger, apply: apply, current: config}
+	w.modTime, w.size = fileStamp(config.Path)
+	return w
+}
+
+// Run reloads on SIGHUP, and when the config file's size or modification
+// time changes, checking every config_poll seconds, until ctx is done.
+func (w *Watcher) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		var poll <-chan time.Time
+		if w.current.Path != "" && w.current.ConfigPoll > 0 {
+			poll = time.After(time.Duration(w.current.ConfigPoll) * time.Second)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			w.logger.Info("Reloading configuration on SIGHUP")
+			w.Reload()
+		case <-poll:
+			if modTime, size := fileStamp(w.current.Path); !modTime.Equal(w.modTime) || size != w.size {
+				w.logger.Info("Reloading configuration after file change", zap.String("path", w.current.Path))
+				w.Reload()
+			}
+		}
+	}
+}
+
+// Reload loads the configuration again and applies it. An invalid
+// configuration is logged and the current one kept. Settings that are not
+// reloadable keep the values the service is running with, so they are
+// reported as pending on every reload until a restart.
+func (w *Watcher) Reload() error {
+	w.modTime, w.size = fileStamp(w.current.Path)
+
+	next, err := Load(w.args)
+	if err != nil {
+		w.logger.Error("Configuration reload rejected", zap.Error(err))
+		return err
+	}
+
+	var applied, pending []string
+	for _, key := range Changed(w.current, next) {
+		if reloadable[key] {
+			applied = append(applied, key)
+		} else {
+			pending = append(pending, key)
+		}
+	}
+
+	next = withReloaded(w.current, next)
+	if err := w.apply(next); err != nil {
+		w.logger.Error("Configuration reload failed", zap.Error(err))
+		return err
+	}
+	w.current = next
+
+	w.logger.Info("Configuration reloaded", zap.Strings("changed", applied))
+	if len(pending) > 0 {
+		w.logger.Warn("Configuration changes need a restart", zap.Strings("settings", pending))
+	}
+	return nil
+}
+
+// withReloaded returns a copy of current with the reloadable settings taken
+// from next.
+func withReloaded(current, next *Config) *Config {
+	merged := *current
+	nextSettings := settings(next)
+	for i, s := range settings(&merged) {
+		if reloadable[s.key] {
+			s.field.Set(nextSettings[i].field)
+		}
+	}
+	return &merged
+}
+
+func fileStamp(path string) (time.Time, int64) {
+	if path == "" {
+		return time.Time{}, 0
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrValidation          = errors.New("validation failed")
+	ErrUnsupportedCurrency = fmt.Errorf("%w: unsupported currency", ErrValidation)
+	ErrDateOutOfRange      = fmt.Errorf("%w: date out of range", ErrValidation)
+	ErrUpstreamUnavailable = errors.New("upstream rate provider unavailable")
+	ErrStaleData           = errors.New("rates are stale")
+	ErrInvalidAPIKey       = errors.New("invalid or revoked api key")
+	ErrRateLimited         = errors.New("rate limit exceeded")
+	ErrQuotaExceeded       = errors.New("daily quota exceeded")
+	ErrExpired             = errors.New("expired")
+	ErrConflict            = errors.New("conflict")
+	ErrOverloaded          = errors.New("server at capacity")
+)
+
+// Stable, machine-readable error codes returned to clients.
+const (
+	CodeValidation          = "validation_failed"
+	CodeUnsupportedCurrency = "unsupported_currency"
+	CodeDateOutOfRange      = "date_out_of_range"
+	CodeUpstreamUnavailable = "upstream_unavailable"
+	CodeStaleData           = "stale_data"
+	CodeNotFound            = "not_found"
+	CodeUnauthorized        = "unauthorized"
+	CodeRateLimited         = "rate_limited"
+	CodeQuotaExceeded       = "quota_exceeded"
+	CodeExpired             = "expired"
+	CodeConflict            = "conflict"
+	CodeOverloaded          = "overloaded"
+	CodeTimeout             = "timeout"
+	CodeInternal            = "internal_error"
+)
+
+// Error is a domain error whose message is safe to show to clients. It
+// matches its Kind, and whatever Kind wraps, under errors.Is.
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// Errorf returns an error of the given kind whose message is the formatted
+// text alone.
+func Errorf(kind error, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// ValidationErrorf returns an error matching ErrValidation whose message is
+// the formatted text alone.
+func ValidationErrorf(format string, args ...interface{}) error {
+	return Errorf(ErrValidation, format, args...)
+}
+
+// ErrorCode classifies err into one of the stable error codes.
+func ErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrUnsupportedCurrency):
+		return CodeUnsupportedCurrency
+	case errors.Is(err, ErrDateOutOfRange):
+		return CodeDateOutOfRange
+	case errors.Is(err, ErrValidation):
+		return CodeValidation
+	case errors.Is(err, ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, ErrInvalidAPIKey):
+		return CodeUnauthorized
+	case errors.Is(err, ErrRateLimited):
+		return CodeRateLimited
+	case errors.Is(err, ErrQuotaExceeded):
+		return CodeQuotaExceeded
+	case errors.Is(err, ErrExpired):
+		return CodeExpired
+	case errors.Is(err, ErrConflict):
+		return CodeConflict
+	case errors.Is(err, ErrUpstreamUnavailable):
+		return CodeUpstreamUnavailable
+	case errors.Is(err, ErrStaleData):
+		return CodeStaleData
+	case errors.Is(err, ErrOverloaded):
+		return CodeOverloaded
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	default:
+		return CodeInternal
+	}
+}
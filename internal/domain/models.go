@@ -4,33 +4,74 @@ import (
 	"time"
 )
 
+// SourceOverride marks rates taken from a manual override rather than the
+// market.
+const SourceOverride = "override"
+
 type ExchangeRate struct {
-	FromCurrency string    `json:"from_currency"`
-	ToCurrency   string    `json:"to_currency"`
-	Rate         float64   `json:"rate"`
-	Timestamp    time.Time `json:"timestamp"`
-	Date         string    `json:"date"`
+	FromCurrency string         `json:"from_currency"`
+	ToCurrency   string         `json:"to_currency"`
+	Rate         float64        `json:"rate"`
+	Timestamp    time.Time      `json:"timestamp"`
+	Date         string         `json:"date"`
+	Source       string         `json:"source,omitempty"`
+	Consensus    *RateConsensus `json:"consensus,omitempty"`
+	ObservedAt   *time.Time     `json:"observed_at,omitempty"`
+}
+
+// RateConsensus describes how a rate was agreed between several providers.
+// Spread is the gap between the highest and lowest accepted quote.
+type RateConsensus struct {
+	Method   string   `json:"method"`
+	Sources  int      `json:"sources"`
+	Queried  int      `json:"queried"`
+	Rejected []string `json:"rejected,omitempty"`
+	Spread   float64  `json:"spread"`
 }
 
 type ConversionRequest struct {
-	From   string  `json:"from" binding:"required"`
-	To     string  `json:"to" binding:"required"`
-	Amount float64 `json:"amount"`
-	Date   string  `json:"date,omitempty"`
+	From   string     `json:"from" binding:"required"`
+	To     string     `json:"to" binding:"required"`
+	Amount float64    `json:"amount"`
+	Date   string     `json:"date,omitempty"`
+	AsOf   *time.Time `json:"as_of,omitempty"`
 }
 
 type ConversionResponse struct {
-	Amount       float64   `json:"amount"`
-	FromCurrency string    `json:"from_currency"`
-	ToCurrency   string    `json:"to_currency"`
-	Rate         float64   `json:"rate"`
-	Date         string    `json:"date"`
-	Timestamp    time.Time `json:"timestamp"`
+	Amount       float64        `json:"amount"`
+	FromCurrency string         `json:"from_currency"`
+	ToCurrency   string         `json:"to_currency"`
+	Rate         float64        `json:"rate"`
+	Date         string         `json:"date"`
+	Timestamp    time.Time      `json:"timestamp"`
+	Source       string         `json:"source,omitempty"`
+	Consensus    *RateConsensus `json:"consensus,omitempty"`
+	ObservedAt   *time.Time     `json:"observed_at,omitempty"`
+	Pricing      *Pricing       `json:"pricing,omitempty"`
+}
+
+type BatchConversionRequest struct {
+	Conversions []ConversionRequest `json:"conversions"`
+}
+
+type BatchConversionResult struct {
+	Request ConversionRequest   `json:"request"`
+	Result  *ConversionResponse `json:"result,omitempty"`
+	Error   *ErrorResponse      `json:"error,omitempty"`
+}
+
+type BatchConversionResponse struct {
+	Results []BatchConversionResult `json:"results"`
+	Count   int                     `json:"count"`
 }
 
 type LatestRatesResponse struct {
 	BaseCurrency string             `json:"base_currency"`
 	Rates        map[string]float64 `json:"rates"`
+	Sources      map[string]string  `json:"sources,omitempty"`
+	Profile      string             `json:"profile,omitempty"`
+	Bid          map[string]float64 `json:"bid,omitempty"`
+	Ask          map[string]float64 `json:"ask,omitempty"`
 	Timestamp    time.Time          `json:"timestamp"`
 	Date         string             `json:"date"`
 }
@@ -41,12 +82,16 @@ type HistoricalRatesResponse struct {
 	Rates        map[string]ExchangeRate `json:"rates"`
 	StartDate    string                  `json:"start_date"`
 	EndDate      string                  `json:"end_date"`
+	AsOf         *time.Time              `json:"as_of,omitempty"`
 }
 
+// ErrorResponse is the default error body. Error holds a stable code such
+// as unsupported_currency; Status repeats the HTTP status.
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Error     string `json:"error"`
+	Status    int    `json:"status"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 var SupportedCurrencies = map[string]bool{
@@ -56,3 +101,348 @@ var SupportedCurrencies = map[string]bool{
 	"JPY": true,
 	"GBP": true,
 }
+
+type RateUpdate struct {
+	BaseCurrency string             `json:"base_currency"`
+	Rates        map[string]float64 `json:"rates"`
+	Changed      map[string]float64 `json:"changed"`
+	Timestamp    time.Time          `json:"timestamp"`
+}
+
+type PairRate struct {
+	Pair         string  `json:"pair"`
+	FromCurrency string  `json:"from_currency"`
+	ToCurrency   string  `json:"to_currency"`
+	Rate         float64 `json:"rate"`
+}
+
+type StreamRequest struct {
+	Action string   `json:"action"`
+	Pairs  []string `json:"pairs,omitempty"`
+}
+
+type StreamMessage struct {
+	Type      string     `json:"type"`
+	Pairs     []string   `json:"pairs,omitempty"`
+	Rates     []PairRate `json:"rates,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+	Error     string     `json:"error,omitempty"`
+	Message   string     `json:"message,omitempty"`
+}
+
+type WebhookCondition struct {
+	Type      string  `json:"type" binding:"required"`
+	Threshold float64 `json:"threshold"`
+}
+
+type WebhookSubscription struct {
+	ID        string           `json:"id"`
+	Pair      string           `json:"pair" binding:"required"`
+	Condition WebhookCondition `json:"condition" binding:"required"`
+	URL       string           `json:"url" binding:"required"`
+	Secret    string           `json:"secret,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+type WebhookEvent struct {
+	ID             string    `json:"id"`
+	SubscriptionID string    `json:"subscription_id"`
+	Pair           string    `json:"pair"`
+	Condition      string    `json:"condition"`
+	Threshold      float64   `json:"threshold"`
+	Rate           float64   `json:"rate"`
+	ReferenceRate  float64   `json:"reference_rate"`
+	ChangePercent  float64   `json:"change_percent"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// WebhookState is what a subscription's condition was last evaluated
+// against: the last rate seen, and for change_pct the day, its reference
+// rate and the day the condition last fired.
+type WebhookState struct {
+	LastRate float64 `json:"last_rate"`
+	Day      string  `json:"day"`
+	DayRef   float64 `json:"day_ref"`
+	FiredDay string  `json:"fired_day,omitempty"`
+}
+
+type WebhookDeadLetter struct {
+	ID             string       `json:"id"`
+	SubscriptionID string       `json:"subscription_id"`
+	URL            string       `json:"url"`
+	Event          WebhookEvent `json:"event"`
+	Attempts       int          `json:"attempts"`
+	LastError      string       `json:"last_error"`
+	FailedAt       time.Time    `json:"failed_at"`
+}
+
+type APIKey struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Prefix        string     `json:"prefix"`
+	Hash          string     `json:"hash,omitempty"`
+	RateLimit     int        `json:"rate_limit"`
+	DailyQuota    int        `json:"daily_quota"`
+	MarkupProfile string     `json:"markup_profile,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+}
+
+type APIKeyRequest struct {
+	Name          string `json:"name"`
+	RateLimit     int    `json:"rate_limit"`
+	DailyQuota    int    `json:"daily_quota"`
+	MarkupProfile string `json:"markup_profile,omitempty"`
+}
+
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type APIKeyUsage struct {
+	Date        string `json:"date"`
+	Requests    int    `json:"requests"`
+	RateLimited int    `json:"rate_limited"`
+	OverQuota   int    `json:"over_quota"`
+}
+
+type APIKeyUsageReport struct {
+	KeyID      string        `json:"key_id"`
+	Name       string        `json:"name"`
+	DailyQuota int           `json:"daily_quota"`
+	Usage      []APIKeyUsage `json:"usage"`
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+type CacheEntry struct {
+	Key        string      `json:"key"`
+	Value      interface{} `json:"value,omitempty"`
+	StoredAt   time.Time   `json:"stored_at"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+	AgeSeconds int64       `json:"age_seconds"`
+	TTLSeconds int64       `json:"ttl_seconds"`
+}
+
+type CacheRefreshResponse struct {
+	Refreshed []string          `json:"refreshed"`
+	Failed    map[string]string `json:"failed,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// RateOverride pins the rate of a pair for an inclusive date range.
+type RateOverride struct {
+	ID           string    `json:"id"`
+	Pair         string    `json:"pair"`
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	Rate         float64   `json:"rate"`
+	StartDate    string    `json:"start_date"`
+	EndDate      string    `json:"end_date"`
+	Reason       string    `json:"reason"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedBy    string    `json:"updated_by,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Covers reports whether date falls inside the override's range.
+func (o *RateOverride) Covers(date string) bool {
+	return o.StartDate <= date && date <= o.EndDate
+}
+
+type RateOverrideRequest struct {
+	Pair      string  `json:"pair"`
+	Rate      float64 `json:"rate"`
+	StartDate string  `json:"start_date"`
+	EndDate   string  `json:"end_date"`
+	Reason    string  `json:"reason"`
+}
+
+// OverrideAuditEntry records one change to an override together with the
+// override as it stood afterwards (or before deletion).
+type OverrideAuditEntry struct {
+	ID         string       `json:"id"`
+	OverrideID string       `json:"override_id"`
+	Action     string       `json:"action"`
+	Actor      string       `json:"actor"`
+	Reason     string       `json:"reason"`
+	Override   RateOverride `json:"override"`
+	Timestamp  time.Time    `json:"timestamp"`
+}
+
+type QuoteRequest struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Amount float64 `json:"amount"`
+}
+
+// Quote locks a rate for a conversion until ExpiresAt. It can be executed
+// once.
+type Quote struct {
+	ID              string     `json:"id"`
+	Pair            string     `json:"pair"`
+	FromCurrency    string     `json:"from_currency"`
+	ToCurrency      string     `json:"to_currency"`
+	Rate            float64    `json:"rate"`
+	Amount          float64    `json:"amount"`
+	ConvertedAmount float64    `json:"converted_amount"`
+	Source          string     `json:"source,omitempty"`
+	Pricing         *Pricing   `json:"pricing,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	ExecutedAt      *time.Time `json:"executed_at,omitempty"`
+}
+
+// Markup is a margin either side of the mid rate, given in basis points or
+// in percent but not both.
+type Markup struct {
+	BasisPoints float64 `json:"bps,omitempty"`
+	Percent     float64 `json:"percent,omitempty"`
+}
+
+// Bps returns the markup in basis points.
+func (m Markup) Bps() float64 {
+	return m.BasisPoints + m.Percent*100
+}
+
+// MarkupRule overrides a profile's default markup for the listed pairs
+// (e.g. "EURUSD") or for any pair touching one of the listed currencies.
+type MarkupRule struct {
+	Pairs      []string `json:"pairs,omitempty"`
+	Currencies []string `json:"currencies,omitempty"`
+	Markup
+}
+
+type MarkupProfile struct {
+	Name    string       `json:"name"`
+	Default Markup       `json:"default"`
+	Rules   []MarkupRule `json:"rules,omitempty"`
+}
+
+// Pricing breaks a conversion down into the mid rate and the client rates
+// derived from it. The client receives Amount at BidRate; Fee is the
+// difference from GrossAmount at the mid rate.
+type Pricing struct {
+	Profile     string  `json:"profile"`
+	MidRate     float64 `json:"mid_rate"`
+	BidRate     float64 `json:"bid_rate"`
+	AskRate     float64 `json:"ask_rate"`
+	MarkupBps   float64 `json:"markup_bps"`
+	GrossAmount float64 `json:"gross_amount"`
+	Fee         float64 `json:"fee"`
+}
+
+// Rate anomaly checks.
+const (
+	AnomalyNonPositive = "non_positive"
+	AnomalyInverse     = "inverse_mismatch"
+	AnomalyJump        = "jump"
+)
+
+// RateAnomaly records an upstream rate the guard refused to cache. LastGood
+// is the rate served instead, zero when there was none. Count is how many
+// times the same rate was rejected by the same check before anyone reviewed
+// it, the last of them at LastSeenAt.
+type RateAnomaly struct {
+	ID         string     `json:"id"`
+	Pair       string     `json:"pair"`
+	Check      string     `json:"check"`
+	Rate       float64    `json:"rate"`
+	LastGood   float64    `json:"last_good,omitempty"`
+	Detail     string     `json:"detail"`
+	DetectedAt time.Time  `json:"detected_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	Count      int        `json:"count"`
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	Accepted   bool       `json:"accepted,omitempty"`
+}
+
+// Fixing is one version of the official end-of-day rates for a date, taken
+// at the configured fixing time. Version 1 is the captured snapshot; each
+// correction adds a version that supersedes the one before it. Rates maps a
+// base currency to its rate table.
+type Fixing struct {
+	Date      string                        `json:"date"`
+	Version   int                           `json:"version"`
+	FixedAt   time.Time                     `json:"fixed_at"`
+	Timezone  string                        `json:"timezone"`
+	Rates     map[string]map[string]float64 `json:"rates"`
+	CreatedBy string                        `json:"created_by"`
+	CreatedAt time.Time                     `json:"created_at"`
+	Reason    string                        `json:"reason,omitempty"`
+}
+
+// FixingCorrectionRequest replaces individual rates of the current fixing
+// version; rates it does not mention carry over unchanged.
+type FixingCorrectionRequest struct {
+	Rates  map[string]map[string]float64 `json:"rates"`
+	Reason string                        `json:"reason"`
+}
+
+// FixingCaptureRequest takes a fixing the scheduler missed, from the rates
+// at the time of the request.
+type FixingCaptureRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RateObservation is a rate as the service learned it from the provider.
+// Date is the day the rate is for and ObservedAt when the service fetched
+// it, so a provider revising a past day adds an observation instead of
+// replacing one.
+type RateObservation struct {
+	Pair         string    `json:"pair"`
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	Date         string    `json:"date"`
+	Rate         float64   `json:"rate"`
+	Timestamp    time.Time `json:"timestamp"`
+	ObservedAt   time.Time `json:"observed_at"`
+}
+
+// UpdateGroup is a refresh schedule for a set of base currencies, whose
+// whole rate tables are refreshed, and pairs, which are refreshed alone.
+// A Demand group refreshes whatever is in demand at the time instead. Cron
+// is a five-field expression or descriptor such as @hourly, evaluated in
+// Timezone (UTC when empty).
+type UpdateGroup struct {
+	Name     string   `json:"name"`
+	Cron     string   `json:"cron"`
+	Timezone string   `json:"timezone,omitempty"`
+	Bases    []string `json:"bases,omitempty"`
+	Pairs    []string `json:"pairs,omitempty"`
+	Demand   bool     `json:"demand,omitempty"`
+}
+
+// UpdateGroupStatus is an update group with its last and next run.
+type UpdateGroupStatus struct {
+	UpdateGroup
+	LastRun *time.Time `json:"last_run,omitempty"`
+	NextRun time.Time  `json:"next_run"`
+}
+
+// DemandStat is how often a pair or base currency has been requested.
+// Score counts requests but halves every demand half-life, so it reflects
+// recent demand.
+type DemandStat struct {
+	Key      string    `json:"key"`
+	Score    float64   `json:"score"`
+	Requests int64     `json:"requests"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// HotSet is what a demand update group keeps warm: the most requested
+// pairs, refreshed alone, and base currencies, whose tables are refreshed.
+type HotSet struct {
+	Pairs []DemandStat `json:"pairs"`
+	Bases []DemandStat `json:"bases"`
+}
@@ -17,3 +17,112 @@ type CacheRepository interface {
 	Delete(key string) error
 	Clear() error
 }
+
+// SharedCache is a CacheRepository several processes use at once. Lock
+// holds key against all of them until unlock is called, so one process's
+// read-modify-write of the key is not lost to another's.
+type SharedCache interface {
+	CacheRepository
+	Lock(key string) (unlock func(), err error)
+}
+
+// CacheInspector is a CacheRepository that can also enumerate and bulk-delete
+// its entries for the admin API.
+type CacheInspector interface {
+	CacheRepository
+	Entries(prefix string) []CacheEntry
+	Entry(key string) (*CacheEntry, error)
+	DeletePrefix(prefix string) int
+}
+
+// WebhookRepository stores webhook subscriptions, the state their
+// conditions were last evaluated against, and dead letters. SaveStates
+// replaces every stored state.
+type WebhookRepository interface {
+	Create(sub *WebhookSubscription) error
+	Get(id string) (*WebhookSubscription, error)
+	List() ([]WebhookSubscription, error)
+	Update(sub *WebhookSubscription) error
+	Delete(id string) error
+	States() (map[string]WebhookState, error)
+	SaveStates(states map[string]WebhookState) error
+	AddDeadLetter(letter *WebhookDeadLetter) error
+	GetDeadLetter(id string) (*WebhookDeadLetter, error)
+	UpdateDeadLetter(letter *WebhookDeadLetter) error
+	DeleteDeadLetter(id string) error
+	ListDeadLetters() ([]WebhookDeadLetter, error)
+}
+
+type APIKeyRepository interface {
+	Create(key *APIKey) error
+	Get(id string) (*APIKey, error)
+	GetByHash(hash string) (*APIKey, error)
+	List() ([]APIKey, error)
+	Update(key *APIKey) error
+}
+
+// APIKeyUsageRepository keeps per-key daily usage counters. Get returns
+// zero counters for a day with no usage.
+type APIKeyUsageRepository interface {
+	Get(keyID, date string) (*APIKeyUsage, error)
+	Save(keyID string, usage *APIKeyUsage) error
+}
+
+// RateOverrideRepository stores overrides with their audit trail. Create,
+// Update and Delete save the change and its audit entry together or not at
+// all.
+type RateOverrideRepository interface {
+	Create(override *RateOverride, entry *OverrideAuditEntry) error
+	Get(id string) (*RateOverride, error)
+	List() ([]RateOverride, error)
+	ListPair(pair string) ([]RateOverride, error)
+	Update(override *RateOverride, entry *OverrideAuditEntry) error
+	Delete(id string, entry *OverrideAuditEntry) error
+	ListAudit() ([]OverrideAuditEntry, error)
+}
+
+// RateAnomalyRepository stores rejected rates. Record adds anomaly, or counts
+// it against an unreviewed anomaly of the same pair, check and rate, and
+// returns the anomaly as stored.
+type RateAnomalyRepository interface {
+	Record(anomaly *RateAnomaly) (*RateAnomaly, error)
+	Get(id string) (*RateAnomaly, error)
+	List() ([]RateAnomaly, error)
+	Update(anomaly *RateAnomaly) error
+}
+
+// QuoteRepository stores rate quotes. Execute marks a quote executed at the
+// given time unless it already was, in which case it fails with ErrConflict,
+// so a quote is executed at most once. Prune drops quotes that expired
+// before the given time.
+// QuoteRepository stores quotes. Execute marks a quote executed at at in
+// one atomic step, failing with ErrExpired once it has expired and with
+// ErrConflict when it was already executed.
+type QuoteRepository interface {
+	Create(quote *Quote) error
+	Get(id string) (*Quote, error)
+	Execute(id string, at time.Time) (*Quote, error)
+	Prune(expiredBefore time.Time) error
+}
+
+// FixingRepository stores fixing versions append-only. Add fails with
+// ErrConflict unless the fixing is the next version for its date.
+type FixingRepository interface {
+	Add(fixing *Fixing) error
+	Versions(date string) ([]Fixing, error)
+}
+
+// RateObservationRepository keeps every observed rate. AsOf returns the
+// observation for date that was current at asOf; an empty date means the
+// latest date observed by then.
+type RateObservationRepository interface {
+	Record(observations []RateObservation) error
+	AsOf(from, to, date string, asOf time.Time) (*RateObservation, error)
+}
+
+// UpdateRunRepository remembers the last scheduled run of each update group
+// across restarts. LastRun returns the zero time for a group that never ran.
+type UpdateRunRepository interface {
+	LastRun(group string) (time.Time, error)
+	SetLastRun(group string, at time.Time) error
+}
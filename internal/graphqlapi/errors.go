@@ -0,0 +1,53 @@
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+
+	"go.uber.org/zap"
+)
+
+// codedError carries a machine-readable code in the GraphQL error's
+// extensions, e.g. {"code": "BAD_USER_INPUT"}.
+type codedError struct {
+	err  error
+	code string
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+func (e *codedError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+func (e *Executor) resolverError(ctx context.Context, msg string, err error) error {
+	code := errorCode(err)
+	if code == "INTERNAL" {
+		requestid.Logger(ctx, e.logger).Error(msg, zap.Error(err))
+	}
+	return &codedError{err: err, code: code}
+}
+
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return "BAD_USER_INPUT"
+	case errors.Is(err, domain.ErrNotFound):
+		return "NOT_FOUND"
+	case errors.Is(err, domain.ErrUpstreamUnavailable), errors.Is(err, domain.ErrStaleData):
+		return "UNAVAILABLE"
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return "TIMEOUT"
+	default:
+		return "INTERNAL"
+	}
+}
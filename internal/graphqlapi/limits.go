@@ -0,0 +1,120 @@
+package graphqlapi
+
+import (
+	"fmt"
+	"strings"
+
+	"exchange-rate-service/internal/utils"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// queryCost tracks the depth and complexity of an operation. Every field
+// costs one point, except historical series which cost one point per day,
+// up to utils.MaxHistoryDays, since each day may need its own upstream
+// fetch. Introspection fields are not counted.
+type queryCost struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+
+	depth      int
+	complexity int
+}
+
+func checkLimits(doc *ast.Document, operationName string, variables map[string]interface{}, limits Limits) *codedError {
+	cost := measure(doc, operationName, variables)
+
+	if limits.MaxDepth > 0 && cost.depth > limits.MaxDepth {
+		return &codedError{
+			err:  fmt.Errorf("query depth %d exceeds the maximum of %d", cost.depth, limits.MaxDepth),
+			code: "QUERY_TOO_DEEP",
+		}
+	}
+	if limits.MaxComplexity > 0 && cost.complexity > limits.MaxComplexity {
+		return &codedError{
+			err:  fmt.Errorf("query complexity %d exceeds the maximum of %d", cost.complexity, limits.MaxComplexity),
+			code: "QUERY_TOO_COMPLEX",
+		}
+	}
+
+	return nil
+}
+
+// measure walks the operations of doc that operationName selects.
+func measure(doc *ast.Document, operationName string, variables map[string]interface{}) *queryCost {
+	cost := &queryCost{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+	}
+
+	var operations []*ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			cost.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				operations = append(operations, def)
+			}
+		}
+	}
+
+	for _, op := range operations {
+		cost.walk(op.SelectionSet, 1)
+	}
+	return cost
+}
+
+func (q *queryCost) walk(set *ast.SelectionSet, depth int) {
+	if set == nil {
+		return
+	}
+
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			if depth > q.depth {
+				q.depth = depth
+			}
+			q.complexity += q.fieldCost(selection)
+			q.walk(selection.SelectionSet, depth+1)
+		case *ast.InlineFragment:
+			q.walk(selection.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			if fragment, exists := q.fragments[selection.Name.Value]; exists {
+				q.walk(fragment.SelectionSet, depth)
+			}
+		}
+	}
+}
+
+func (q *queryCost) fieldCost(field *ast.Field) int {
+	if field.Name.Value != "historical" {
+		return 1
+	}
+
+	days, err := utils.CountDays(q.stringArgument(field, "startDate"), q.stringArgument(field, "endDate"), utils.MaxHistoryDays)
+	if err != nil {
+		return 1
+	}
+	return days
+}
+
+func (q *queryCost) stringArgument(field *ast.Field, name string) string {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != name {
+			continue
+		}
+		switch value := arg.Value.(type) {
+		case *ast.StringValue:
+			return value.Value
+		case *ast.Variable:
+			s, _ := q.variables[value.Name.Value].(string)
+			return s
+		}
+	}
+	return ""
+}
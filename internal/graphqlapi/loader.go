@@ -0,0 +1,145 @@
+package graphqlapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/internal/utils"
+)
+
+type loaderKey struct{}
+
+// loader memoizes rate lookups for the lifetime of a single query, so that
+// conversions sharing a base currency are served from one latest-rates
+// lookup instead of one per pair.
+type loader struct {
+	service *service.ExchangeService
+
+	mu         sync.Mutex
+	latest     map[string]latestResult
+	conversion map[string]conversionResult
+	historical map[string]historicalResult
+}
+
+type latestResult struct {
+	rates *domain.LatestRatesResponse
+	err   error
+}
+
+type conversionResult struct {
+	conversion *domain.ConversionResponse
+	err        error
+}
+
+type historicalResult struct {
+	rates *domain.HistoricalRatesResponse
+	err   error
+}
+
+func newLoader(exchangeService *service.ExchangeService) *loader {
+	return &loader{
+		service:    exchangeService,
+		latest:     make(map[string]latestResult),
+		conversion: make(map[string]conversionResult),
+		historical: make(map[string]historicalResult),
+	}
+}
+
+func withLoader(ctx context.Context, l *loader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+func loaderFrom(ctx context.Context) *loader {
+	return ctx.Value(loaderKey{}).(*loader)
+}
+
+func (l *loader) Latest(ctx context.Context, base string) (*domain.LatestRatesResponse, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if result, exists := l.latest[base]; exists {
+		return result.rates, result.err
+	}
+
+	rates, err := l.service.GetLatestRates(ctx, base)
+	l.latest[base] = latestResult{rates: rates, err: err}
+	return rates, err
+}
+
+// Convert prices a conversion. Latest conversions reuse the base currency's
+// rate table unless consensus is wanted, which only single-pair lookups
+// report; those and dated conversions are memoized per pair and date.
+func (l *loader) Convert(ctx context.Context, req domain.ConversionRequest, consensus bool) (*domain.ConversionResponse, error) {
+	if req.Amount == 0 {
+		req.Amount = 1
+	}
+
+	if req.Date == "" && !consensus {
+		return l.convertLatest(ctx, req)
+	}
+
+	l.mu.Lock()
+	key := fmt.Sprintf("%s_%s_%s", req.From, req.To, req.Date)
+	result, exists := l.conversion[key]
+	if !exists {
+		unit := domain.ConversionRequest{From: req.From, To: req.To, Amount: 1, Date: req.Date}
+		result.conversion, result.err = l.service.ConvertCurrency(ctx, &unit)
+		l.conversion[key] = result
+	}
+	l.mu.Unlock()
+
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	conversion := *result.conversion
+	l.service.Price(ctx, &conversion, req.Amount)
+	return &conversion, nil
+}
+
+func (l *loader) convertLatest(ctx context.Context, req domain.ConversionRequest) (*domain.ConversionResponse, error) {
+	if !utils.IsValidCurrency(req.To) {
+		return nil, domain.ValidationErrorf("unsupported currency pair: %s to %s", req.From, req.To)
+	}
+
+	latest, err := l.Latest(ctx, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	rate, exists := latest.Rates[req.To]
+	if req.From == req.To {
+		rate, exists = 1, true
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: no rate for %s to %s", domain.ErrNotFound, req.From, req.To)
+	}
+
+	conversion := &domain.ConversionResponse{
+		FromCurrency: req.From,
+		ToCurrency:   req.To,
+		Rate:         rate,
+		Date:         latest.Date,
+		Timestamp:    latest.Timestamp,
+		Source:       latest.Sources[req.To],
+	}
+	l.service.Price(ctx, conversion, req.Amount)
+	return conversion, nil
+}
+
+func (l *loader) Historical(ctx context.Context, from, to, startDate, endDate string) (*domain.HistoricalRatesResponse, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := fmt.Sprintf("%s_%s_%s_%s", from, to, startDate, endDate)
+	if result, exists := l.historical[key]; exists {
+		return result.rates, result.err
+	}
+
+	rates, err := l.service.GetHistoricalRates(ctx, from, to, startDate, endDate)
+	l.historical[key] = historicalResult{rates: rates, err: err}
+	return rates, err
+}
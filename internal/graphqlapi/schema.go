@@ -0,0 +1,367 @@
+package graphqlapi
+
+import (
+	"context"
+	"sort"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/internal/utils"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"go.uber.org/zap"
+)
+
+// Limits bounds the shape of accepted queries. Zero disables a limit.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type Executor struct {
+	schema  graphql.Schema
+	service *service.ExchangeService
+	logger  *zap.Logger
+	limits  Limits
+}
+
+func New(exchangeService *service.ExchangeService, logger *zap.Logger, limits Limits) (*Executor, error) {
+	e := &Executor{
+		service: exchangeService,
+		logger:  logger,
+		limits:  limits,
+	}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: e.queryType()})
+	if err != nil {
+		return nil, err
+	}
+	e.schema = schema
+
+	return e, nil
+}
+
+// Execute parses, validates and runs a query. Queries exceeding the depth
+// or complexity limits are rejected before any resolver runs.
+func (e *Executor) Execute(ctx context.Context, req Request) *graphql.Result {
+	doc, err := parse(req)
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if validation := graphql.ValidateDocument(&e.schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	if err := checkLimits(doc, req.OperationName, req.Variables, e.limits); err != nil {
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{{
+			Message:    err.Error(),
+			Locations:  []location.SourceLocation{},
+			Extensions: err.Extensions(),
+		}}}
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoader(ctx, newLoader(e.service)),
+	})
+}
+
+// Cost returns how many rate-limit tokens req consumes: its complexity,
+// capped at the complexity limit since costlier queries are rejected
+// anyway. A query that does not parse costs one token.
+func (e *Executor) Cost(req Request) int {
+	doc, err := parse(req)
+	if err != nil {
+		return 1
+	}
+
+	cost := measure(doc, req.OperationName, req.Variables).complexity
+	if e.limits.MaxComplexity > 0 && cost > e.limits.MaxComplexity {
+		cost = e.limits.MaxComplexity
+	}
+	if cost < 1 {
+		cost = 1
+	}
+	return cost
+}
+
+func parse(req Request) (*ast.Document, error) {
+	return parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+}
+
+func (e *Executor) queryType() *graphql.Object {
+	rateType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Rate",
+		Fields: graphql.Fields{
+			"currency": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"rate":     &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	latestType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "LatestRates",
+		Fields: graphql.Fields{
+			"baseCurrency": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"date":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"timestamp":    &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"rates": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(rateType))),
+				Description: "Rates sorted by currency, optionally restricted to symbols",
+				Args: graphql.FieldConfigArgument{
+					"symbols": &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+				},
+				Resolve: resolveRates,
+			},
+		},
+	})
+
+	consensusType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Consensus",
+		Fields: graphql.Fields{
+			"method":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"sources":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "Providers whose quotes were accepted"},
+			"queried":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"rejected": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+			"spread":   &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	conversionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Conversion",
+		Fields: graphql.Fields{
+			"fromCurrency": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"toCurrency":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"amount":       &graphql.Field{Type: graphql.NewNonNull(graphql.Float), Description: "Converted amount"},
+			"rate":         &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"date":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"timestamp":    &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"source":       &graphql.Field{Type: graphql.String, Description: "Set when the rate did not come from the market, e.g. override"},
+			"consensus":    &graphql.Field{Type: consensusType, Description: "How providers agreed on the rate, with RATE_PROVIDER=consensus"},
+		},
+	})
+
+	historicalRateType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "HistoricalRate",
+		Fields: graphql.Fields{
+			"date": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"rate": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	seriesType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "HistoricalSeries",
+		Fields: graphql.Fields{
+			"fromCurrency": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"toCurrency":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"startDate":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"endDate":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"rates": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(historicalRateType))),
+				Description: "Daily rates in date order; days without data are omitted",
+			},
+		},
+	})
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"currencies": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Resolve: e.resolveCurrencies,
+			},
+			"latest": &graphql.Field{
+				Type: graphql.NewNonNull(latestType),
+				Args: graphql.FieldConfigArgument{
+					"base": &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "USD"},
+				},
+				Resolve: e.resolveLatest,
+			},
+			"convert": &graphql.Field{
+				Type: graphql.NewNonNull(conversionType),
+				Args: graphql.FieldConfigArgument{
+					"from":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"to":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"amount": &graphql.ArgumentConfig{Type: graphql.Float, DefaultValue: 1.0},
+					"date":   &graphql.ArgumentConfig{Type: graphql.String, Description: "Convert at a past date's rate"},
+				},
+				Resolve: e.resolveConvert,
+			},
+			"historical": &graphql.Field{
+				Type: graphql.NewNonNull(seriesType),
+				Args: graphql.FieldConfigArgument{
+					"from":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"to":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"startDate": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"endDate":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: e.resolveHistorical,
+			},
+		},
+	})
+}
+
+func (e *Executor) resolveCurrencies(p graphql.ResolveParams) (interface{}, error) {
+	currencies := utils.EnabledCurrencies()
+	return currencies, nil
+}
+
+func (e *Executor) resolveLatest(p graphql.ResolveParams) (interface{}, error) {
+	base, _ := p.Args["base"].(string)
+
+	latest, err := loaderFrom(p.Context).Latest(p.Context, base)
+	if err != nil {
+		return nil, e.resolverError(p.Context, "Failed to get latest rates", err)
+	}
+
+	return map[string]interface{}{
+		"baseCurrency": latest.BaseCurrency,
+		"date":         latest.Date,
+		"timestamp":    latest.Timestamp,
+		"rates":        latest.Rates,
+	}, nil
+}
+
+func resolveRates(p graphql.ResolveParams) (interface{}, error) {
+	source, _ := p.Source.(map[string]interface{})
+	rates, _ := source["rates"].(map[string]float64)
+
+	currencies := make([]string, 0, len(rates))
+	if symbols, ok := p.Args["symbols"].([]interface{}); ok {
+		for _, symbol := range symbols {
+			if currency, _ := symbol.(string); currency != "" {
+				if _, exists := rates[currency]; exists {
+					currencies = append(currencies, currency)
+				}
+			}
+		}
+	} else {
+		for currency := range rates {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies)
+
+	result := make([]map[string]interface{}, len(currencies))
+	for i, currency := range currencies {
+		result[i] = map[string]interface{}{"currency": currency, "rate": rates[currency]}
+	}
+	return result, nil
+}
+
+func (e *Executor) resolveConvert(p graphql.ResolveParams) (interface{}, error) {
+	req := domain.ConversionRequest{}
+	req.From, _ = p.Args["from"].(string)
+	req.To, _ = p.Args["to"].(string)
+	req.Amount, _ = p.Args["amount"].(float64)
+	req.Date, _ = p.Args["date"].(string)
+
+	conversion, err := loaderFrom(p.Context).Convert(p.Context, req, selects(p, "consensus"))
+	if err != nil {
+		return nil, e.resolverError(p.Context, "Conversion failed", err)
+	}
+
+	result := map[string]interface{}{
+		"fromCurrency": conversion.FromCurrency,
+		"toCurrency":   conversion.ToCurrency,
+		"amount":       conversion.Amount,
+		"rate":         conversion.Rate,
+		"date":         conversion.Date,
+		"timestamp":    conversion.Timestamp,
+		"consensus":    nil,
+	}
+	if conversion.Source != "" {
+		result["source"] = conversion.Source
+	}
+	if c := conversion.Consensus; c != nil {
+		rejected := c.Rejected
+		if rejected == nil {
+			rejected = []string{}
+		}
+		result["consensus"] = map[string]interface{}{
+			"method":   c.Method,
+			"sources":  c.Sources,
+			"queried":  c.Queried,
+			"rejected": rejected,
+			"spread":   c.Spread,
+		}
+	}
+	return result, nil
+}
+
+// selects reports whether the field being resolved selects the named
+// subfield directly or through an inline fragment.
+func selects(p graphql.ResolveParams, name string) bool {
+	for _, field := range p.Info.FieldASTs {
+		if selectionSetHas(field.SelectionSet, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func selectionSetHas(set *ast.SelectionSet, name string) bool {
+	if set == nil {
+		return false
+	}
+	for _, selection := range set.Selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Name != nil && s.Name.Value == name {
+				return true
+			}
+		case *ast.InlineFragment:
+			if selectionSetHas(s.SelectionSet, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (e *Executor) resolveHistorical(p graphql.ResolveParams) (interface{}, error) {
+	from, _ := p.Args["from"].(string)
+	to, _ := p.Args["to"].(string)
+	startDate, _ := p.Args["startDate"].(string)
+	endDate, _ := p.Args["endDate"].(string)
+
+	historical, err := loaderFrom(p.Context).Historical(p.Context, from, to, startDate, endDate)
+	if err != nil {
+		return nil, e.resolverError(p.Context, "Failed to get historical rates", err)
+	}
+
+	dates := make([]string, 0, len(historical.Rates))
+	for date := range historical.Rates {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	rates := make([]map[string]interface{}, len(dates))
+	for i, date := range dates {
+		rates[i] = map[string]interface{}{"date": date, "rate": historical.Rates[date].Rate}
+	}
+
+	return map[string]interface{}{
+		"fromCurrency": historical.FromCurrency,
+		"toCurrency":   historical.ToCurrency,
+		"startDate":    historical.StartDate,
+		"endDate":      historical.EndDate,
+		"rates":        rates,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"exchange-rate-service/internal/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func unaryAuth(keys *service.APIKeyService, cost costFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, keys, cost(info.FullMethod, req))
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(keys *service.APIKeyService, cost costFunc) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(stream.Context(), keys, cost(info.FullMethod, nil))
+		if err != nil {
+			return err
+		}
+		return handler(srv, &scopedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authorize checks the caller's API key, charges cost to its rate limit and
+// quota, and returns ctx carrying the key's markup profile.
+func authorize(ctx context.Context, keys *service.APIKeyService, cost int) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	raw := first(md.Get("x-api-key"))
+	if raw == "" {
+		auth := first(md.Get("authorization"))
+		if len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+			raw = strings.TrimSpace(auth[7:])
+		}
+	}
+
+	key, err := keys.Authenticate(raw)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if result, err := keys.Allow(key, cost); err != nil {
+		return nil, rateLimited(ctx, result, err)
+	}
+
+	if key.MarkupProfile != "" {
+		ctx = service.WithMarkupProfile(ctx, key.MarkupProfile)
+	}
+	return ctx, nil
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,59 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps domain errors onto gRPC status codes. The message is the
+// client-facing one HTTP responses carry, so upstream details stay in the
+// server logs.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var code codes.Code
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		code = codes.InvalidArgument
+	case errors.Is(err, domain.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrInvalidAPIKey):
+		code = codes.Unauthenticated
+	case errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrExpired):
+		code = codes.FailedPrecondition
+	case errors.Is(err, domain.ErrRateLimited), errors.Is(err, domain.ErrQuotaExceeded):
+		code = codes.ResourceExhausted
+	case errors.Is(err, domain.ErrUpstreamUnavailable), errors.Is(err, domain.ErrStaleData):
+		code = codes.Unavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	default:
+		code = codes.Internal
+	}
+
+	return status.Error(code, problem.Message(err))
+}
+
+// codeName renders a code the way the gRPC spec spells it, e.g. INVALID_ARGUMENT.
+func codeName(code codes.Code) string {
+	name := code.String()
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' && name[i-1] >= 'a' && name[i-1] <= 'z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
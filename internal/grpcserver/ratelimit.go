@@ -0,0 +1,99 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/service"
+	exchangev1 "exchange-rate-service/proto/exchange/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// RateLimit limits anonymous calls with a token bucket per peer IP, the way
+// the HTTP API limits anonymous requests. Costs weighs methods by the HTTP
+// route they mirror, e.g. /api/v1/latest for GetLatestRates, so
+// RATE_LIMIT_COSTS applies to both. A nil Limiter disables it.
+type RateLimit struct {
+	Limiter *service.RateLimiter
+	Limit   int
+	Window  time.Duration
+	Costs   map[string]int
+}
+
+// methodRoutes maps each method onto the HTTP route it mirrors.
+var methodRoutes = map[string]string{
+	exchangev1.ExchangeService_Convert_FullMethodName:            "/api/v1/convert",
+	exchangev1.ExchangeService_BatchConvert_FullMethodName:       "/api/v1/convert/batch",
+	exchangev1.ExchangeService_GetLatestRates_FullMethodName:     "/api/v1/latest",
+	exchangev1.ExchangeService_GetHistoricalRates_FullMethodName: "/api/v1/historical",
+	exchangev1.ExchangeService_ListCurrencies_FullMethodName:     "/api/v1/currencies",
+	exchangev1.ExchangeService_WatchRates_FullMethodName:         "/api/v1/ws",
+}
+
+// costFunc returns how many tokens a call to method with req consumes. req
+// is nil for streams.
+type costFunc func(method string, req interface{}) int
+
+func methodCost(costs map[string]int) costFunc {
+	return func(method string, req interface{}) int {
+		var start, end string
+		if historical, ok := req.(*exchangev1.GetHistoricalRatesRequest); ok {
+			start, end = historical.GetStartDate(), historical.GetEndDate()
+		}
+		return service.RouteCost(costs, methodRoutes[method], start, end)
+	}
+}
+
+func unaryRateLimit(limit RateLimit) grpc.UnaryServerInterceptor {
+	cost := methodCost(limit.Costs)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := limit.take(ctx, cost(info.FullMethod, req)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamRateLimit(limit RateLimit) grpc.StreamServerInterceptor {
+	cost := methodCost(limit.Costs)
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := limit.take(stream.Context(), cost(info.FullMethod, nil)); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// take charges cost to the bucket of the caller's IP.
+func (l RateLimit) take(ctx context.Context, cost int) error {
+	result := l.Limiter.Take("ip_"+peerIP(ctx), cost, l.Limit, l.Window)
+	if !result.Allowed {
+		return rateLimited(ctx, result, domain.ErrRateLimited)
+	}
+	return nil
+}
+
+// rateLimited returns err as a status, telling the caller when to retry in
+// the retry-after header.
+func rateLimited(ctx context.Context, result domain.RateLimitResult, err error) error {
+	seconds := int((result.RetryAfter + time.Second - 1) / time.Second)
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+	return toStatus(err)
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
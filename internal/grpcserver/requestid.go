@@ -0,0 +1,71 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"exchange-rate-service/internal/requestid"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey is the metadata spelling of the X-Request-ID header.
+var requestIDKey = strings.ToLower(requestid.Header)
+
+// unaryRequestID mirrors the HTTP RequestID and Logger middleware: it tags
+// the call with the caller's x-request-id (or a fresh one), returns it in the
+// response header and logs the call through the request-scoped logger.
+func unaryRequestID(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = scopeRequest(ctx, logger)
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func streamRequestID(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := scopeRequest(stream.Context(), logger)
+		start := time.Now()
+
+		err := handler(srv, &scopedStream{ServerStream: stream, ctx: ctx})
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func scopeRequest(ctx context.Context, logger *zap.Logger) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := first(md.Get(requestIDKey))
+	if !requestid.Valid(id) {
+		id = requestid.New()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+	return requestid.Scope(ctx, id, logger)
+}
+
+func logCall(ctx context.Context, logger *zap.Logger, method string, start time.Time, err error) {
+	requestid.Logger(ctx, logger).Info("gRPC Request",
+		zap.String("method", method),
+		zap.String("code", codeName(status.Code(err))),
+		zap.Duration("latency", time.Since(start)),
+	)
+}
+
+// scopedStream swaps the stream context for the request-scoped one.
+type scopedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *scopedStream) Context() context.Context {
+	return s.ctx
+}
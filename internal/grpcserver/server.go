@@ -0,0 +1,313 @@
+package grpcserver
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/internal/utils"
+	exchangev1 "exchange-rate-service/proto/exchange/v1"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const maxBatchSize = 100
+
+type Server struct {
+	exchangev1.UnimplementedExchangeServiceServer
+
+	service *service.ExchangeService
+	logger  *zap.Logger
+}
+
+// New returns a gRPC server with the exchange service registered. Calls are
+// tagged with an x-request-id the same way HTTP requests are. When
+// apiKeys is non-nil every call must carry a valid key in the x-api-key or
+// authorization metadata and is charged to that key; otherwise calls are
+// charged to the caller's IP under rateLimit. Both use the HTTP route costs.
+func New(exchangeService *service.ExchangeService, logger *zap.Logger, apiKeys *service.APIKeyService, rateLimit RateLimit, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unaryRequestID(logger)),
+		grpc.ChainStreamInterceptor(streamRequestID(logger)),
+	)
+	if apiKeys != nil {
+		cost := methodCost(rateLimit.Costs)
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(unaryAuth(apiKeys, cost)),
+			grpc.ChainStreamInterceptor(streamAuth(apiKeys, cost)),
+		)
+	} else if rateLimit.Limiter != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(unaryRateLimit(rateLimit)),
+			grpc.ChainStreamInterceptor(streamRateLimit(rateLimit)),
+		)
+	}
+
+	srv := grpc.NewServer(opts...)
+	exchangev1.RegisterExchangeServiceServer(srv, &Server{
+		service: exchangeService,
+		logger:  logger,
+	})
+
+	return srv
+}
+
+func (s *Server) Convert(ctx context.Context, req *exchangev1.ConvertRequest) (*exchangev1.ConvertResponse, error) {
+	if req.GetFrom() == "" || req.GetTo() == "" {
+		return nil, status.Error(codes.InvalidArgument, "from and to currencies are required")
+	}
+
+	result, err := s.service.ConvertCurrency(ctx, conversionRequest(req))
+	if err != nil {
+		return nil, s.statusError(ctx, "Conversion failed", err)
+	}
+
+	return conversionResponse(result), nil
+}
+
+func (s *Server) BatchConvert(ctx context.Context, req *exchangev1.BatchConvertRequest) (*exchangev1.BatchConvertResponse, error) {
+	if len(req.GetConversions()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one conversion is required")
+	}
+	if len(req.GetConversions()) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d conversions are allowed per batch", maxBatchSize)
+	}
+
+	reqs := make([]domain.ConversionRequest, len(req.GetConversions()))
+	for i, conversion := range req.GetConversions() {
+		reqs[i] = *conversionRequest(conversion)
+	}
+
+	results, errs := s.service.BatchConvert(ctx, reqs)
+
+	resp := &exchangev1.BatchConvertResponse{
+		Results: make([]*exchangev1.BatchConvertResult, len(reqs)),
+	}
+	for i := range reqs {
+		if errs[i] != nil {
+			st := status.Convert(toStatus(errs[i]))
+			resp.Results[i] = &exchangev1.BatchConvertResult{
+				Outcome: &exchangev1.BatchConvertResult_Error{
+					Error: &exchangev1.ConversionError{
+						Code:    codeName(st.Code()),
+						Message: st.Message(),
+					},
+				},
+			}
+			continue
+		}
+		resp.Results[i] = &exchangev1.BatchConvertResult{
+			Outcome: &exchangev1.BatchConvertResult_Result{Result: conversionResponse(results[i])},
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *Server) GetLatestRates(ctx context.Context, req *exchangev1.GetLatestRatesRequest) (*exchangev1.GetLatestRatesResponse, error) {
+	base := req.GetBaseCurrency()
+	if base == "" {
+		base = "USD"
+	}
+
+	result, err := s.service.GetLatestRates(ctx, base)
+	if err != nil {
+		return nil, s.statusError(ctx, "Failed to get latest rates", err)
+	}
+
+	return &exchangev1.GetLatestRatesResponse{
+		BaseCurrency: result.BaseCurrency,
+		Rates:        result.Rates,
+		Timestamp:    timestamppb.New(result.Timestamp),
+		Date:         result.Date,
+	}, nil
+}
+
+func (s *Server) GetHistoricalRates(ctx context.Context, req *exchangev1.GetHistoricalRatesRequest) (*exchangev1.GetHistoricalRatesResponse, error) {
+	if req.GetFrom() == "" || req.GetTo() == "" || req.GetStartDate() == "" || req.GetEndDate() == "" {
+		return nil, status.Error(codes.InvalidArgument, "from, to, start_date, and end_date are required")
+	}
+
+	result, err := s.service.GetHistoricalRates(ctx, req.GetFrom(), req.GetTo(), req.GetStartDate(), req.GetEndDate())
+	if err != nil {
+		return nil, s.statusError(ctx, "Failed to get historical rates", err)
+	}
+
+	dates := make([]string, 0, len(result.Rates))
+	for date := range result.Rates {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	rates := make([]*exchangev1.ExchangeRate, 0, len(dates))
+	for _, date := range dates {
+		rate := result.Rates[date]
+		rates = append(rates, &exchangev1.ExchangeRate{
+			FromCurrency: rate.FromCurrency,
+			ToCurrency:   rate.ToCurrency,
+			Rate:         rate.Rate,
+			Timestamp:    timestamppb.New(rate.Timestamp),
+			Date:         rate.Date,
+		})
+	}
+
+	return &exchangev1.GetHistoricalRatesResponse{
+		FromCurrency: result.FromCurrency,
+		ToCurrency:   result.ToCurrency,
+		Rates:        rates,
+		StartDate:    result.StartDate,
+		EndDate:      result.EndDate,
+	}, nil
+}
+
+func (s *Server) ListCurrencies(ctx context.Context, req *exchangev1.ListCurrenciesRequest) (*exchangev1.ListCurrenciesResponse, error) {
+	currencies := utils.EnabledCurrencies()
+
+	return &exchangev1.ListCurrenciesResponse{Currencies: currencies}, nil
+}
+
+func (s *Server) WatchRates(req *exchangev1.WatchRatesRequest, stream exchangev1.ExchangeService_WatchRatesServer) error {
+	pairs := make(map[string]bool)
+	for _, pair := range req.GetPairs() {
+		from, to, err := utils.ParsePair(pair)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		pairs[from+to] = true
+	}
+
+	updates, unsubscribe := s.service.SubscribeUpdates(64)
+	defer unsubscribe()
+
+	if len(pairs) > 0 {
+		snapshot, err := s.snapshot(stream.Context(), pairs)
+		if err != nil {
+			return s.statusError(stream.Context(), "Failed to load rate snapshot", err)
+		}
+		if err := stream.Send(snapshot); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return status.Error(codes.Unavailable, "rate updates closed")
+			}
+
+			var rates []*exchangev1.PairRate
+			for to, rate := range update.Changed {
+				pair := update.BaseCurrency + to
+				if len(pairs) > 0 && !pairs[pair] {
+					continue
+				}
+				rates = append(rates, &exchangev1.PairRate{
+					Pair:         pair,
+					FromCurrency: update.BaseCurrency,
+					ToCurrency:   to,
+					Rate:         rate,
+				})
+			}
+			if len(rates) == 0 {
+				continue
+			}
+			sort.Slice(rates, func(i, j int) bool { return rates[i].Pair < rates[j].Pair })
+
+			if err := stream.Send(&exchangev1.RateUpdate{
+				Type:      "UPDATE",
+				Rates:     rates,
+				Timestamp: timestamppb.New(update.Timestamp),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) snapshot(ctx context.Context, pairs map[string]bool) (*exchangev1.RateUpdate, error) {
+	latest := make(map[string]map[string]float64)
+	snapshot := &exchangev1.RateUpdate{Type: "SNAPSHOT", Timestamp: timestamppb.New(time.Now())}
+
+	for pair := range pairs {
+		from, to := pair[:3], pair[3:]
+		if _, exists := latest[from]; !exists {
+			result, err := s.service.GetLatestRates(ctx, from)
+			if err != nil {
+				return nil, err
+			}
+			latest[from] = result.Rates
+		}
+
+		if rate, exists := latest[from][to]; exists {
+			snapshot.Rates = append(snapshot.Rates, &exchangev1.PairRate{
+				Pair:         pair,
+				FromCurrency: from,
+				ToCurrency:   to,
+				Rate:         rate,
+			})
+		}
+	}
+	sort.Slice(snapshot.Rates, func(i, j int) bool { return snapshot.Rates[i].Pair < snapshot.Rates[j].Pair })
+
+	return snapshot, nil
+}
+
+func (s *Server) statusError(ctx context.Context, msg string, err error) error {
+	st := toStatus(err)
+	if status.Code(st) == codes.Internal {
+		requestid.Logger(ctx, s.logger).Error(msg, zap.Error(err))
+	}
+	return st
+}
+
+func conversionRequest(req *exchangev1.ConvertRequest) *domain.ConversionRequest {
+	return &domain.ConversionRequest{
+		From:   req.GetFrom(),
+		To:     req.GetTo(),
+		Amount: req.GetAmount(),
+		Date:   req.GetDate(),
+	}
+}
+
+func conversionResponse(result *domain.ConversionResponse) *exchangev1.ConvertResponse {
+	resp := &exchangev1.ConvertResponse{
+		Amount:       result.Amount,
+		FromCurrency: result.FromCurrency,
+		ToCurrency:   result.ToCurrency,
+		Rate:         result.Rate,
+		Date:         result.Date,
+		Timestamp:    timestamppb.New(result.Timestamp),
+		Source:       result.Source,
+	}
+	if p := result.Pricing; p != nil {
+		resp.Pricing = &exchangev1.Pricing{
+			Profile:     p.Profile,
+			MidRate:     p.MidRate,
+			BidRate:     p.BidRate,
+			AskRate:     p.AskRate,
+			MarkupBps:   p.MarkupBps,
+			GrossAmount: p.GrossAmount,
+			Fee:         p.Fee,
+		}
+	}
+	if c := result.Consensus; c != nil {
+		resp.Consensus = &exchangev1.Consensus{
+			Method:   c.Method,
+			Sources:  int32(c.Sources),
+			Queried:  int32(c.Queried),
+			Rejected: c.Rejected,
+			Spread:   c.Spread,
+		}
+	}
+	return resp
+}
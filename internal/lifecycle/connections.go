@@ -0,0 +1,97 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+)
+
+// Connections tracks long-lived connections taken over from the HTTP
+// server, such as WebSocket streams. http.Server.Shutdown neither closes nor
+// waits for hijacked connections, so the worker from Worker does both.
+type Connections struct {
+	mu      sync.Mutex
+	nextID  int
+	closers map[int]func()
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+func NewConnections() *Connections {
+	return &Connections{closers: make(map[int]func())}
+}
+
+// Track registers a connection that closeConn closes on shutdown. Call done
+// once the connection has finished. Once shutdown has begun Track returns
+// false, and the caller should close the connection itself.
+func (c *Connections) Track(closeConn func()) (done func(), ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return func() {}, false
+	}
+
+	id := c.nextID
+	c.nextID++
+	c.closers[id] = closeConn
+	c.wg.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			delete(c.closers, id)
+			c.mu.Unlock()
+			c.wg.Done()
+		})
+	}, true
+}
+
+// Len returns the number of connections open.
+func (c *Connections) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.closers)
+}
+
+// Worker returns a worker named name that, when stopped, closes every
+// tracked connection and waits for them to finish.
+func (c *Connections) Worker(name string) Worker {
+	return Worker{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+		Stop: c.Close,
+	}
+}
+
+// Close refuses new connections, closes the tracked ones and waits until
+// they have finished or ctx is done.
+func (c *Connections) Close(ctx context.Context) error {
+	c.mu.Lock()
+	c.closed = true
+	closers := make([]func(), 0, len(c.closers))
+	for _, closeConn := range c.closers {
+		closers = append(closers, closeConn)
+	}
+	c.mu.Unlock()
+
+	for _, closeConn := range closers {
+		closeConn()
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,183 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Worker is a long-running part of the service, such as a server or a
+// background loop.
+type Worker struct {
+	Name string
+	// Run does the work until ctx is cancelled, then returns. A Run that
+	// returns an error before then shuts the whole service down.
+	Run func(ctx context.Context) error
+	// Stop, if set, is called with the shutdown deadline once ctx is
+	// cancelled, for workers such as servers that are stopped by a call
+	// rather than by their context.
+	Stop func(ctx context.Context) error
+}
+
+type running struct {
+	Worker
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StopError lists the workers that had not returned by the shutdown
+// deadline.
+type StopError struct {
+	Workers []string
+}
+
+func (e *StopError) Error() string {
+	return "workers did not stop: " + strings.Join(e.Workers, ", ")
+}
+
+// Manager starts workers in the order they were added and stops them in
+// reverse, so servers added last stop taking requests before the workers
+// they depend on go away.
+type Manager struct {
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	workers []Worker
+	running []*running
+
+	failOnce sync.Once
+	failed   chan struct{}
+	err      error
+}
+
+func NewManager(logger *zap.Logger) *Manager {
+	return &Manager{
+		logger: logger,
+		failed: make(chan struct{}),
+	}
+}
+
+// Add registers w. Workers added after Start are not started.
+func (m *Manager) Add(w Worker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.workers = append(m.workers, w)
+}
+
+// Go registers a worker that runs fn and has nothing to report.
+func (m *Manager) Go(name string, fn func(ctx context.Context)) {
+	m.Add(Worker{Name: name, Run: func(ctx context.Context) error {
+		fn(ctx)
+		return nil
+	}})
+}
+
+// Start runs every worker in its own goroutine, in order. Workers see the
+// values of ctx but not its cancellation; only Stop stops them, so they stop
+// in order.
+func (m *Manager) Start(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ctx = context.WithoutCancel(ctx)
+	for _, w := range m.workers {
+		workerCtx, cancel := context.WithCancel(ctx)
+		r := &running{Worker: w, cancel: cancel, done: make(chan struct{})}
+		m.running = append(m.running, r)
+
+		go func() {
+			defer close(r.done)
+			if err := r.Run(workerCtx); err != nil {
+				m.fail(fmt.Errorf("%s: %w", r.Name, err))
+			}
+		}()
+		m.logger.Debug("Worker started", zap.String("worker", w.Name))
+	}
+}
+
+// Failed is closed once a worker returns an error.
+func (m *Manager) Failed() <-chan struct{} {
+	return m.failed
+}
+
+// Err returns the first error a worker returned.
+func (m *Manager) Err() error {
+	select {
+	case <-m.failed:
+		return m.err
+	default:
+		return nil
+	}
+}
+
+func (m *Manager) fail(err error) {
+	m.failOnce.Do(func() {
+		m.err = err
+		close(m.failed)
+	})
+	m.logger.Error("Worker failed", zap.Error(err))
+}
+
+// Stop cancels the workers in reverse order, waiting for each to return
+// before cancelling the next. Once ctx is done the rest are cancelled
+// without waiting, and Stop returns a *StopError naming every worker still
+// running.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	workers := m.running
+	m.running = nil
+	m.mu.Unlock()
+
+	var stuck []string
+	for i := len(workers) - 1; i >= 0; i-- {
+		w := workers[i]
+		w.cancel()
+		if ctx.Err() != nil {
+			select {
+			case <-w.done:
+			default:
+				stuck = append(stuck, w.Name)
+			}
+			continue
+		}
+
+		if w.Stop != nil {
+			if err := w.Stop(ctx); err != nil {
+				m.logger.Warn("Worker stop failed", zap.String("worker", w.Name), zap.Error(err))
+			}
+		}
+		select {
+		case <-w.done:
+			m.logger.Debug("Worker stopped", zap.String("worker", w.Name))
+		case <-ctx.Done():
+			stuck = append(stuck, w.Name)
+		}
+	}
+
+	if len(stuck) > 0 {
+		m.logger.Error("Workers did not stop in time", zap.Strings("workers", stuck))
+		return &StopError{Workers: stuck}
+	}
+	return nil
+}
+
+// Run starts the workers and stops them, allowing timeout, once ctx is done
+// or a worker fails. It returns the worker's error and any *StopError.
+func (m *Manager) Run(ctx context.Context, timeout time.Duration) error {
+	m.Start(ctx)
+
+	select {
+	case <-ctx.Done():
+	case <-m.failed:
+	}
+	m.logger.Info("Shutting down...")
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return errors.Join(m.Err(), m.Stop(stopCtx))
+}
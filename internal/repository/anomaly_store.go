@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"sync"
+
+	"exchange-rate-service/internal/domain"
+)
+
+// AnomalyRepository stores rejected rates in a JSON file, least recently
+// seen first. It keeps at most limit anomalies, dropping reviewed ones
+// before unreviewed ones; a limit of zero keeps them all. With an empty path
+// they only live in memory.
+type AnomalyRepository struct {
+	mu        sync.RWMutex
+	path      string
+	limit     int
+	anomalies []domain.RateAnomaly
+}
+
+func NewAnomalyRepository(path string, limit int) (*AnomalyRepository, error) {
+	repo := &AnomalyRepository{path: path, limit: limit}
+
+	if path != "" {
+		if err := loadJSONFile(path, &repo.anomalies); err != nil {
+			return nil, err
+		}
+	}
+	repo.trim()
+
+	return repo, nil
+}
+
+func (r *AnomalyRepository) Record(anomaly *domain.RateAnomaly) (*domain.RateAnomaly, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded := *anomaly
+	for i, existing := range r.anomalies {
+		if existing.ReviewedAt == nil && existing.Pair == anomaly.Pair && existing.Check == anomaly.Check && existing.Rate == anomaly.Rate {
+			existing.Count = max(existing.Count, 1) + 1
+			existing.LastSeenAt = anomaly.DetectedAt
+			existing.LastGood = anomaly.LastGood
+			recorded = existing
+			r.anomalies = append(r.anomalies[:i], r.anomalies[i+1:]...)
+			break
+		}
+	}
+
+	r.anomalies = append(r.anomalies, recorded)
+	r.trim()
+	if err := r.persist(); err != nil {
+		return nil, err
+	}
+	return &recorded, nil
+}
+
+func (r *AnomalyRepository) Get(id string) (*domain.RateAnomaly, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, anomaly := range r.anomalies {
+		if anomaly.ID == id {
+			return &anomaly, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *AnomalyRepository) List() ([]domain.RateAnomaly, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	anomalies := make([]domain.RateAnomaly, len(r.anomalies))
+	copy(anomalies, r.anomalies)
+	return anomalies, nil
+}
+
+func (r *AnomalyRepository) Update(anomaly *domain.RateAnomaly) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.anomalies {
+		if r.anomalies[i].ID == anomaly.ID {
+			r.anomalies[i] = *anomaly
+			return r.persist()
+		}
+	}
+	return domain.ErrNotFound
+}
+
+// trim drops anomalies beyond the limit, oldest reviewed ones first.
+// Callers hold the lock, or own the repository.
+func (r *AnomalyRepository) trim() {
+	if r.limit <= 0 {
+		return
+	}
+
+	for excess := len(r.anomalies) - r.limit; excess > 0; excess-- {
+		drop := 0
+		for i := range r.anomalies {
+			if r.anomalies[i].ReviewedAt != nil {
+				drop = i
+				break
+			}
+		}
+		r.anomalies = append(r.anomalies[:drop], r.anomalies[drop+1:]...)
+	}
+}
+
+func (r *AnomalyRepository) persist() error {
+	if r.path == "" {
+		return nil
+	}
+	return saveJSONFile(r.path, r.anomalies)
+}
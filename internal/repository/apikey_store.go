@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"exchange-rate-service/internal/domain"
+)
+
+// APIKeyRepository stores hashed API keys in a JSON file. With an empty path
+// the keys only live in memory.
+type APIKeyRepository struct {
+	mu     sync.RWMutex
+	path   string
+	keys   map[string]domain.APIKey
+	byHash map[string]string
+}
+
+func NewAPIKeyRepository(path string) (*APIKeyRepository, error) {
+	repo := &APIKeyRepository{
+		path:   path,
+		keys:   make(map[string]domain.APIKey),
+		byHash: make(map[string]string),
+	}
+
+	if path != "" {
+		if err := loadJSONFile(path, &repo.keys); err != nil {
+			return nil, err
+		}
+		if repo.keys == nil {
+			repo.keys = make(map[string]domain.APIKey)
+		}
+		for id, key := range repo.keys {
+			repo.byHash[key.Hash] = id
+		}
+	}
+
+	return repo, nil
+}
+
+func (r *APIKeyRepository) Create(key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[key.ID] = *key
+	r.byHash[key.Hash] = key.ID
+	return r.persist()
+}
+
+func (r *APIKeyRepository) Get(id string) (*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, exists := r.keys[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) GetByHash(hash string) (*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, exists := r.byHash[hash]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	key := r.keys[id]
+	return &key, nil
+}
+
+func (r *APIKeyRepository) List() ([]domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]domain.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Update(key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.keys[key.ID]
+	if !exists {
+		return domain.ErrNotFound
+	}
+	delete(r.byHash, existing.Hash)
+
+	r.keys[key.ID] = *key
+	r.byHash[key.Hash] = key.ID
+	return r.persist()
+}
+
+func (r *APIKeyRepository) persist() error {
+	if r.path == "" {
+		return nil
+	}
+	return saveJSONFile(r.path, r.keys)
+}
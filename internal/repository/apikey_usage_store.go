@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+)
+
+// usageCompactEvery is how often Save rewrites the usage file without the
+// days past retention.
+const usageCompactEvery = time.Hour
+
+// usageRecord is one line of the usage file: a key's counters for a day.
+type usageRecord struct {
+	KeyID string `json:"key_id"`
+	domain.APIKeyUsage
+}
+
+// APIKeyUsageRepository keeps per-key daily usage counters in a file of JSON
+// lines, apart from the rates cache so clearing the cache cannot reset a
+// quota. Every save appends the day's counters; the last line for a key and
+// day wins. The file is rewritten on load and at most hourly to drop days
+// older than retention. A zero retention keeps everything. With an empty
+// path the counters only live in memory.
+type APIKeyUsageRepository struct {
+	mu        sync.RWMutex
+	path      string
+	retention time.Duration
+	compacted time.Time
+	usage     map[string]map[string]domain.APIKeyUsage
+}
+
+func NewAPIKeyUsageRepository(path string, retention time.Duration) (*APIKeyUsageRepository, error) {
+	repo := &APIKeyUsageRepository{
+		path:      path,
+		retention: retention,
+		usage:     make(map[string]map[string]domain.APIKeyUsage),
+	}
+
+	if path != "" {
+		if err := repo.load(); err != nil {
+			return nil, err
+		}
+		if err := repo.compact(); err != nil {
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+// Get returns the key's counters for date, all zero when nothing was
+// recorded.
+func (r *APIKeyUsageRepository) Get(keyID, date string) (*domain.APIKeyUsage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	usage, exists := r.usage[keyID][date]
+	if !exists {
+		usage = domain.APIKeyUsage{Date: date}
+	}
+	return &usage, nil
+}
+
+func (r *APIKeyUsageRepository) Save(keyID string, usage *domain.APIKeyUsage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.set(usageRecord{KeyID: keyID, APIKeyUsage: *usage})
+
+	if time.Since(r.compacted) >= usageCompactEvery {
+		return r.compact()
+	}
+	if r.path == "" {
+		return nil
+	}
+	return r.append(usageRecord{KeyID: keyID, APIKeyUsage: *usage})
+}
+
+// set stores record in memory. Callers hold the lock.
+func (r *APIKeyUsageRepository) set(record usageRecord) {
+	days, exists := r.usage[record.KeyID]
+	if !exists {
+		days = make(map[string]domain.APIKeyUsage)
+		r.usage[record.KeyID] = days
+	}
+	days[record.Date] = record.APIKeyUsage
+}
+
+// prune drops the days past retention. Callers hold the lock.
+func (r *APIKeyUsageRepository) prune() {
+	if r.retention <= 0 {
+		return
+	}
+
+	cutoff := time.Now().UTC().Add(-r.retention).Format("2006-01-02")
+	for keyID, days := range r.usage {
+		for date := range days {
+			if date < cutoff {
+				delete(days, date)
+			}
+		}
+		if len(days) == 0 {
+			delete(r.usage, keyID)
+		}
+	}
+}
+
+// load reads the usage file. A torn last line, left by a crash while
+// appending, is ignored.
+func (r *APIKeyUsageRepository) load() error {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var record usageRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			if bytes.HasSuffix(data, scanner.Bytes()) {
+				break
+			}
+			return fmt.Errorf("%s line %d: %w", r.path, line, err)
+		}
+		r.set(record)
+	}
+	return scanner.Err()
+}
+
+// compact prunes and rewrites the whole usage file. Callers hold the lock.
+func (r *APIKeyUsageRepository) compact() error {
+	r.prune()
+	r.compacted = time.Now()
+	if r.path == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for keyID, days := range r.usage {
+		for _, usage := range days {
+			if err := encoder.Encode(usageRecord{KeyID: keyID, APIKeyUsage: usage}); err != nil {
+				return err
+			}
+		}
+	}
+	return writeFileAtomic(r.path, buf.Bytes())
+}
+
+// append adds record to the end of the usage file. Callers hold the lock.
+func (r *APIKeyUsageRepository) append(record usageRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
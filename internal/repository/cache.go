@@ -1,15 +1,21 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"exchange-rate-service/internal/domain"
 )
 
 type CacheItem struct {
 	Value      interface{}
 	Expiration time.Time
+	StoredAt   time.Time
 }
 
 type CacheRepository struct {
@@ -18,22 +24,20 @@ type CacheRepository struct {
 }
 
 func NewCacheRepository() *CacheRepository {
-	cache := &CacheRepository{
+	return &CacheRepository{
 		items: make(map[string]CacheItem),
 	}
-
-	go cache.cleanup()
-
-	return cache
 }
 
 func (c *CacheRepository) Set(key string, value interface{}, expiration time.Duration) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	now := time.Now()
 	c.items[key] = CacheItem{
 		Value:      value,
-		Expiration: time.Now().Add(expiration),
+		Expiration: now.Add(expiration),
+		StoredAt:   now,
 	}
 
 	return nil
@@ -49,7 +53,6 @@ func (c *CacheRepository) Get(key string, dest interface{}) error {
 	}
 
 	if time.Now().After(item.Expiration) {
-		delete(c.items, key)
 		return errors.New("key expired")
 	}
 
@@ -77,18 +80,89 @@ func (c *CacheRepository) Clear() error {
 	return nil
 }
 
-func (c *CacheRepository) cleanup() {
-	ticker := time.NewTicker(5 * time.Minute)
+// Entries lists the live entries whose key starts with prefix, sorted by key
+// and without their values.
+func (c *CacheRepository) Entries(prefix string) []domain.CacheEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	entries := []domain.CacheEntry{}
+	for key, item := range c.items {
+		if strings.HasPrefix(key, prefix) && now.Before(item.Expiration) {
+			entries = append(entries, cacheEntry(key, item, now))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	return entries
+}
+
+func (c *CacheRepository) Entry(key string) (*domain.CacheEntry, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	item, exists := c.items[key]
+	if !exists || now.After(item.Expiration) {
+		return nil, domain.ErrNotFound
+	}
+
+	entry := cacheEntry(key, item, now)
+	entry.Value = item.Value
+	return &entry, nil
+}
+
+// DeletePrefix removes every entry whose key starts with prefix and returns
+// how many were removed.
+func (c *CacheRepository) DeletePrefix(prefix string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deleted := 0
+	for key := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.items, key)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func cacheEntry(key string, item CacheItem, now time.Time) domain.CacheEntry {
+	return domain.CacheEntry{
+		Key:        key,
+		StoredAt:   item.StoredAt,
+		ExpiresAt:  item.Expiration,
+		AgeSeconds: int64(now.Sub(item.StoredAt).Seconds()),
+		TTLSeconds: int64(item.Expiration.Sub(now).Seconds()),
+	}
+}
+
+// StartJanitor evicts expired items every interval until ctx is done. Get
+// never returns expired items, so the janitor only reclaims memory.
+func (c *CacheRepository) StartJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		c.mu.Lock()
-		now := time.Now()
-		for key, item := range c.items {
-			if now.After(item.Expiration) {
-				delete(c.items, key)
-			}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.evictExpired()
+		}
+	}
+}
+
+func (c *CacheRepository) evictExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, item := range c.items {
+		if now.After(item.Expiration) {
+			delete(c.items, key)
 		}
-		c.mu.Unlock()
 	}
 }
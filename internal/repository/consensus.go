@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ConsensusMedian       = "median"
+	ConsensusWeightedMean = "weighted_mean"
+)
+
+// ConsensusSource is one provider taking part in a consensus. A zero weight
+// counts as one.
+type ConsensusSource struct {
+	Name   string
+	Repo   domain.ExchangeRepository
+	Weight float64
+}
+
+type ConsensusOptions struct {
+	// Method is ConsensusMedian or ConsensusWeightedMean.
+	Method string
+	// MaxDeviation is the largest accepted distance from the median of all
+	// quotes, as a fraction of it (0.02 is 2%).
+	MaxDeviation float64
+	// MinSources is how many quotes must agree for a rate to be served.
+	MinSources int
+}
+
+// ConsensusRepository queries every source concurrently and serves the rate
+// they agree on. Non-positive quotes and quotes further than MaxDeviation
+// from the median are dropped before the remaining ones are combined.
+type ConsensusRepository struct {
+	sources []ConsensusSource
+	options ConsensusOptions
+	logger  *zap.Logger
+}
+
+type sourceQuote struct {
+	source ConsensusSource
+	rate   *domain.ExchangeRate
+	rates  map[string]float64
+	err    error
+}
+
+func NewConsensusRepository(sources []ConsensusSource, options ConsensusOptions, logger *zap.Logger) (*ConsensusRepository, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("consensus needs at least one source")
+	}
+	if options.Method == "" {
+		options.Method = ConsensusMedian
+	}
+	if options.Method != ConsensusMedian && options.Method != ConsensusWeightedMean {
+		return nil, fmt.Errorf("unknown consensus method: %s", options.Method)
+	}
+	if options.MaxDeviation <= 0 {
+		return nil, fmt.Errorf("consensus max deviation must be positive")
+	}
+	if options.MinSources < 1 {
+		options.MinSources = 1
+	}
+	if options.MinSources > len(sources) {
+		return nil, fmt.Errorf("consensus needs %d sources but only %d are configured", options.MinSources, len(sources))
+	}
+
+	for i := range sources {
+		if sources[i].Weight <= 0 {
+			sources[i].Weight = 1
+		}
+	}
+
+	return &ConsensusRepository{
+		sources: sources,
+		options: options,
+		logger:  logger,
+	}, nil
+}
+
+func (r *ConsensusRepository) GetLatestRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	quotes := r.query(ctx, from+to, func(repo domain.ExchangeRepository) (*domain.ExchangeRate, map[string]float64, error) {
+		rate, err := repo.GetLatestRate(ctx, from, to)
+		return rate, nil, err
+	})
+	return r.agreeOnRate(ctx, from+to, quotes)
+}
+
+func (r *ConsensusRepository) GetHistoricalRate(ctx context.Context, from, to, date string) (*domain.ExchangeRate, error) {
+	quotes := r.query(ctx, from+to, func(repo domain.ExchangeRepository) (*domain.ExchangeRate, map[string]float64, error) {
+		rate, err := repo.GetHistoricalRate(ctx, from, to, date)
+		return rate, nil, err
+	})
+	return r.agreeOnRate(ctx, from+to, quotes)
+}
+
+// GetAllLatestRates agrees on each currency separately. Currencies without
+// consensus are left out of the table.
+func (r *ConsensusRepository) GetAllLatestRates(ctx context.Context, baseCurrency string) (map[string]float64, error) {
+	quotes := r.query(ctx, baseCurrency, func(repo domain.ExchangeRepository) (*domain.ExchangeRate, map[string]float64, error) {
+		rates, err := repo.GetAllLatestRates(ctx, baseCurrency)
+		return nil, rates, err
+	})
+
+	currencies := make(map[string]bool)
+	for _, quote := range quotes {
+		for currency := range quote.rates {
+			currencies[currency] = true
+		}
+	}
+
+	rates := make(map[string]float64, len(currencies))
+	for currency := range currencies {
+		values := make(map[string]float64)
+		for _, quote := range quotes {
+			if rate, ok := quote.rates[currency]; ok {
+				values[quote.source.Name] = rate
+			}
+		}
+
+		rate, _, err := r.agree(ctx, baseCurrency+currency, values)
+		if err != nil {
+			continue
+		}
+		rates[currency] = rate
+	}
+
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: no consensus on %s rates", domain.ErrUpstreamUnavailable, baseCurrency)
+	}
+	return rates, nil
+}
+
+// query calls fetch on every source concurrently, logging the sources that
+// fail.
+func (r *ConsensusRepository) query(ctx context.Context, subject string, fetch func(domain.ExchangeRepository) (*domain.ExchangeRate, map[string]float64, error)) []sourceQuote {
+	quotes := make([]sourceQuote, len(r.sources))
+
+	var wg sync.WaitGroup
+	for i, source := range r.sources {
+		wg.Add(1)
+		go func(i int, source ConsensusSource) {
+			defer wg.Done()
+			quotes[i].source = source
+			quotes[i].rate, quotes[i].rates, quotes[i].err = fetch(source.Repo)
+		}(i, source)
+	}
+	wg.Wait()
+
+	for _, quote := range quotes {
+		if quote.err != nil {
+			requestid.Logger(ctx, r.logger).Warn("Consensus source failed",
+				zap.String("source", quote.source.Name),
+				zap.String("subject", subject),
+				zap.Error(quote.err))
+		}
+	}
+	return quotes
+}
+
+func (r *ConsensusRepository) agreeOnRate(ctx context.Context, pair string, quotes []sourceQuote) (*domain.ExchangeRate, error) {
+	values := make(map[string]float64)
+	var template *domain.ExchangeRate
+	for _, quote := range quotes {
+		if quote.err != nil {
+			continue
+		}
+		values[quote.source.Name] = quote.rate.Rate
+		if template == nil {
+			template = quote.rate
+		}
+	}
+
+	rate, consensus, err := r.agree(ctx, pair, values)
+	if err != nil {
+		return nil, err
+	}
+
+	result := *template
+	result.Rate = rate
+	result.Consensus = consensus
+	return &result, nil
+}
+
+// agree combines the quotes per source name into one rate.
+func (r *ConsensusRepository) agree(ctx context.Context, pair string, values map[string]float64) (float64, *domain.RateConsensus, error) {
+	consensus := &domain.RateConsensus{
+		Method:  r.options.Method,
+		Queried: len(r.sources),
+	}
+
+	var valid []float64
+	for name, value := range values {
+		if usableQuote(value) {
+			valid = append(valid, value)
+		} else {
+			consensus.Rejected = append(consensus.Rejected, name)
+		}
+	}
+	if len(valid) == 0 {
+		return 0, nil, fmt.Errorf("%w: no usable quotes for %s", domain.ErrUpstreamUnavailable, pair)
+	}
+	mid := median(valid)
+
+	var accepted []ConsensusSource
+	var acceptedValues []float64
+	for _, source := range r.sources {
+		value, ok := values[source.Name]
+		if !ok || !usableQuote(value) {
+			continue
+		}
+		if math.Abs(value-mid)/mid > r.options.MaxDeviation {
+			consensus.Rejected = append(consensus.Rejected, source.Name)
+			continue
+		}
+		accepted = append(accepted, source)
+		acceptedValues = append(acceptedValues, value)
+	}
+	sort.Strings(consensus.Rejected)
+
+	if len(consensus.Rejected) > 0 {
+		requestid.Logger(ctx, r.logger).Warn("Rejected outlier quotes",
+			zap.String("pair", pair),
+			zap.Strings("sources", consensus.Rejected),
+			zap.Float64("median", mid))
+	}
+
+	consensus.Sources = len(accepted)
+	if consensus.Sources < r.options.MinSources {
+		return 0, nil, fmt.Errorf("%w: only %d of %d sources agree on %s", domain.ErrUpstreamUnavailable, consensus.Sources, r.options.MinSources, pair)
+	}
+
+	low, high := acceptedValues[0], acceptedValues[0]
+	var weighted, weights float64
+	for i, value := range acceptedValues {
+		low, high = math.Min(low, value), math.Max(high, value)
+		weighted += value * accepted[i].Weight
+		weights += accepted[i].Weight
+	}
+	consensus.Spread = high - low
+
+	if r.options.Method == ConsensusWeightedMean {
+		return weighted / weights, consensus, nil
+	}
+	return median(acceptedValues), consensus, nil
+}
+
+// usableQuote rejects zero, negative, infinite and NaN quotes.
+func usableQuote(value float64) bool {
+	return value > 0 && !math.IsInf(value, 1)
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
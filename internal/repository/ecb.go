@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ecbDailyFeed   = "/eurofxref-daily.xml"
+	ecbHistoryFeed = "/eurofxref-hist-90d.xml"
+
+	// ecbFeedTTL bounds how long a parsed feed is reused. The ECB publishes
+	// once per working day, so refetching more often gains nothing.
+	ecbFeedTTL = time.Hour
+)
+
+// ECBRepository serves European Central Bank reference rates. The ECB quotes
+// every currency against EUR; other bases are derived by cross rates.
+// Historical rates come from the 90-day feed, and a date without a
+// publication (weekends, TARGET holidays) uses the last one before it.
+type ECBRepository struct {
+	baseURL string
+	client  *http.Client
+	logger  *zap.Logger
+
+	mu    sync.Mutex
+	feeds map[string]*ecbFeed
+}
+
+// ecbFeed is a parsed feed: EUR-based rates per publication date, with the
+// dates sorted ascending.
+type ecbFeed struct {
+	rates     map[string]map[string]float64
+	dates     []string
+	fetchedAt time.Time
+}
+
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string  `xml:"currency,attr"`
+			Rate     float64 `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+func NewECBRepository(baseURL string, logger *zap.Logger) *ECBRepository {
+	if baseURL == "" {
+		baseURL = "https://www.ecb.europa.eu/stats/eurofxref"
+	}
+
+	return &ECBRepository{
+		baseURL: baseURL,
+		client:  newUpstreamClient(http.DefaultTransport),
+		logger:  logger,
+		feeds:   make(map[string]*ecbFeed),
+	}
+}
+
+func (r *ECBRepository) GetLatestRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	feed, err := r.feed(ctx, ecbDailyFeed)
+	if err != nil {
+		return nil, err
+	}
+
+	return feed.rate(from, to, feed.dates[len(feed.dates)-1])
+}
+
+func (r *ECBRepository) GetHistoricalRate(ctx context.Context, from, to, date string) (*domain.ExchangeRate, error) {
+	feed, err := r.feed(ctx, ecbHistoryFeed)
+	if err != nil {
+		return nil, err
+	}
+
+	published, ok := feed.publishedOn(date)
+	if !ok {
+		return nil, fmt.Errorf("%w: no ECB reference rate on or before %s", domain.ErrNotFound, date)
+	}
+
+	rate, err := feed.rate(from, to, published)
+	if err != nil {
+		return nil, err
+	}
+	rate.Date = date
+	return rate, nil
+}
+
+func (r *ECBRepository) GetAllLatestRates(ctx context.Context, baseCurrency string) (map[string]float64, error) {
+	feed, err := r.feed(ctx, ecbDailyFeed)
+	if err != nil {
+		return nil, err
+	}
+
+	eur := feed.rates[feed.dates[len(feed.dates)-1]]
+	base, ok := eur[baseCurrency]
+	if !ok {
+		return nil, fmt.Errorf("%w: ECB publishes no rate for %s", domain.ErrNotFound, baseCurrency)
+	}
+
+	rates := make(map[string]float64)
+	for currency := range domain.SupportedCurrencies {
+		if rate, ok := eur[currency]; ok && currency != baseCurrency {
+			rates[currency] = rate / base
+		}
+	}
+	return rates, nil
+}
+
+// feed returns the parsed feed at path, fetching it when it is missing or
+// older than ecbFeedTTL.
+func (r *ECBRepository) feed(ctx context.Context, path string) (*ecbFeed, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if feed, ok := r.feeds[path]; ok && time.Since(feed.fetchedAt) < ecbFeedTTL {
+		return feed, nil
+	}
+
+	feed, err := r.fetch(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	r.feeds[path] = feed
+	return feed, nil
+}
+
+func (r *ECBRepository) fetch(ctx context.Context, path string) (*ecbFeed, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", r.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	requestid.Logger(ctx, r.logger).Debug("Upstream request",
+		zap.String("path", path),
+		zap.Int("status", resp.StatusCode),
+		zap.Duration("latency", time.Since(start)),
+	)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ECB feed %s returned status %d", path, resp.StatusCode)
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("parse ECB feed %s: %w", path, err)
+	}
+
+	feed := &ecbFeed{
+		rates:     make(map[string]map[string]float64, len(envelope.Days)),
+		fetchedAt: time.Now(),
+	}
+	for _, day := range envelope.Days {
+		rates := map[string]float64{"EUR": 1}
+		for _, rate := range day.Rates {
+			if rate.Rate > 0 {
+				rates[rate.Currency] = rate.Rate
+			}
+		}
+		feed.rates[day.Time] = rates
+		feed.dates = append(feed.dates, day.Time)
+	}
+	if len(feed.dates) == 0 {
+		return nil, fmt.Errorf("ECB feed %s has no rates", path)
+	}
+	sort.Strings(feed.dates)
+
+	return feed, nil
+}
+
+// publishedOn returns the last publication date on or before date.
+func (f *ecbFeed) publishedOn(date string) (string, bool) {
+	i := sort.SearchStrings(f.dates, date)
+	if i < len(f.dates) && f.dates[i] == date {
+		return date, true
+	}
+	if i == 0 {
+		return "", false
+	}
+	return f.dates[i-1], true
+}
+
+// rate derives from/to from the EUR rates published on date.
+func (f *ecbFeed) rate(from, to, date string) (*domain.ExchangeRate, error) {
+	eur := f.rates[date]
+	fromRate, fromOK := eur[from]
+	toRate, toOK := eur[to]
+	if !fromOK || !toOK {
+		return nil, fmt.Errorf("%w: ECB publishes no rate for %s to %s", domain.ErrNotFound, from, to)
+	}
+
+	published, _ := time.Parse("2006-01-02", date)
+	return &domain.ExchangeRate{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         toRate / fromRate,
+		Timestamp:    published,
+		Date:         date,
+	}, nil
+}
@@ -6,16 +6,32 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.uber.org/zap"
 )
 
+// ExchangeAPIRepository fetches rates from exchangerate.host. When the
+// provider fails, it serves rates from the fallback repository if one is set
+// and its rates are not older than the fallback max age.
 type ExchangeAPIRepository struct {
-	apiKey  string
-	baseURL string
-	client  *http.Client
+	baseURL        string
+	client         *http.Client
+	fallback       domain.ExchangeRepository
+	fallbackMaxAge time.Duration
+	logger         *zap.Logger
+}
+
+// publishedRepository is a fallback that knows when its latest rates were
+// published.
+type publishedRepository interface {
+	LatestPublished() (time.Time, bool)
 }
 
 type ExchangeHostResponse struct {
@@ -60,60 +76,149 @@ type APIError struct {
 	Info string `json:"info"`
 }
 
-func NewExchangeAPIRepository(apiKey, baseURL string) *ExchangeAPIRepository {
+func (e *APIError) Error() string {
+	if e == nil {
+		return "provider reported failure"
+	}
+	return fmt.Sprintf("provider error %d: %s", e.Code, e.Info)
+}
+
+func NewExchangeAPIRepository(apiKey, baseURL string, fallback domain.ExchangeRepository, logger *zap.Logger) *ExchangeAPIRepository {
 	if baseURL == "" {
 		baseURL = "https://api.exchangerate.host"
 	}
 
 	return &ExchangeAPIRepository{
-		apiKey:  apiKey,
-		baseURL: baseURL,
-		client: &http.Client{
-			Timeout: 15 * time.Second,
-		},
+		baseURL:  baseURL,
+		client:   newUpstreamClient(accessKeyTransport{key: apiKey, base: http.DefaultTransport}),
+		fallback: fallback,
+		logger:   logger,
 	}
 }
 
-func (r *ExchangeAPIRepository) GetLatestRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
-	url := fmt.Sprintf("%s/live", r.baseURL)
+// newUpstreamClient returns the HTTP client used for rate providers, sending
+// requests through base. Each call gets a client span named after the method
+// and path.
+func newUpstreamClient(base http.RoundTripper) *http.Client {
+	return &http.Client{
+		Timeout: 15 * time.Second,
+		Transport: otelhttp.NewTransport(base,
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method + " " + r.URL.Path
+			}),
+		),
+	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return r.getMockRate(from, to), nil
+// accessKeyTransport adds the provider access key to each request below the
+// tracing transport, so the key never shows up in span attributes, recorded
+// errors or logs, which all only see the request without it.
+type accessKeyTransport struct {
+	key  string
+	base http.RoundTripper
+}
+
+func (t accessKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.key == "" {
+		return t.base.RoundTrip(req)
 	}
 
-	q := req.URL.Query()
-	if r.apiKey != "" {
-		q.Add("access_key", r.apiKey)
+	keyed := req.Clone(req.Context())
+	q := keyed.URL.Query()
+	q.Set("access_key", t.key)
+	keyed.URL.RawQuery = q.Encode()
+	return t.base.RoundTrip(keyed)
+}
+
+// SetFallbackMaxAge makes fallback rates published more than maxAge before
+// the time they are for fail with ErrStaleData. Zero accepts any age.
+func (r *ExchangeAPIRepository) SetFallbackMaxAge(maxAge time.Duration) {
+	r.fallbackMaxAge = maxAge
+}
+
+func (r *ExchangeAPIRepository) GetLatestRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	rate, err := r.liveRate(ctx, from, to)
+	if err != nil {
+		if r.fallback == nil {
+			return nil, err
+		}
+		r.logFallback(ctx, "live", err, zap.String("from", from), zap.String("to", to))
+		rate, err = r.fallback.GetLatestRate(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.checkFallbackAge(rate.Timestamp, time.Now()); err != nil {
+			return nil, err
+		}
 	}
-	q.Add("source", from)
-	q.Add("currencies", to)
-	req.URL.RawQuery = q.Encode()
+	return rate, nil
+}
 
-	resp, err := r.client.Do(req)
+func (r *ExchangeAPIRepository) GetHistoricalRate(ctx context.Context, from, to, date string) (*domain.ExchangeRate, error) {
+	rate, err := r.historicalRate(ctx, from, to, date)
 	if err != nil {
-		return r.getMockRate(from, to), nil
+		if r.fallback == nil {
+			return nil, err
+		}
+		r.logFallback(ctx, "historical", err, zap.String("from", from), zap.String("to", to), zap.String("date", date))
+		rate, err = r.fallback.GetHistoricalRate(ctx, from, to, date)
+		if err != nil {
+			return nil, err
+		}
+		day, _ := time.Parse("2006-01-02", date)
+		if err := r.checkFallbackAge(rate.Timestamp, day); err != nil {
+			return nil, err
+		}
 	}
-	defer resp.Body.Close()
+	return rate, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+func (r *ExchangeAPIRepository) GetAllLatestRates(ctx context.Context, baseCurrency string) (map[string]float64, error) {
+	rates, err := r.liveRates(ctx, baseCurrency)
 	if err != nil {
-		return r.getMockRate(from, to), nil
+		if r.fallback == nil {
+			return nil, err
+		}
+		r.logFallback(ctx, "live", err, zap.String("base_currency", baseCurrency))
+		if published, ok := r.fallback.(publishedRepository); ok {
+			if at, ok := published.LatestPublished(); ok {
+				if err := r.checkFallbackAge(at, time.Now()); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return r.fallback.GetAllLatestRates(ctx, baseCurrency)
 	}
+	return rates, nil
+}
+
+// checkFallbackAge fails with ErrStaleData when fallback rates published at
+// published are too old to stand in for rates at at.
+func (r *ExchangeAPIRepository) checkFallbackAge(published, at time.Time) error {
+	if r.fallbackMaxAge <= 0 || published.IsZero() || at.Sub(published) <= r.fallbackMaxAge {
+		return nil
+	}
+	return domain.Errorf(domain.ErrStaleData, "fallback rates published %s are older than %s", published.Format("2006-01-02"), r.fallbackMaxAge)
+}
+
+func (r *ExchangeAPIRepository) liveRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	q := url.Values{}
+	q.Add("source", from)
+	q.Add("currencies", to)
 
 	var apiResp ExchangeHostResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return r.getMockRate(from, to), nil
+	if err := r.get(ctx, "/live", q, &apiResp); err != nil {
+		return nil, err
 	}
 
 	if !apiResp.Success || apiResp.Error != nil {
-		return r.getMockRate(from, to), nil
+		return nil, apiResp.Error
 	}
 
 	quoteKey := from + to
 	rate, exists := apiResp.Quotes[quoteKey]
 	if !exists {
-		return r.getMockRate(from, to), nil
+		return nil, errMissingQuote(quoteKey)
 	}
 
 	return &domain.ExchangeRate{
@@ -125,47 +230,25 @@ func (r *ExchangeAPIRepository) GetLatestRate(ctx context.Context, from, to stri
 	}, nil
 }
 
-func (r *ExchangeAPIRepository) GetHistoricalRate(ctx context.Context, from, to, date string) (*domain.ExchangeRate, error) {
-	url := fmt.Sprintf("%s/historical", r.baseURL)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return r.getMockHistoricalRate(from, to, date), nil
-	}
-
-	q := req.URL.Query()
-	if r.apiKey != "" {
-		q.Add("access_key", r.apiKey)
-	}
+func (r *ExchangeAPIRepository) historicalRate(ctx context.Context, from, to, date string) (*domain.ExchangeRate, error) {
+	q := url.Values{}
 	q.Add("date", date)
 	q.Add("source", from)
 	q.Add("currencies", to)
-	req.URL.RawQuery = q.Encode()
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return r.getMockHistoricalRate(from, to, date), nil
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return r.getMockHistoricalRate(from, to, date), nil
-	}
 
 	var apiResp HistoricalResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return r.getMockHistoricalRate(from, to, date), nil
+	if err := r.get(ctx, "/historical", q, &apiResp); err != nil {
+		return nil, err
 	}
 
 	if !apiResp.Success || apiResp.Error != nil {
-		return r.getMockHistoricalRate(from, to, date), nil
+		return nil, apiResp.Error
 	}
 
 	quoteKey := from + to
 	rate, exists := apiResp.Quotes[quoteKey]
 	if !exists {
-		return r.getMockHistoricalRate(from, to, date), nil
+		return nil, errMissingQuote(quoteKey)
 	}
 
 	return &domain.ExchangeRate{
@@ -177,18 +260,8 @@ func (r *ExchangeAPIRepository) GetHistoricalRate(ctx context.Context, from, to,
 	}, nil
 }
 
-func (r *ExchangeAPIRepository) GetAllLatestRates(ctx context.Context, baseCurrency string) (map[string]float64, error) {
-	url := fmt.Sprintf("%s/live", r.baseURL)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return r.getMockRates(baseCurrency), nil
-	}
-
-	q := req.URL.Query()
-	if r.apiKey != "" {
-		q.Add("access_key", r.apiKey)
-	}
+func (r *ExchangeAPIRepository) liveRates(ctx context.Context, baseCurrency string) (map[string]float64, error) {
+	q := url.Values{}
 	q.Add("source", baseCurrency)
 
 	supportedCurrencies := []string{}
@@ -198,26 +271,14 @@ func (r *ExchangeAPIRepository) GetAllLatestRates(ctx context.Context, baseCurre
 		}
 	}
 	q.Add("currencies", strings.Join(supportedCurrencies, ","))
-	req.URL.RawQuery = q.Encode()
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return r.getMockRates(baseCurrency), nil
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return r.getMockRates(baseCurrency), nil
-	}
 
 	var apiResp ExchangeHostResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return r.getMockRates(baseCurrency), nil
+	if err := r.get(ctx, "/live", q, &apiResp); err != nil {
+		return nil, err
 	}
 
 	if !apiResp.Success || apiResp.Error != nil {
-		return r.getMockRates(baseCurrency), nil
+		return nil, apiResp.Error
 	}
 
 	rates := make(map[string]float64)
@@ -231,74 +292,52 @@ func (r *ExchangeAPIRepository) GetAllLatestRates(ctx context.Context, baseCurre
 	return rates, nil
 }
 
-func (r *ExchangeAPIRepository) getMockRates(baseCurrency string) map[string]float64 {
-	mockRates := map[string]map[string]float64{
-		"USD": {
-			"INR": 83.25,
-			"EUR": 0.85,
-			"JPY": 110.50,
-			"GBP": 0.73,
-		},
-		"EUR": {
-			"USD": 1.18,
-			"INR": 98.12,
-			"JPY": 130.25,
-			"GBP": 0.86,
-		},
-		"GBP": {
-			"USD": 1.37,
-			"INR": 114.05,
-			"EUR": 1.16,
-			"JPY": 151.38,
-		},
-		"INR": {
-			"USD": 0.012,
-			"EUR": 0.010,
-			"JPY": 1.33,
-			"GBP": 0.0088,
-		},
-		"JPY": {
-			"USD": 0.0090,
-			"EUR": 0.0077,
-			"INR": 0.75,
-			"GBP": 0.0066,
-		},
+// get calls the provider endpoint and decodes its JSON body into dest. The
+// request ID and trace context from ctx are forwarded so provider-side logs
+// and spans can be correlated.
+func (r *ExchangeAPIRepository) get(ctx context.Context, path string, q url.Values, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", r.baseURL+path, nil)
+	if err != nil {
+		return err
 	}
 
-	if rates, exists := mockRates[baseCurrency]; exists {
-		return rates
+	req.URL.RawQuery = q.Encode()
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
 	}
-	return make(map[string]float64)
-}
 
-func (r *ExchangeAPIRepository) getMockRate(from, to string) *domain.ExchangeRate {
-	baseRates := r.getMockRates(from)
-	rate, exists := baseRates[to]
-	if !exists {
-		rate = 1.0
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
 
-	return &domain.ExchangeRate{
-		FromCurrency: from,
-		ToCurrency:   to,
-		Rate:         rate,
-		Timestamp:    time.Now(),
-		Date:         time.Now().Format("2006-01-02"),
+	r.log(ctx).Debug("Upstream request",
+		zap.String("path", path),
+		zap.Int("status", resp.StatusCode),
+		zap.Duration("latency", time.Since(start)),
+	)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
 	}
+
+	return json.Unmarshal(body, dest)
 }
 
-func (r *ExchangeAPIRepository) getMockHistoricalRate(from, to, date string) *domain.ExchangeRate {
-	baseRates := r.getMockRates(from)
-	rate, exists := baseRates[to]
-	if !exists {
-		rate = 1.0
-	}
+// logFallback records why the provider response was not usable before the
+// caller serves fallback rates instead.
+func (r *ExchangeAPIRepository) logFallback(ctx context.Context, endpoint string, cause error, fields ...zap.Field) {
+	fields = append(fields, zap.String("endpoint", endpoint), zap.Error(cause))
+	r.log(ctx).Warn("Upstream rates unavailable, using fallback rates", fields...)
+}
 
-	return &domain.ExchangeRate{
-		FromCurrency: from,
-		ToCurrency:   to,
-		Rate:         rate,
-		Timestamp:    time.Now(),
-		Date:         date,
-	}
+func (r *ExchangeAPIRepository) log(ctx context.Context) *zap.Logger {
+	return requestid.Logger(ctx, r.logger)
+}
+
+func errMissingQuote(pair string) error {
+	return fmt.Errorf("quote %s missing from response", pair)
 }
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// FileRatesRepository serves rates from a directory of snapshot files named
+// after their date, e.g. 2025-09-01.json or 2025-09-01.csv. A JSON snapshot
+// maps each base currency to its rate table; a CSV snapshot has
+// base_currency,currency,rate rows under a header. The newest snapshot is
+// the latest rate, and a historical date uses the last snapshot on or before
+// it.
+type FileRatesRepository struct {
+	dir    string
+	logger *zap.Logger
+
+	mu        sync.RWMutex
+	snapshots map[string]map[string]map[string]float64
+	dates     []string
+	signature string
+}
+
+func NewFileRatesRepository(dir string, logger *zap.Logger) (*FileRatesRepository, error) {
+	r := &FileRatesRepository{dir: dir, logger: logger}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *FileRatesRepository) GetLatestRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.dates) == 0 {
+		return nil, fmt.Errorf("%w: no rate snapshots in %s", domain.ErrNotFound, r.dir)
+	}
+	return r.rate(from, to, r.dates[len(r.dates)-1])
+}
+
+func (r *FileRatesRepository) GetHistoricalRate(ctx context.Context, from, to, date string) (*domain.ExchangeRate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := sort.SearchStrings(r.dates, date)
+	if i == len(r.dates) || r.dates[i] != date {
+		if i == 0 {
+			return nil, fmt.Errorf("%w: no rate snapshot on or before %s", domain.ErrNotFound, date)
+		}
+		i--
+	}
+
+	rate, err := r.rate(from, to, r.dates[i])
+	if err != nil {
+		return nil, err
+	}
+	rate.Date = date
+	return rate, nil
+}
+
+// LatestPublished returns the date of the newest snapshot, and false when
+// there is none.
+func (r *FileRatesRepository) LatestPublished() (time.Time, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.dates) == 0 {
+		return time.Time{}, false
+	}
+	published, err := time.Parse("2006-01-02", r.dates[len(r.dates)-1])
+	return published, err == nil
+}
+
+func (r *FileRatesRepository) GetAllLatestRates(ctx context.Context, baseCurrency string) (map[string]float64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.dates) == 0 {
+		return nil, fmt.Errorf("%w: no rate snapshots in %s", domain.ErrNotFound, r.dir)
+	}
+
+	date := r.dates[len(r.dates)-1]
+	rates := make(map[string]float64)
+	for currency := range domain.SupportedCurrencies {
+		if currency == baseCurrency {
+			continue
+		}
+		if rate, err := r.rate(baseCurrency, currency, date); err == nil {
+			rates[currency] = rate.Rate
+		}
+	}
+	return rates, nil
+}
+
+// rate looks from/to up in the snapshot for date, inverting the opposite
+// quote when the snapshot has no table for from. Callers hold r.mu.
+func (r *FileRatesRepository) rate(from, to, date string) (*domain.ExchangeRate, error) {
+	snapshot := r.snapshots[date]
+
+	rate, ok := snapshot[from][to]
+	if !ok {
+		if inverse, exists := snapshot[to][from]; exists && inverse > 0 {
+			rate, ok = 1/inverse, true
+		}
+	}
+	if from == to {
+		rate, ok = 1, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: snapshot %s has no rate for %s to %s", domain.ErrNotFound, date, from, to)
+	}
+
+	published, _ := time.Parse("2006-01-02", date)
+	return &domain.ExchangeRate{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         rate,
+		Timestamp:    published,
+		Date:         date,
+	}, nil
+}
+
+// Reload re-reads every snapshot in the directory. On error the previously
+// loaded snapshots stay in use.
+func (r *FileRatesRepository) Reload() error {
+	signature, err := r.scan()
+	if err != nil {
+		return err
+	}
+
+	snapshots := make(map[string]map[string]map[string]float64)
+	var dates []string
+	for _, name := range snapshotFiles(signature) {
+		date := strings.TrimSuffix(name, filepath.Ext(name))
+		if _, exists := snapshots[date]; exists {
+			return fmt.Errorf("duplicate rate snapshot for %s", date)
+		}
+
+		snapshot, err := readSnapshot(filepath.Join(r.dir, name))
+		if err != nil {
+			return fmt.Errorf("read rate snapshot %s: %w", name, err)
+		}
+		snapshots[date] = snapshot
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	r.mu.Lock()
+	r.snapshots = snapshots
+	r.dates = dates
+	r.signature = strings.Join(signature, "\n")
+	r.mu.Unlock()
+	return nil
+}
+
+// Watch polls the directory every interval and reloads when a snapshot is
+// added, removed or modified, until ctx is cancelled.
+func (r *FileRatesRepository) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			signature, err := r.scan()
+			if err != nil {
+				r.logger.Warn("Failed to scan rate snapshots", zap.String("dir", r.dir), zap.Error(err))
+				continue
+			}
+
+			r.mu.RLock()
+			unchanged := strings.Join(signature, "\n") == r.signature
+			r.mu.RUnlock()
+			if unchanged {
+				continue
+			}
+
+			if err := r.Reload(); err != nil {
+				r.logger.Warn("Failed to reload rate snapshots", zap.String("dir", r.dir), zap.Error(err))
+				continue
+			}
+			r.logger.Info("Reloaded rate snapshots", zap.String("dir", r.dir), zap.Int("snapshots", len(signature)))
+		}
+	}
+}
+
+// scan lists the snapshot files as "name size modtime" lines, sorted, so a
+// change to any of them changes the result.
+func (r *FileRatesRepository) scan() ([]string, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var signature []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".csv") {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", strings.TrimSuffix(entry.Name(), ext)); err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		signature = append(signature, fmt.Sprintf("%s %d %d", entry.Name(), info.Size(), info.ModTime().UnixNano()))
+	}
+	sort.Strings(signature)
+	return signature, nil
+}
+
+func snapshotFiles(signature []string) []string {
+	names := make([]string, len(signature))
+	for i, line := range signature {
+		names[i], _, _ = strings.Cut(line, " ")
+	}
+	return names
+}
+
+func readSnapshot(path string) (map[string]map[string]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	snapshot := make(map[string]map[string]float64)
+	if filepath.Ext(path) == ".json" {
+		if err := json.NewDecoder(f).Decode(&snapshot); err != nil {
+			return nil, err
+		}
+		return snapshot, nil
+	}
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = 3
+	if _, err := reader.Read(); err != nil {
+		return nil, fmt.Errorf("missing header: %w", err)
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate %q: %w", record[2], err)
+		}
+		base := strings.ToUpper(strings.TrimSpace(record[0]))
+		if snapshot[base] == nil {
+			snapshot[base] = make(map[string]float64)
+		}
+		snapshot[base][strings.ToUpper(strings.TrimSpace(record[1]))] = rate
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"sync"
+
+	"exchange-rate-service/internal/domain"
+)
+
+// FixingRepository stores every version of every fixing in a JSON file,
+// keyed by date. With an empty path they only live in memory.
+type FixingRepository struct {
+	mu       sync.RWMutex
+	path     string
+	versions map[string][]domain.Fixing
+}
+
+func NewFixingRepository(path string) (*FixingRepository, error) {
+	repo := &FixingRepository{path: path}
+
+	if path != "" {
+		if err := loadJSONFile(path, &repo.versions); err != nil {
+			return nil, err
+		}
+	}
+	if repo.versions == nil {
+		repo.versions = make(map[string][]domain.Fixing)
+	}
+
+	return repo, nil
+}
+
+func (r *FixingRepository) Add(fixing *domain.Fixing) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if fixing.Version != len(r.versions[fixing.Date])+1 {
+		return domain.ErrConflict
+	}
+	r.versions[fixing.Date] = append(r.versions[fixing.Date], *fixing)
+	return r.persist()
+}
+
+// Versions returns the versions for date, oldest first.
+func (r *FixingRepository) Versions(date string) ([]domain.Fixing, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make([]domain.Fixing, len(r.versions[date]))
+	copy(versions, r.versions[date])
+	return versions, nil
+}
+
+func (r *FixingRepository) persist() error {
+	if r.path == "" {
+		return nil
+	}
+	return saveJSONFile(r.path, r.versions)
+}
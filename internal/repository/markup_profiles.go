@@ -0,0 +1,16 @@
+package repository
+
+import "exchange-rate-service/internal/domain"
+
+// LoadMarkupProfiles reads a JSON array of markup profiles from path. An
+// empty path or a missing file yields no profiles.
+func LoadMarkupProfiles(path string) ([]domain.MarkupProfile, error) {
+	var profiles []domain.MarkupProfile
+	if path == "" {
+		return profiles, nil
+	}
+	if err := loadJSONFile(path, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+)
+
+// observationCompactEvery is how often Record rewrites the history file
+// without the observations past retention.
+const observationCompactEvery = time.Hour
+
+// ObservationRepository keeps observed rates per pair, in the order they were
+// observed, in a file of JSON lines. An observation repeating the current
+// value for its pair and date is dropped, so the file only grows when the
+// provider moves or revises a rate. New observations are appended; the file
+// is rewritten on load and at most hourly to drop observations older than
+// retention. A zero retention keeps everything. With an empty path they only
+// live in memory.
+type ObservationRepository struct {
+	mu           sync.RWMutex
+	path         string
+	retention    time.Duration
+	compacted    time.Time
+	observations map[string][]domain.RateObservation
+}
+
+func NewObservationRepository(path string, retention time.Duration) (*ObservationRepository, error) {
+	repo := &ObservationRepository{
+		path:         path,
+		retention:    retention,
+		observations: make(map[string][]domain.RateObservation),
+	}
+
+	if path != "" {
+		if err := repo.load(); err != nil {
+			return nil, err
+		}
+		if err := repo.compact(); err != nil {
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+func (r *ObservationRepository) Record(observations []domain.RateObservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var added []domain.RateObservation
+	for _, observation := range observations {
+		if current := r.current(observation.Pair, observation.Date); current != nil && current.Rate == observation.Rate {
+			continue
+		}
+		r.observations[observation.Pair] = append(r.observations[observation.Pair], observation)
+		added = append(added, observation)
+	}
+
+	if time.Since(r.compacted) >= observationCompactEvery {
+		return r.compact()
+	}
+	if len(added) == 0 || r.path == "" {
+		return nil
+	}
+	return r.append(added)
+}
+
+func (r *ObservationRepository) AsOf(from, to, date string, asOf time.Time) (*domain.RateObservation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found *domain.RateObservation
+	observations := r.observations[from+to]
+	for i := range observations {
+		observation := &observations[i]
+		if observation.ObservedAt.After(asOf) || (date != "" && observation.Date != date) {
+			continue
+		}
+		if found == nil || observation.Date > found.Date ||
+			(observation.Date == found.Date && !observation.ObservedAt.Before(found.ObservedAt)) {
+			found = observation
+		}
+	}
+
+	if found == nil {
+		return nil, domain.Errorf(domain.ErrNotFound, "no %s%s rate known as of %s", from, to, asOf.Format(time.RFC3339))
+	}
+	result := *found
+	return &result, nil
+}
+
+// current returns the most recent observation for pair on date. Callers
+// hold the lock.
+func (r *ObservationRepository) current(pair, date string) *domain.RateObservation {
+	observations := r.observations[pair]
+	for i := len(observations) - 1; i >= 0; i-- {
+		if observations[i].Date == date {
+			return &observations[i]
+		}
+	}
+	return nil
+}
+
+// prune drops the observations past retention. Callers hold the lock.
+func (r *ObservationRepository) prune() {
+	if r.retention <= 0 {
+		return
+	}
+
+	cutoff := time.Now().Add(-r.retention)
+	for pair, observations := range r.observations {
+		kept := observations[:0]
+		for _, observation := range observations {
+			if !observation.ObservedAt.Before(cutoff) {
+				kept = append(kept, observation)
+			}
+		}
+		if len(kept) == 0 {
+			delete(r.observations, pair)
+		} else {
+			r.observations[pair] = kept
+		}
+	}
+}
+
+// load reads the history file. A torn last line, left by a crash while
+// appending, is ignored.
+func (r *ObservationRepository) load() error {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var observation domain.RateObservation
+		if err := json.Unmarshal(scanner.Bytes(), &observation); err != nil {
+			if bytes.HasSuffix(data, scanner.Bytes()) {
+				break
+			}
+			return fmt.Errorf("%s line %d: %w", r.path, line, err)
+		}
+		r.observations[observation.Pair] = append(r.observations[observation.Pair], observation)
+	}
+	return scanner.Err()
+}
+
+// compact prunes and rewrites the whole history file. Callers hold the lock.
+func (r *ObservationRepository) compact() error {
+	r.prune()
+	r.compacted = time.Now()
+	if r.path == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, observations := range r.observations {
+		for _, observation := range observations {
+			if err := encoder.Encode(observation); err != nil {
+				return err
+			}
+		}
+	}
+	return writeFileAtomic(r.path, buf.Bytes())
+}
+
+// append adds observations to the end of the history file. Callers hold
+// the lock.
+func (r *ObservationRepository) append(observations []domain.RateObservation) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, observation := range observations {
+		if err := encoder.Encode(observation); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"exchange-rate-service/internal/domain"
+)
+
+type overrideFile struct {
+	Overrides map[string]domain.RateOverride `json:"overrides"`
+	Audit     []domain.OverrideAuditEntry    `json:"audit"`
+}
+
+// OverrideRepository stores rate overrides and their audit trail in a JSON
+// file. Each change and its audit entry are written together, so neither is
+// saved without the other. Overrides are indexed by pair for lookups. With
+// an empty path they only live in memory.
+type OverrideRepository struct {
+	mu     sync.RWMutex
+	path   string
+	data   overrideFile
+	byPair map[string][]domain.RateOverride
+}
+
+func NewOverrideRepository(path string) (*OverrideRepository, error) {
+	repo := &OverrideRepository{path: path, byPair: make(map[string][]domain.RateOverride)}
+
+	if path != "" {
+		if err := loadJSONFile(path, &repo.data); err != nil {
+			return nil, err
+		}
+	}
+	if repo.data.Overrides == nil {
+		repo.data.Overrides = make(map[string]domain.RateOverride)
+	}
+	for _, override := range repo.data.Overrides {
+		repo.byPair[override.Pair] = append(repo.byPair[override.Pair], override)
+	}
+	for _, overrides := range repo.byPair {
+		sortByStart(overrides)
+	}
+
+	return repo, nil
+}
+
+func (r *OverrideRepository) Create(override *domain.RateOverride, entry *domain.OverrideAuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.write(override.ID, override, entry)
+}
+
+func (r *OverrideRepository) Get(id string) (*domain.RateOverride, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	override, exists := r.data.Overrides[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	return &override, nil
+}
+
+func (r *OverrideRepository) List() ([]domain.RateOverride, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	overrides := make([]domain.RateOverride, 0, len(r.data.Overrides))
+	for _, override := range r.data.Overrides {
+		overrides = append(overrides, override)
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		return overrides[i].CreatedAt.Before(overrides[j].CreatedAt)
+	})
+
+	return overrides, nil
+}
+
+// ListPair returns the overrides for pair, ordered by start date.
+func (r *OverrideRepository) ListPair(pair string) ([]domain.RateOverride, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]domain.RateOverride(nil), r.byPair[pair]...), nil
+}
+
+func (r *OverrideRepository) Update(override *domain.RateOverride, entry *domain.OverrideAuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.data.Overrides[override.ID]; !exists {
+		return domain.ErrNotFound
+	}
+	return r.write(override.ID, override, entry)
+}
+
+func (r *OverrideRepository) Delete(id string, entry *domain.OverrideAuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.data.Overrides[id]; !exists {
+		return domain.ErrNotFound
+	}
+	return r.write(id, nil, entry)
+}
+
+func (r *OverrideRepository) ListAudit() ([]domain.OverrideAuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	audit := make([]domain.OverrideAuditEntry, len(r.data.Audit))
+	copy(audit, r.data.Audit)
+	return audit, nil
+}
+
+// write sets the override with id, or deletes it when override is nil, and
+// appends entry to the audit trail in one save. If the save fails, both are
+// rolled back. Callers hold the lock.
+func (r *OverrideRepository) write(id string, override *domain.RateOverride, entry *domain.OverrideAuditEntry) error {
+	previous, existed := r.data.Overrides[id]
+	if override != nil {
+		r.data.Overrides[id] = *override
+	} else {
+		delete(r.data.Overrides, id)
+	}
+	r.data.Audit = append(r.data.Audit, *entry)
+
+	if err := r.persist(); err != nil {
+		if existed {
+			r.data.Overrides[id] = previous
+		} else {
+			delete(r.data.Overrides, id)
+		}
+		r.data.Audit = r.data.Audit[:len(r.data.Audit)-1]
+		return err
+	}
+
+	if existed {
+		r.reindex(previous.Pair)
+	}
+	if override != nil && (!existed || override.Pair != previous.Pair) {
+		r.reindex(override.Pair)
+	}
+	return nil
+}
+
+// reindex rebuilds the index entry for pair. Callers hold the lock.
+func (r *OverrideRepository) reindex(pair string) {
+	var overrides []domain.RateOverride
+	for _, override := range r.data.Overrides {
+		if override.Pair == pair {
+			overrides = append(overrides, override)
+		}
+	}
+	sortByStart(overrides)
+
+	if len(overrides) == 0 {
+		delete(r.byPair, pair)
+		return
+	}
+	r.byPair[pair] = overrides
+}
+
+func sortByStart(overrides []domain.RateOverride) {
+	sort.Slice(overrides, func(i, j int) bool {
+		return overrides[i].StartDate < overrides[j].StartDate
+	})
+}
+
+func (r *OverrideRepository) persist() error {
+	if r.path == "" {
+		return nil
+	}
+	return saveJSONFile(r.path, r.data)
+}
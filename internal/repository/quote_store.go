@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+)
+
+// QuoteRepository stores each rate quote in its own file under dir, so
+// replicas sharing the directory see each other's quotes. A quote file is
+// written once; executing it creates a marker file next to it with
+// O_EXCL, which the filesystem lets exactly one replica do. With an empty
+// dir quotes only live in memory.
+type QuoteRepository struct {
+	mu     sync.Mutex
+	dir    string
+	quotes map[string]domain.Quote
+}
+
+func NewQuoteRepository(dir string) (*QuoteRepository, error) {
+	repo := &QuoteRepository{dir: dir, quotes: make(map[string]domain.Quote)}
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+func (r *QuoteRepository) Create(quote *domain.Quote) error {
+	if r.dir == "" {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.quotes[quote.ID] = *quote
+		return nil
+	}
+
+	stored := *quote
+	stored.ExecutedAt = nil
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(r.quotePath(quote.ID), data)
+}
+
+func (r *QuoteRepository) Get(id string) (*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.get(id)
+}
+
+// Execute marks the quote executed at at. It fails with ErrExpired once the
+// quote has expired and with ErrConflict when it was already executed, here
+// or by another replica.
+func (r *QuoteRepository) Execute(id string, at time.Time) (*domain.Quote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	quote, err := r.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if quote.ExecutedAt != nil {
+		return nil, errExecuted(id, *quote.ExecutedAt)
+	}
+	if !at.Before(quote.ExpiresAt) {
+		return nil, domain.Errorf(domain.ErrExpired, "quote %s expired at %s", id, quote.ExpiresAt.Format(time.RFC3339))
+	}
+
+	quote.ExecutedAt = &at
+	if r.dir == "" {
+		r.quotes[id] = *quote
+		return quote, nil
+	}
+
+	marker, err := os.OpenFile(r.markerPath(id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		executed, _ := r.get(id)
+		if executed != nil && executed.ExecutedAt != nil {
+			return nil, errExecuted(id, *executed.ExecutedAt)
+		}
+		return nil, domain.Errorf(domain.ErrConflict, "quote %s was already executed", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := marker.WriteString(at.Format(time.RFC3339Nano)); err != nil {
+		marker.Close()
+		return nil, err
+	}
+	if err := marker.Close(); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// Prune drops quotes that expired before expiredBefore.
+func (r *QuoteRepository) Prune(expiredBefore time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.dir == "" {
+		for id, quote := range r.quotes {
+			if quote.ExpiresAt.Before(expiredBefore) {
+				delete(r.quotes, id)
+			}
+		}
+		return nil
+	}
+
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || !validQuoteID(id) {
+			continue
+		}
+		quote, err := r.get(id)
+		if err != nil || !quote.ExpiresAt.Before(expiredBefore) {
+			continue
+		}
+		if err := os.Remove(r.quotePath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := os.Remove(r.markerPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// get reads a quote and whether it was executed. Callers hold the lock.
+func (r *QuoteRepository) get(id string) (*domain.Quote, error) {
+	if r.dir == "" {
+		quote, exists := r.quotes[id]
+		if !exists {
+			return nil, domain.ErrNotFound
+		}
+		return &quote, nil
+	}
+
+	if !validQuoteID(id) {
+		return nil, domain.ErrNotFound
+	}
+	data, err := os.ReadFile(r.quotePath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var quote domain.Quote
+	if err := json.Unmarshal(data, &quote); err != nil {
+		return nil, err
+	}
+
+	marker, err := os.ReadFile(r.markerPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return &quote, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	executedAt, err := time.Parse(time.RFC3339Nano, string(marker))
+	if err != nil {
+		// The executing replica created the marker but has not written
+		// the time yet; the marker's modification time is close enough.
+		info, statErr := os.Stat(r.markerPath(id))
+		if statErr != nil {
+			return nil, statErr
+		}
+		executedAt = info.ModTime()
+	}
+	quote.ExecutedAt = &executedAt
+	return &quote, nil
+}
+
+func (r *QuoteRepository) quotePath(id string) string {
+	return filepath.Join(r.dir, id+".json")
+}
+
+func (r *QuoteRepository) markerPath(id string) string {
+	return filepath.Join(r.dir, id+".executed")
+}
+
+// validQuoteID keeps ids from the URL from naming files outside the store.
+func validQuoteID(id string) bool {
+	_, err := hex.DecodeString(id)
+	return id != "" && err == nil
+}
+
+func errExecuted(id string, at time.Time) error {
+	return domain.Errorf(domain.ErrConflict, "quote %s was already executed at %s", id, at.Format(time.RFC3339))
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// sharedCacheLockWait is how long Lock waits for another process to
+	// release a key.
+	sharedCacheLockWait = time.Second
+	// sharedCacheLockStale is how old a lock must be before it is taken to
+	// belong to a process that died holding it.
+	sharedCacheLockStale = 5 * time.Second
+)
+
+type sharedCacheItem struct {
+	Value      json.RawMessage `json:"value"`
+	Expiration time.Time       `json:"expiration"`
+}
+
+// SharedCacheRepository keeps each entry in its own file under dir, so
+// processes sharing the directory share the cache. Lock guards a key across
+// all of them with a lock file only one process can create.
+type SharedCacheRepository struct {
+	dir string
+}
+
+func NewSharedCacheRepository(dir string) (*SharedCacheRepository, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &SharedCacheRepository{dir: dir}, nil
+}
+
+func (c *SharedCacheRepository) Set(key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	item, err := json.Marshal(sharedCacheItem{Value: data, Expiration: time.Now().Add(expiration)})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.itemPath(key), item)
+}
+
+func (c *SharedCacheRepository) Get(key string, dest interface{}) error {
+	item, err := c.read(c.itemPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return errors.New("key not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	if time.Now().After(item.Expiration) {
+		return errors.New("key expired")
+	}
+
+	return json.Unmarshal(item.Value, dest)
+}
+
+func (c *SharedCacheRepository) Delete(key string) error {
+	if err := os.Remove(c.itemPath(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (c *SharedCacheRepository) Clear() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".json") {
+			if err := os.Remove(filepath.Join(c.dir, entry.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Lock holds key against every process sharing the directory until unlock
+// is called. A lock left behind by a process that died is broken after
+// sharedCacheLockStale.
+func (c *SharedCacheRepository) Lock(key string) (func(), error) {
+	path := c.lockPath(key)
+	deadline := time.Now().Add(sharedCacheLockWait)
+
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > sharedCacheLockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for cache lock on %s", key)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// StartJanitor removes expired entries and stale locks every interval until
+// ctx is done. Get never returns expired entries, so the janitor only
+// reclaims disk space.
+func (c *SharedCacheRepository) StartJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.evictExpired()
+		}
+	}
+}
+
+func (c *SharedCacheRepository) evictExpired() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		path := filepath.Join(c.dir, entry.Name())
+		switch filepath.Ext(entry.Name()) {
+		case ".json":
+			if item, err := c.read(path); err == nil && now.After(item.Expiration) {
+				os.Remove(path)
+			}
+		case ".lock":
+			if info, err := entry.Info(); err == nil && now.Sub(info.ModTime()) > sharedCacheLockStale {
+				os.Remove(path)
+			}
+		}
+	}
+}
+
+func (c *SharedCacheRepository) read(path string) (*sharedCacheItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var item sharedCacheItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// fileName turns key, which may hold any characters, into a file name.
+func (c *SharedCacheRepository) fileName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+func (c *SharedCacheRepository) itemPath(key string) string {
+	return c.fileName(key) + ".json"
+}
+
+func (c *SharedCacheRepository) lockPath(key string) string {
+	return c.fileName(key) + ".lock"
+}
@@ -0,0 +1,16 @@
+package repository
+
+import "exchange-rate-service/internal/domain"
+
+// LoadUpdateGroups reads a JSON array of update groups from path. An empty
+// path or a missing file yields no groups.
+func LoadUpdateGroups(path string) ([]domain.UpdateGroup, error) {
+	var groups []domain.UpdateGroup
+	if path == "" {
+		return groups, nil
+	}
+	if err := loadJSONFile(path, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
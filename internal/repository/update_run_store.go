@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"sync"
+	"time"
+)
+
+// UpdateRunRepository stores the last run of each update group in a JSON
+// file. With an empty path they only live in memory.
+type UpdateRunRepository struct {
+	mu   sync.RWMutex
+	path string
+	runs map[string]time.Time
+}
+
+func NewUpdateRunRepository(path string) (*UpdateRunRepository, error) {
+	repo := &UpdateRunRepository{path: path}
+
+	if path != "" {
+		if err := loadJSONFile(path, &repo.runs); err != nil {
+			return nil, err
+		}
+	}
+	if repo.runs == nil {
+		repo.runs = make(map[string]time.Time)
+	}
+
+	return repo, nil
+}
+
+func (r *UpdateRunRepository) LastRun(group string) (time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.runs[group], nil
+}
+
+func (r *UpdateRunRepository) SetLastRun(group string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.runs[group] = at
+	return r.persist()
+}
+
+func (r *UpdateRunRepository) persist() error {
+	if r.path == "" {
+		return nil
+	}
+	return saveJSONFile(r.path, r.runs)
+}
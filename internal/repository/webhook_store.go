@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"exchange-rate-service/internal/domain"
+)
+
+type webhookData struct {
+	Subscriptions map[string]domain.WebhookSubscription `json:"subscriptions"`
+	States        map[string]domain.WebhookState        `json:"states,omitempty"`
+	DeadLetters   []domain.WebhookDeadLetter            `json:"dead_letters"`
+}
+
+// WebhookRepository keeps webhook subscriptions, their condition state and
+// dead letters in a JSON file. At most maxDeadLetters dead letters are kept,
+// the oldest dropped first; zero keeps them all. With an empty path the data
+// only lives in memory.
+type WebhookRepository struct {
+	mu             sync.RWMutex
+	path           string
+	maxDeadLetters int
+	data           webhookData
+}
+
+func NewWebhookRepository(path string, maxDeadLetters int) (*WebhookRepository, error) {
+	repo := &WebhookRepository{
+		path:           path,
+		maxDeadLetters: maxDeadLetters,
+		data: webhookData{
+			Subscriptions: make(map[string]domain.WebhookSubscription),
+		},
+	}
+
+	if path != "" {
+		if err := loadJSONFile(path, &repo.data); err != nil {
+			return nil, err
+		}
+		if repo.data.Subscriptions == nil {
+			repo.data.Subscriptions = make(map[string]domain.WebhookSubscription)
+		}
+	}
+
+	return repo, nil
+}
+
+func (r *WebhookRepository) Create(sub *domain.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data.Subscriptions[sub.ID] = *sub
+	return r.persist()
+}
+
+func (r *WebhookRepository) Get(id string) (*domain.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sub, exists := r.data.Subscriptions[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	return &sub, nil
+}
+
+func (r *WebhookRepository) List() ([]domain.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subs := make([]domain.WebhookSubscription, 0, len(r.data.Subscriptions))
+	for _, sub := range r.data.Subscriptions {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].CreatedAt.Before(subs[j].CreatedAt)
+	})
+
+	return subs, nil
+}
+
+func (r *WebhookRepository) Update(sub *domain.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.data.Subscriptions[sub.ID]; !exists {
+		return domain.ErrNotFound
+	}
+	r.data.Subscriptions[sub.ID] = *sub
+	return r.persist()
+}
+
+func (r *WebhookRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.data.Subscriptions[id]; !exists {
+		return domain.ErrNotFound
+	}
+	delete(r.data.Subscriptions, id)
+	delete(r.data.States, id)
+	return r.persist()
+}
+
+func (r *WebhookRepository) States() (map[string]domain.WebhookState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	states := make(map[string]domain.WebhookState, len(r.data.States))
+	for id, state := range r.data.States {
+		states[id] = state
+	}
+	return states, nil
+}
+
+func (r *WebhookRepository) SaveStates(states map[string]domain.WebhookState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data.States = make(map[string]domain.WebhookState, len(states))
+	for id, state := range states {
+		r.data.States[id] = state
+	}
+	return r.persist()
+}
+
+func (r *WebhookRepository) AddDeadLetter(letter *domain.WebhookDeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data.DeadLetters = append(r.data.DeadLetters, *letter)
+	if r.maxDeadLetters > 0 && len(r.data.DeadLetters) > r.maxDeadLetters {
+		r.data.DeadLetters = r.data.DeadLetters[len(r.data.DeadLetters)-r.maxDeadLetters:]
+	}
+	return r.persist()
+}
+
+func (r *WebhookRepository) GetDeadLetter(id string) (*domain.WebhookDeadLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, letter := range r.data.DeadLetters {
+		if letter.ID == id {
+			return &letter, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *WebhookRepository) UpdateDeadLetter(letter *domain.WebhookDeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.data.DeadLetters {
+		if r.data.DeadLetters[i].ID == letter.ID {
+			r.data.DeadLetters[i] = *letter
+			return r.persist()
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (r *WebhookRepository) DeleteDeadLetter(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.data.DeadLetters {
+		if r.data.DeadLetters[i].ID == id {
+			r.data.DeadLetters = append(r.data.DeadLetters[:i], r.data.DeadLetters[i+1:]...)
+			return r.persist()
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (r *WebhookRepository) ListDeadLetters() ([]domain.WebhookDeadLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	letters := make([]domain.WebhookDeadLetter, len(r.data.DeadLetters))
+	copy(letters, r.data.DeadLetters)
+	return letters, nil
+}
+
+func (r *WebhookRepository) persist() error {
+	if r.path == "" {
+		return nil
+	}
+	return saveJSONFile(r.path, r.data)
+}
@@ -0,0 +1,69 @@
+package requestid
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// Header carries the request ID on HTTP requests and responses.
+const Header = "X-Request-ID"
+
+const maxLength = 128
+
+type contextKey struct{}
+
+type loggerKey struct{}
+
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// WithLogger stores a request-scoped logger, normally one already tagged
+// with the request ID.
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// Logger returns the logger stored by WithLogger, or fallback when ctx
+// carries none, so code outside a request keeps logging as before.
+func Logger(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return fallback
+}
+
+// Scope tags ctx with id and a child of logger carrying a request_id field.
+func Scope(ctx context.Context, id string, logger *zap.Logger) context.Context {
+	ctx = WithContext(ctx, id)
+	return WithLogger(ctx, logger.With(zap.String("request_id", id)))
+}
+
+// New returns a fresh request ID.
+func New() string {
+	return utils.NewID()
+}
+
+// Valid reports whether a client-supplied ID is safe to echo back and log:
+// 1 to 128 characters of letters, digits, '-', '_' and '.'.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const apiKeyPrefix = "ers_"
+
+type APIKeyService struct {
+	repo              domain.APIKeyRepository
+	usage             domain.APIKeyUsageRepository
+	limiter           *RateLimiter
+	logger            *zap.Logger
+	defaultRateLimit  int
+	defaultDailyQuota int
+
+	mu sync.Mutex
+}
+
+func NewAPIKeyService(repo domain.APIKeyRepository, usage domain.APIKeyUsageRepository, limiter *RateLimiter, logger *zap.Logger, defaultRateLimit, defaultDailyQuota int) *APIKeyService {
+	return &APIKeyService{
+		repo:              repo,
+		usage:             usage,
+		limiter:           limiter,
+		logger:            logger,
+		defaultRateLimit:  defaultRateLimit,
+		defaultDailyQuota: defaultDailyQuota,
+	}
+}
+
+// Create issues a new key. The plaintext key is only returned here; the
+// store keeps its SHA-256 hash.
+func (s *APIKeyService) Create(req *domain.APIKeyRequest) (*domain.CreatedAPIKey, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, domain.ValidationErrorf("name is required")
+	}
+	if req.RateLimit < 0 || req.DailyQuota < 0 {
+		return nil, domain.ValidationErrorf("rate_limit and daily_quota cannot be negative")
+	}
+
+	raw := apiKeyPrefix + utils.NewID()
+	key := &domain.APIKey{
+		ID:            utils.NewID(),
+		Name:          req.Name,
+		Prefix:        raw[:len(apiKeyPrefix)+6],
+		Hash:          HashAPIKey(raw),
+		RateLimit:     req.RateLimit,
+		DailyQuota:    req.DailyQuota,
+		MarkupProfile: strings.TrimSpace(req.MarkupProfile),
+		CreatedAt:     time.Now(),
+	}
+	if key.RateLimit == 0 {
+		key.RateLimit = s.defaultRateLimit
+	}
+	if key.DailyQuota == 0 {
+		key.DailyQuota = s.defaultDailyQuota
+	}
+
+	if err := s.repo.Create(key); err != nil {
+		return nil, err
+	}
+
+	return &domain.CreatedAPIKey{APIKey: *key, Key: raw}, nil
+}
+
+func (s *APIKeyService) Get(id string) (*domain.APIKey, error) {
+	return s.repo.Get(id)
+}
+
+func (s *APIKeyService) List() ([]domain.APIKey, error) {
+	return s.repo.List()
+}
+
+func (s *APIKeyService) Update(id string, req *domain.APIKeyRequest) (*domain.APIKey, error) {
+	key, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.RateLimit < 0 || req.DailyQuota < 0 {
+		return nil, domain.ValidationErrorf("rate_limit and daily_quota cannot be negative")
+	}
+
+	if strings.TrimSpace(req.Name) != "" {
+		key.Name = req.Name
+	}
+	if req.RateLimit > 0 {
+		key.RateLimit = req.RateLimit
+	}
+	if req.DailyQuota > 0 {
+		key.DailyQuota = req.DailyQuota
+	}
+	if strings.TrimSpace(req.MarkupProfile) != "" {
+		key.MarkupProfile = strings.TrimSpace(req.MarkupProfile)
+	}
+
+	if err := s.repo.Update(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (s *APIKeyService) Revoke(id string) error {
+	key, err := s.repo.Get(id)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	key.RevokedAt = &now
+	return s.repo.Update(key)
+}
+
+func (s *APIKeyService) Authenticate(raw string) (*domain.APIKey, error) {
+	if raw == "" {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.GetByHash(HashAPIKey(raw))
+	if err != nil || key.RevokedAt != nil {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	return key, nil
+}
+
+// Allow records a request costing cost tokens against the key's per-minute
+// rate limit and its daily quota.
+func (s *APIKeyService) Allow(key *domain.APIKey, cost int) (domain.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	date := now.Format("2006-01-02")
+	usage := s.loadUsage(key.ID, date)
+	defer s.saveUsage(key.ID, usage)
+
+	if key.DailyQuota > 0 && usage.Requests >= key.DailyQuota {
+		usage.OverQuota++
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		return domain.RateLimitResult{
+			Limit:      key.RateLimit,
+			RetryAfter: midnight.Sub(now),
+		}, domain.ErrQuotaExceeded
+	}
+
+	result := s.limiter.Take("apikey_"+key.ID, cost, key.RateLimit, time.Minute)
+	if !result.Allowed {
+		usage.RateLimited++
+		return result, domain.ErrRateLimited
+	}
+
+	usage.Requests++
+	return result, nil
+}
+
+func (s *APIKeyService) Usage(id string, days int) (*domain.APIKeyUsageReport, error) {
+	key, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if days <= 0 {
+		days = 7
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &domain.APIKeyUsageReport{
+		KeyID:      key.ID,
+		Name:       key.Name,
+		DailyQuota: key.DailyQuota,
+	}
+	today := time.Now().UTC()
+	for i := 0; i < days; i++ {
+		date := today.AddDate(0, 0, -i).Format("2006-01-02")
+		report.Usage = append(report.Usage, *s.loadUsage(id, date))
+	}
+
+	return report, nil
+}
+
+func (s *APIKeyService) loadUsage(id, date string) *domain.APIKeyUsage {
+	usage, err := s.usage.Get(id, date)
+	if err != nil {
+		s.logger.Warn("Failed to load api key usage", zap.String("key_id", id), zap.Error(err))
+		return &domain.APIKeyUsage{Date: date}
+	}
+	return usage
+}
+
+func (s *APIKeyService) saveUsage(id string, usage *domain.APIKeyUsage) {
+	if err := s.usage.Save(id, usage); err != nil {
+		s.logger.Warn("Failed to store api key usage", zap.String("key_id", id), zap.Error(err))
+	}
+}
+
+func HashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
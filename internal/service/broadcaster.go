@@ -0,0 +1,157 @@
+package service
+
+import (
+	"sync"
+
+	"exchange-rate-service/internal/domain"
+)
+
+type RateBroadcaster struct {
+	mu          sync.RWMutex
+	subscribers map[int]chan domain.RateUpdate
+	queues      map[int]*updateQueue
+	nextID      int
+}
+
+func NewRateBroadcaster() *RateBroadcaster {
+	return &RateBroadcaster{
+		subscribers: make(map[int]chan domain.RateUpdate),
+		queues:      make(map[int]*updateQueue),
+	}
+}
+
+// Subscribe registers a listener for rate updates. The returned cancel
+// function must be called to release the subscription; it closes the channel.
+func (b *RateBroadcaster) Subscribe(buffer int) (<-chan domain.RateUpdate, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	ch := make(chan domain.RateUpdate, buffer)
+	b.subscribers[id] = ch
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers, id)
+			close(ch)
+		})
+	}
+
+	return ch, cancel
+}
+
+// SubscribeQueued registers a listener that never misses an update: updates
+// it has not received yet are queued without limit instead of dropped. Use
+// it for consumers that must see every snapshot and keep up on average. The
+// returned cancel function releases the subscription and closes the channel.
+func (b *RateBroadcaster) SubscribeQueued() (<-chan domain.RateUpdate, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	q := &updateQueue{
+		out:    make(chan domain.RateUpdate),
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	b.queues[id] = q
+	go q.forward()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.queues, id)
+			b.mu.Unlock()
+
+			close(q.done)
+		})
+	}
+
+	return q.out, cancel
+}
+
+// Publish delivers the update to every subscriber without blocking. Updates
+// are dropped for subscribers whose buffer is full and the number of such
+// drops is returned. Queued subscribers always receive it.
+func (b *RateBroadcaster) Publish(update domain.RateUpdate) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, q := range b.queues {
+		q.push(update)
+	}
+
+	dropped := 0
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- update:
+		default:
+			dropped++
+		}
+	}
+
+	return dropped
+}
+
+func (b *RateBroadcaster) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.subscribers) + len(b.queues)
+}
+
+// updateQueue buffers updates for one queued subscriber and forwards them in
+// order.
+type updateQueue struct {
+	mu      sync.Mutex
+	pending []domain.RateUpdate
+	out     chan domain.RateUpdate
+	notify  chan struct{}
+	done    chan struct{}
+}
+
+func (q *updateQueue) push(update domain.RateUpdate) {
+	q.mu.Lock()
+	q.pending = append(q.pending, update)
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *updateQueue) forward() {
+	defer close(q.out)
+
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.mu.Unlock()
+			select {
+			case <-q.notify:
+				continue
+			case <-q.done:
+				return
+			}
+		}
+		next := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		select {
+		case q.out <- next:
+		case <-q.done:
+			return
+		}
+	}
+}
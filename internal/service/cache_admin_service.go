@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// CacheAdminService backs the /admin/cache routes: it inspects and
+// invalidates the rates cache and forces refreshes from the provider.
+type CacheAdminService struct {
+	cache    domain.CacheInspector
+	exchange *ExchangeService
+	logger   *zap.Logger
+}
+
+func NewCacheAdminService(cache domain.CacheInspector, exchange *ExchangeService, logger *zap.Logger) *CacheAdminService {
+	return &CacheAdminService{
+		cache:    cache,
+		exchange: exchange,
+		logger:   logger,
+	}
+}
+
+func (s *CacheAdminService) List(prefix string) []domain.CacheEntry {
+	return s.cache.Entries(prefix)
+}
+
+func (s *CacheAdminService) Get(key string) (*domain.CacheEntry, error) {
+	return s.cache.Entry(key)
+}
+
+func (s *CacheAdminService) Invalidate(key string) error {
+	if _, err := s.cache.Entry(key); err != nil {
+		return err
+	}
+	if err := s.cache.Delete(key); err != nil {
+		return err
+	}
+
+	s.logger.Info("Invalidated cache entry", zap.String("key", key))
+	return nil
+}
+
+// InvalidatePrefix removes every entry whose key starts with prefix, e.g.
+// "rate_USD_" for all cached USD pairs. An empty prefix is rejected so the
+// whole cache is never dropped by accident.
+func (s *CacheAdminService) InvalidatePrefix(prefix string) (int, error) {
+	if prefix == "" {
+		return 0, domain.ValidationErrorf("prefix is required")
+	}
+
+	deleted := s.cache.DeletePrefix(prefix)
+	s.logger.Info("Invalidated cache entries", zap.String("prefix", prefix), zap.Int("deleted", deleted))
+	return deleted, nil
+}
+
+func (s *CacheAdminService) Refresh(ctx context.Context, baseCurrency string) (*domain.CacheRefreshResponse, error) {
+	return s.exchange.RefreshRates(ctx, baseCurrency)
+}
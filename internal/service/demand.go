@@ -0,0 +1,137 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+)
+
+// demandForgetScore is the score below which a pair or base is forgotten.
+const demandForgetScore = 0.01
+
+type DemandOptions struct {
+	// TopPairs and TopBases bound the hot set.
+	TopPairs int
+	TopBases int
+	// HalfLife is how long it takes a request to count for half as much.
+	HalfLife time.Duration
+	// MinScore keeps rarely requested pairs and bases out of the hot set
+	// even when there is room for them.
+	MinScore float64
+}
+
+type demandEntry struct {
+	score    float64
+	requests int64
+	lastSeen time.Time
+}
+
+// DemandTracker counts requests per pair and per base currency so the
+// updater can keep what clients actually ask for warm.
+type DemandTracker struct {
+	clock   clock.Clock
+	options DemandOptions
+
+	mu    sync.Mutex
+	pairs map[string]*demandEntry
+	bases map[string]*demandEntry
+}
+
+func NewDemandTracker(clk clock.Clock, options DemandOptions) *DemandTracker {
+	if options.HalfLife <= 0 {
+		options.HalfLife = time.Hour
+	}
+	return &DemandTracker{
+		clock:   clk,
+		options: options,
+		pairs:   make(map[string]*demandEntry),
+		bases:   make(map[string]*demandEntry),
+	}
+}
+
+// WithDemand records the pairs converted at latest rates and the bases of
+// latest rate tables as demand.
+func WithDemand(demand *DemandTracker) ExchangeOption {
+	return func(s *ExchangeService) {
+		s.demand = demand
+	}
+}
+
+func (d *DemandTracker) RecordPair(from, to string) {
+	d.record(d.pairs, from+to)
+}
+
+func (d *DemandTracker) RecordBase(base string) {
+	d.record(d.bases, base)
+}
+
+func (d *DemandTracker) record(entries map[string]*demandEntry, key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+	entry := entries[key]
+	if entry == nil {
+		entry = &demandEntry{}
+		entries[key] = entry
+	}
+	entry.score = d.decayed(entry, now) + 1
+	entry.requests++
+	entry.lastSeen = now
+}
+
+// HotSet returns the highest-scoring pairs and bases, best first.
+func (d *DemandTracker) HotSet() domain.HotSet {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+	return domain.HotSet{
+		Pairs: d.top(d.pairs, d.options.TopPairs, now),
+		Bases: d.top(d.bases, d.options.TopBases, now),
+	}
+}
+
+func (d *DemandTracker) top(entries map[string]*demandEntry, n int, now time.Time) []domain.DemandStat {
+	stats := []domain.DemandStat{}
+	for key, entry := range entries {
+		score := d.decayed(entry, now)
+		if score < demandForgetScore {
+			delete(entries, key)
+			continue
+		}
+		if score < d.options.MinScore {
+			continue
+		}
+		stats = append(stats, domain.DemandStat{
+			Key:      key,
+			Score:    score,
+			Requests: entry.requests,
+			LastSeen: entry.lastSeen,
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Score != stats[j].Score {
+			return stats[i].Score > stats[j].Score
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	if len(stats) > n {
+		stats = stats[:n]
+	}
+	return stats
+}
+
+// decayed returns the entry's score as of now.
+func (d *DemandTracker) decayed(entry *demandEntry, now time.Time) float64 {
+	if entry.lastSeen.IsZero() {
+		return entry.score
+	}
+	elapsed := now.Sub(entry.lastSeen)
+	return entry.score * math.Exp2(-float64(elapsed)/float64(d.options.HalfLife))
+}
@@ -2,32 +2,165 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/telemetry"
 	"exchange-rate-service/internal/utils"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
+// updaterCurrencies are the base currencies the background updater keeps
+// warm.
+var updaterCurrencies = []string{"USD", "EUR", "GBP", "INR", "JPY"}
+
+// trackedCurrencies returns the updater currencies that are enabled.
+func trackedCurrencies() []string {
+	currencies := make([]string, 0, len(updaterCurrencies))
+	for _, currency := range updaterCurrencies {
+		if utils.IsValidCurrency(currency) {
+			currencies = append(currencies, currency)
+		}
+	}
+	return currencies
+}
+
+// CacheTTLs is how long rates fetched from the provider stay cached.
+type CacheTTLs struct {
+	Latest     time.Duration
+	Historical time.Duration
+}
+
+var defaultCacheTTLs = CacheTTLs{Latest: time.Hour, Historical: 24 * time.Hour}
+
 type ExchangeService struct {
-	cacheRepo domain.CacheRepository
-	apiRepo   domain.ExchangeRepository
-	logger    *zap.Logger
+	cacheRepo   domain.CacheRepository
+	apiRepo     domain.ExchangeRepository
+	logger      *zap.Logger
+	broadcaster *RateBroadcaster
+	overrides   *OverrideService
+	markups     *MarkupService
+	guard       *RateGuard
+	history     domain.RateObservationRepository
+	demand      *DemandTracker
+	cacheTTLs   atomic.Pointer[CacheTTLs]
+
+	updaterMu     sync.Mutex
+	updater       UpdaterOptions
+	updaterReload chan struct{}
+
+	snapshotMu sync.Mutex
+	snapshots  map[string]map[string]float64
+
+	fixings        domain.FixingRepository
+	fixingSchedule FixingSchedule
+	fixingMu       sync.Mutex
+
+	lastRunMu sync.Mutex
+	lastRuns  map[string]time.Time
+}
+
+type ExchangeOption func(*ExchangeService)
+
+// WithOverrides applies manual rate overrides ahead of the cache and the
+// provider.
+func WithOverrides(overrides *OverrideService) ExchangeOption {
+	return func(s *ExchangeService) {
+		s.overrides = overrides
+	}
+}
+
+// WithMarkups prices conversions and latest rates with the caller's markup
+// profile. Rates are cached at mid, so the markup never leaks between
+// callers.
+func WithMarkups(markups *MarkupService) ExchangeOption {
+	return func(s *ExchangeService) {
+		s.markups = markups
+	}
+}
+
+// WithRateGuard vets latest rates from the provider before they are cached
+// or served.
+func WithRateGuard(guard *RateGuard) ExchangeOption {
+	return func(s *ExchangeService) {
+		s.guard = guard
+	}
+}
+
+// WithRateHistory records every rate fetched from the provider so
+// conversions and historical queries can be answered as of a past moment.
+func WithRateHistory(history domain.RateObservationRepository) ExchangeOption {
+	return func(s *ExchangeService) {
+		s.history = history
+	}
+}
+
+// WithCacheTTLs sets how long fetched rates stay cached.
+func WithCacheTTLs(ttls CacheTTLs) ExchangeOption {
+	return func(s *ExchangeService) {
+		s.SetCacheTTLs(ttls)
+	}
+}
+
+// SetCacheTTLs changes how long rates fetched from now on stay cached.
+// Zero durations keep their default.
+func (s *ExchangeService) SetCacheTTLs(ttls CacheTTLs) {
+	if ttls.Latest <= 0 {
+		ttls.Latest = defaultCacheTTLs.Latest
+	}
+	if ttls.Historical <= 0 {
+		ttls.Historical = defaultCacheTTLs.Historical
+	}
+	s.cacheTTLs.Store(&ttls)
 }
 
-func NewExchangeService(cacheRepo domain.CacheRepository, apiRepo domain.ExchangeRepository, logger *zap.Logger) *ExchangeService {
-	return &ExchangeService{
-		cacheRepo: cacheRepo,
-		apiRepo:   apiRepo,
-		logger:    logger,
+func NewExchangeService(cacheRepo domain.CacheRepository, apiRepo domain.ExchangeRepository, logger *zap.Logger, opts ...ExchangeOption) *ExchangeService {
+	s := &ExchangeService{
+		cacheRepo:     cacheRepo,
+		apiRepo:       apiRepo,
+		logger:        logger,
+		broadcaster:   NewRateBroadcaster(),
+		snapshots:     make(map[string]map[string]float64),
+		updater:       defaultUpdaterOptions(),
+		updaterReload: make(chan struct{}, 1),
+		lastRuns:      make(map[string]time.Time),
 	}
+	s.SetCacheTTLs(CacheTTLs{})
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// SubscribeUpdates returns a channel receiving the rate changes published by
+// the background updater. Call the returned function to unsubscribe.
+func (s *ExchangeService) SubscribeUpdates(buffer int) (<-chan domain.RateUpdate, func()) {
+	return s.broadcaster.Subscribe(buffer)
+}
+
+// SubscribeAllUpdates is SubscribeUpdates for consumers that must not miss
+// an update, such as webhook evaluation; see RateBroadcaster.SubscribeQueued.
+func (s *ExchangeService) SubscribeAllUpdates() (<-chan domain.RateUpdate, func()) {
+	return s.broadcaster.SubscribeQueued()
 }
 
-func (s *ExchangeService) ConvertCurrency(ctx context.Context, req *domain.ConversionRequest) (*domain.ConversionResponse, error) {
+func (s *ExchangeService) ConvertCurrency(ctx context.Context, req *domain.ConversionRequest) (_ *domain.ConversionResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "ExchangeService.ConvertCurrency",
+		attribute.String("currency.from", req.From),
+		attribute.String("currency.to", req.To),
+		attribute.String("date", req.Date),
+	)
+	defer func() { telemetry.End(span, err) }()
+
 	if !utils.IsValidCurrency(req.From) || !utils.IsValidCurrency(req.To) {
-		return nil, fmt.Errorf("unsupported currency pair: %s to %s", req.From, req.To)
+		return nil, domain.Errorf(domain.ErrUnsupportedCurrency, "unsupported currency pair: %s to %s", req.From, req.To)
 	}
 
 	if req.Amount == 0 {
@@ -35,15 +168,20 @@ func (s *ExchangeService) ConvertCurrency(ctx context.Context, req *domain.Conve
 	}
 
 	var rate *domain.ExchangeRate
-	var err error
 
-	if req.Date != "" {
+	switch {
+	case req.AsOf != nil:
+		rate, err = s.rateAsOf(req.From, req.To, req.Date, *req.AsOf, s.overridesAsOf(*req.AsOf))
+	case req.Date != "":
 		if err := utils.ValidateDate(req.Date); err != nil {
 			return nil, err
 		}
 
 		rate, err = s.getHistoricalRate(ctx, req.From, req.To, req.Date)
-	} else {
+	default:
+		if s.demand != nil {
+			s.demand.RecordPair(req.From, req.To)
+		}
 		rate, err = s.getLatestRate(ctx, req.From, req.To)
 	}
 
@@ -51,61 +189,188 @@ func (s *ExchangeService) ConvertCurrency(ctx context.Context, req *domain.Conve
 		return nil, err
 	}
 
-	convertedAmount := req.Amount * rate.Rate
-
-	return &domain.ConversionResponse{
-		Amount:       convertedAmount,
+	resp := &domain.ConversionResponse{
 		FromCurrency: req.From,
 		ToCurrency:   req.To,
 		Rate:         rate.Rate,
 		Date:         rate.Date,
 		Timestamp:    rate.Timestamp,
-	}, nil
+		Source:       rate.Source,
+		Consensus:    rate.Consensus,
+		ObservedAt:   rate.ObservedAt,
+	}
+	s.Price(ctx, resp, req.Amount)
+
+	return resp, nil
 }
 
-func (s *ExchangeService) GetLatestRates(ctx context.Context, baseCurrency string) (*domain.LatestRatesResponse, error) {
+// Price sets resp.Amount to amount converted at the mid rate in resp.Rate,
+// less the markup of the caller's profile. With a profile, resp.Pricing
+// records the mid, bid and ask rates and the fee taken; resp.Rate stays mid.
+func (s *ExchangeService) Price(ctx context.Context, resp *domain.ConversionResponse, amount float64) {
+	resp.Amount = amount * resp.Rate
+	resp.Pricing = nil
+	if s.markups == nil {
+		return
+	}
+
+	profile := s.markups.Resolve(ctx)
+	if profile == nil {
+		return
+	}
+
+	bps := MarkupBps(profile, resp.FromCurrency, resp.ToCurrency)
+	bid, ask := Spread(resp.Rate, bps)
+	resp.Amount = amount * bid
+	resp.Pricing = &domain.Pricing{
+		Profile:     profile.Name,
+		MidRate:     resp.Rate,
+		BidRate:     bid,
+		AskRate:     ask,
+		MarkupBps:   bps,
+		GrossAmount: amount * resp.Rate,
+		Fee:         amount*resp.Rate - resp.Amount,
+	}
+}
+
+// BatchConvert runs each conversion independently. The result slices are
+// parallel to reqs: a failed conversion leaves its result nil and sets the
+// matching error.
+func (s *ExchangeService) BatchConvert(ctx context.Context, reqs []domain.ConversionRequest) ([]*domain.ConversionResponse, []error) {
+	ctx, span := telemetry.Start(ctx, "ExchangeService.BatchConvert", attribute.Int("batch.size", len(reqs)))
+	defer span.End()
+
+	results := make([]*domain.ConversionResponse, len(reqs))
+	errs := make([]error, len(reqs))
+
+	for i := range reqs {
+		results[i], errs[i] = s.ConvertCurrency(ctx, &reqs[i])
+	}
+
+	return results, errs
+}
+
+func (s *ExchangeService) GetLatestRates(ctx context.Context, baseCurrency string) (_ *domain.LatestRatesResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "ExchangeService.GetLatestRates", attribute.String("currency.base", baseCurrency))
+	defer func() { telemetry.End(span, err) }()
+
 	if !utils.IsValidCurrency(baseCurrency) {
-		return nil, fmt.Errorf("unsupported base currency: %s", baseCurrency)
+		return nil, domain.Errorf(domain.ErrUnsupportedCurrency, "unsupported base currency: %s", baseCurrency)
+	}
+	if s.demand != nil {
+		s.demand.RecordBase(baseCurrency)
 	}
 
 	cacheKey := fmt.Sprintf("latest_rates_%s", baseCurrency)
 
 	var cachedRates map[string]float64
-	if err := s.cacheRepo.Get(cacheKey, &cachedRates); err == nil {
-		return &domain.LatestRatesResponse{
-			BaseCurrency: baseCurrency,
-			Rates:        cachedRates,
-			Timestamp:    time.Now(),
-			Date:         time.Now().Format("2006-01-02"),
-		}, nil
+	if s.cacheGet(ctx, cacheKey, &cachedRates) {
+		return s.latestRatesResponse(ctx, baseCurrency, cachedRates), nil
 	}
 
-	rates, err := s.apiRepo.GetAllLatestRates(ctx, baseCurrency)
+	s.log(ctx).Debug("Rate cache miss", zap.String("key", cacheKey))
+
+	rates, err := s.fetchLatestRates(ctx, baseCurrency)
 	if err != nil {
-		return nil, err
+		return nil, upstreamError(err)
 	}
 
-	s.cacheRepo.Set(cacheKey, rates, time.Hour)
+	s.cacheSet(ctx, cacheKey, rates, s.cacheTTLs.Load().Latest)
 
-	return &domain.LatestRatesResponse{
+	return s.latestRatesResponse(ctx, baseCurrency, rates), nil
+}
+
+// latestRatesResponse wraps a rate table, replacing overridden rates on a
+// copy so the cached table stays the market one, and adds bid and ask
+// tables for the caller's markup profile.
+func (s *ExchangeService) latestRatesResponse(ctx context.Context, baseCurrency string, rates map[string]float64) *domain.LatestRatesResponse {
+	now := time.Now()
+	resp := &domain.LatestRatesResponse{
 		BaseCurrency: baseCurrency,
 		Rates:        rates,
-		Timestamp:    time.Now(),
-		Date:         time.Now().Format("2006-01-02"),
-	}, nil
+		Timestamp:    now,
+		Date:         now.Format("2006-01-02"),
+	}
+	s.applyOverrides(resp)
+	s.applyMarkups(ctx, resp)
+	return resp
+}
+
+func (s *ExchangeService) applyOverrides(resp *domain.LatestRatesResponse) {
+	if s.overrides == nil {
+		return
+	}
+
+	rates := resp.Rates
+	for currency := range rates {
+		override := s.overrides.Lookup(resp.BaseCurrency, currency, resp.Date)
+		if override == nil {
+			continue
+		}
+		if resp.Sources == nil {
+			resp.Rates = make(map[string]float64, len(rates))
+			for c, r := range rates {
+				resp.Rates[c] = r
+			}
+			resp.Sources = make(map[string]string)
+		}
+		resp.Rates[currency] = override.Rate
+		resp.Sources[currency] = domain.SourceOverride
+	}
+}
+
+func (s *ExchangeService) applyMarkups(ctx context.Context, resp *domain.LatestRatesResponse) {
+	if s.markups == nil {
+		return
+	}
+
+	profile := s.markups.Resolve(ctx)
+	if profile == nil {
+		return
+	}
+
+	resp.Profile = profile.Name
+	resp.Bid = make(map[string]float64, len(resp.Rates))
+	resp.Ask = make(map[string]float64, len(resp.Rates))
+	for currency, rate := range resp.Rates {
+		resp.Bid[currency], resp.Ask[currency] = Spread(rate, MarkupBps(profile, resp.BaseCurrency, currency))
+	}
 }
 
 func (s *ExchangeService) GetHistoricalRates(ctx context.Context, from, to, startDate, endDate string) (*domain.HistoricalRatesResponse, error) {
+	return s.GetHistoricalRatesAsOf(ctx, from, to, startDate, endDate, nil)
+}
+
+// maxAsOfSpanDays bounds the dates one as-of historical query may cover.
+const maxAsOfSpanDays = 366
+
+// GetHistoricalRatesAsOf is GetHistoricalRates answered from the rate
+// history as it stood at asOf when asOf is set. Dates are then not limited
+// to the provider's 90-day window, but a query spans at most
+// maxAsOfSpanDays, and dates nothing was known for by asOf are left out.
+func (s *ExchangeService) GetHistoricalRatesAsOf(ctx context.Context, from, to, startDate, endDate string, asOf *time.Time) (_ *domain.HistoricalRatesResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "ExchangeService.GetHistoricalRates",
+		attribute.String("currency.from", from),
+		attribute.String("currency.to", to),
+		attribute.String("date.start", startDate),
+		attribute.String("date.end", endDate),
+	)
+	defer func() { telemetry.End(span, err) }()
+
 	if !utils.IsValidCurrency(from) || !utils.IsValidCurrency(to) {
-		return nil, fmt.Errorf("unsupported currency pair: %s to %s", from, to)
+		return nil, domain.Errorf(domain.ErrUnsupportedCurrency, "unsupported currency pair: %s to %s", from, to)
 	}
 
-	if err := utils.ValidateDate(startDate); err != nil {
-		return nil, fmt.Errorf("invalid start date: %v", err)
-	}
+	if asOf == nil {
+		if err := utils.ValidateDate(startDate); err != nil {
+			return nil, fmt.Errorf("invalid start date: %w", err)
+		}
 
-	if err := utils.ValidateDate(endDate); err != nil {
-		return nil, fmt.Errorf("invalid end date: %v", err)
+		if err := utils.ValidateDate(endDate); err != nil {
+			return nil, fmt.Errorf("invalid end date: %w", err)
+		}
+	} else if err := utils.ValidateDateSpan(startDate, endDate, maxAsOfSpanDays); err != nil {
+		return nil, err
 	}
 
 	dates, err := utils.GetDateRange(startDate, endDate)
@@ -113,11 +378,21 @@ func (s *ExchangeService) GetHistoricalRates(ctx context.Context, from, to, star
 		return nil, err
 	}
 
+	var overrides *OverrideSnapshot
+	if asOf != nil {
+		overrides = s.overridesAsOf(*asOf)
+	}
+
 	rates := make(map[string]domain.ExchangeRate)
 	for _, date := range dates {
-		rate, err := s.getHistoricalRate(ctx, from, to, date)
+		var rate *domain.ExchangeRate
+		if asOf != nil {
+			rate, err = s.rateAsOf(from, to, date, *asOf, overrides)
+		} else {
+			rate, err = s.getHistoricalRate(ctx, from, to, date)
+		}
 		if err != nil {
-			s.logger.Warn("Failed to get historical rate",
+			s.log(ctx).Warn("Failed to get historical rate",
 				zap.String("date", date),
 				zap.Error(err))
 			continue
@@ -131,76 +406,249 @@ func (s *ExchangeService) GetHistoricalRates(ctx context.Context, from, to, star
 		Rates:        rates,
 		StartDate:    startDate,
 		EndDate:      endDate,
+		AsOf:         asOf,
 	}, nil
 }
 
 func (s *ExchangeService) getLatestRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	if rate := s.overrideRate(from, to, time.Now().Format("2006-01-02")); rate != nil {
+		return rate, nil
+	}
+
 	cacheKey := fmt.Sprintf("rate_%s_%s_latest", from, to)
 
 	var cachedRate domain.ExchangeRate
-	if err := s.cacheRepo.Get(cacheKey, &cachedRate); err == nil {
+	if s.cacheGet(ctx, cacheKey, &cachedRate) {
 		return &cachedRate, nil
 	}
 
-	rate, err := s.apiRepo.GetLatestRate(ctx, from, to)
+	s.log(ctx).Debug("Rate cache miss", zap.String("key", cacheKey))
+
+	rate, err := s.fetchLatestRate(ctx, from, to)
 	if err != nil {
 		return nil, err
 	}
 
-	s.cacheRepo.Set(cacheKey, rate, time.Hour)
+	s.cacheSet(ctx, cacheKey, rate, s.cacheTTLs.Load().Latest)
+
+	return rate, nil
+}
+
+// refreshPair replaces the cached latest rate for from/to with a fresh one.
+func (s *ExchangeService) refreshPair(ctx context.Context, from, to string) error {
+	rate, err := s.fetchLatestRate(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	s.cacheSet(ctx, fmt.Sprintf("rate_%s_%s_latest", from, to), rate, s.cacheTTLs.Load().Latest)
+	return nil
+}
+
+// fetchLatestRate fetches the latest rate for from/to from the provider,
+// vets it and records it in the rate history.
+func (s *ExchangeService) fetchLatestRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	rate, err := s.apiRepo.GetLatestRate(ctx, from, to)
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+	if s.guard != nil {
+		value, ok := s.guard.FilterRate(ctx, from, to, rate.Rate)
+		if !ok {
+			return nil, fmt.Errorf("%w: rate for %s to %s failed sanity checks", domain.ErrUpstreamUnavailable, from, to)
+		}
+		rate.Rate = value
+	}
+	s.observe(*rate)
 
 	return rate, nil
 }
 
 func (s *ExchangeService) getHistoricalRate(ctx context.Context, from, to, date string) (*domain.ExchangeRate, error) {
+	if rate := s.overrideRate(from, to, date); rate != nil {
+		return rate, nil
+	}
+
 	cacheKey := fmt.Sprintf("rate_%s_%s_%s", from, to, date)
 
 	var cachedRate domain.ExchangeRate
-	if err := s.cacheRepo.Get(cacheKey, &cachedRate); err == nil {
+	if s.cacheGet(ctx, cacheKey, &cachedRate) {
 		return &cachedRate, nil
 	}
 
+	s.log(ctx).Debug("Rate cache miss", zap.String("key", cacheKey))
+
 	rate, err := s.apiRepo.GetHistoricalRate(ctx, from, to, date)
 	if err != nil {
-		return nil, err
+		return nil, upstreamError(err)
 	}
+	s.observe(*rate)
 
-	s.cacheRepo.Set(cacheKey, rate, 24*time.Hour)
+	s.cacheSet(ctx, cacheKey, rate, s.cacheTTLs.Load().Historical)
 
 	return rate, nil
 }
 
-func (s *ExchangeService) StartRateUpdater(ctx context.Context) {
-	ticker := time.NewTicker(4 * time.Hour)
-	defer ticker.Stop()
+// overrideRate returns the overridden rate for from/to on date, or nil when
+// the market rate applies.
+func (s *ExchangeService) overrideRate(from, to, date string) *domain.ExchangeRate {
+	if s.overrides == nil {
+		return nil
+	}
 
-	s.updateAllRates(ctx)
+	override := s.overrides.Lookup(from, to, date)
+	if override == nil {
+		return nil
+	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			s.updateAllRates(ctx)
-		}
+	return &domain.ExchangeRate{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         override.Rate,
+		Timestamp:    override.UpdatedAt,
+		Date:         date,
+		Source:       domain.SourceOverride,
 	}
 }
 
-func (s *ExchangeService) updateAllRates(ctx context.Context) {
-	currencies := []string{"USD", "EUR", "GBP", "INR", "JPY"}
+// RefreshRates fetches fresh rates for baseCurrency, or for every currency
+// the background updater tracks when it is empty, replacing whatever the
+// cache holds. Per-currency failures are reported in the response rather
+// than as an error.
+func (s *ExchangeService) RefreshRates(ctx context.Context, baseCurrency string) (*domain.CacheRefreshResponse, error) {
+	currencies := trackedCurrencies()
+	if baseCurrency != "" {
+		if !utils.IsValidCurrency(baseCurrency) {
+			return nil, domain.Errorf(domain.ErrUnsupportedCurrency, "unsupported base currency: %s", baseCurrency)
+		}
+		currencies = []string{baseCurrency}
+	}
 
-	for _, baseCurrency := range currencies {
-		rates, err := s.apiRepo.GetAllLatestRates(ctx, baseCurrency)
-		if err != nil {
-			s.logger.Error("Failed to update rates",
-				zap.String("base_currency", baseCurrency),
-				zap.Error(err))
+	resp := &domain.CacheRefreshResponse{
+		Refreshed: []string{},
+		Failed:    make(map[string]string),
+		Timestamp: time.Now(),
+	}
+	for _, currency := range currencies {
+		if err := s.updateRates(ctx, currency); err != nil {
+			resp.Failed[currency] = err.Error()
 			continue
 		}
+		resp.Refreshed = append(resp.Refreshed, currency)
+	}
+
+	return resp, nil
+}
+
+func (s *ExchangeService) updateRates(ctx context.Context, baseCurrency string) error {
+	rates, err := s.fetchLatestRates(ctx, baseCurrency)
+	if err != nil {
+		s.log(ctx).Error("Failed to update rates",
+			zap.String("base_currency", baseCurrency),
+			zap.Error(err))
+		return upstreamError(err)
+	}
+
+	cacheKey := fmt.Sprintf("latest_rates_%s", baseCurrency)
+	s.cacheSet(ctx, cacheKey, rates, s.cacheTTLs.Load().Latest)
+
+	// Drop single-pair entries so conversions pick up the new table instead
+	// of a rate fetched before it.
+	for currency := range rates {
+		s.cacheRepo.Delete(fmt.Sprintf("rate_%s_%s_latest", baseCurrency, currency))
+	}
 
-		cacheKey := fmt.Sprintf("latest_rates_%s", baseCurrency)
-		s.cacheRepo.Set(cacheKey, rates, time.Hour)
+	s.log(ctx).Info("Updated rates", zap.String("base_currency", baseCurrency))
+
+	s.publishSnapshot(baseCurrency, rates)
+	return nil
+}
+
+func (s *ExchangeService) publishSnapshot(baseCurrency string, rates map[string]float64) {
+	s.snapshotMu.Lock()
+	previous := s.snapshots[baseCurrency]
+	current := make(map[string]float64, len(rates))
+	changed := make(map[string]float64)
+	for currency, rate := range rates {
+		current[currency] = rate
+		if prev, exists := previous[currency]; !exists || prev != rate {
+			changed[currency] = rate
+		}
+	}
+	s.snapshots[baseCurrency] = current
+	s.snapshotMu.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+
+	dropped := s.broadcaster.Publish(domain.RateUpdate{
+		BaseCurrency: baseCurrency,
+		Rates:        current,
+		Changed:      changed,
+		Timestamp:    time.Now(),
+	})
+	if dropped > 0 {
+		s.logger.Warn("Dropped rate update for slow subscribers",
+			zap.String("base_currency", baseCurrency),
+			zap.Int("dropped", dropped))
+	}
+}
+
+// fetchLatestRates fetches the latest table for baseCurrency from the
+// provider, vets it and records it in the rate history.
+func (s *ExchangeService) fetchLatestRates(ctx context.Context, baseCurrency string) (map[string]float64, error) {
+	rates, err := s.apiRepo.GetAllLatestRates(ctx, baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if s.guard != nil {
+		rates = s.guard.FilterRates(ctx, baseCurrency, rates)
+	}
+
+	now := time.Now()
+	observed := make([]domain.ExchangeRate, 0, len(rates))
+	for currency, rate := range rates {
+		observed = append(observed, domain.ExchangeRate{
+			FromCurrency: baseCurrency,
+			ToCurrency:   currency,
+			Rate:         rate,
+			Timestamp:    now,
+			Date:         now.Format("2006-01-02"),
+		})
+	}
+	s.observe(observed...)
+
+	return rates, nil
+}
+
+// cacheGet reads key into dest inside a cache.get span, reporting whether it
+// was a hit.
+func (s *ExchangeService) cacheGet(ctx context.Context, key string, dest interface{}) bool {
+	_, span := telemetry.Start(ctx, "cache.get", attribute.String("cache.key", key))
+	defer span.End()
+
+	hit := s.cacheRepo.Get(key, dest) == nil
+	span.SetAttributes(attribute.Bool("cache.hit", hit))
+	return hit
+}
+
+func (s *ExchangeService) cacheSet(ctx context.Context, key string, value interface{}, expiration time.Duration) {
+	_, span := telemetry.Start(ctx, "cache.set", attribute.String("cache.key", key))
+	telemetry.End(span, s.cacheRepo.Set(key, value, expiration))
+}
+
+// log returns the request-scoped logger carried by ctx, falling back to the
+// service logger for background work.
+func (s *ExchangeService) log(ctx context.Context) *zap.Logger {
+	return requestid.Logger(ctx, s.logger)
+}
 
-		s.logger.Info("Updated rates", zap.String("base_currency", baseCurrency))
+// upstreamError marks a provider failure as ErrUpstreamUnavailable while
+// keeping the cause inspectable.
+func upstreamError(err error) error {
+	if errors.Is(err, domain.ErrUpstreamUnavailable) {
+		return err
 	}
+	return fmt.Errorf("%w: %w", domain.ErrUpstreamUnavailable, err)
 }
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// fixingActor is recorded as the author of captured fixings.
+	fixingActor = "scheduler"
+	// fixingAttempts and fixingRetryDelay bound how long the scheduler keeps
+	// retrying a capture that failed because the provider did.
+	fixingAttempts   = 5
+	fixingRetryDelay = time.Minute
+)
+
+// FixingSchedule is the daily wall-clock time, in Location, at which the
+// fixing is taken.
+type FixingSchedule struct {
+	Hour     int
+	Minute   int
+	Location *time.Location
+}
+
+// ParseFixingSchedule parses a HH:MM time in the named IANA timezone, such
+// as "16:00" in "Europe/Paris".
+func ParseFixingSchedule(at, timezone string) (FixingSchedule, error) {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return FixingSchedule{}, fmt.Errorf("fixing time must be HH:MM: %q", at)
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return FixingSchedule{}, fmt.Errorf("unknown fixing timezone %q: %w", timezone, err)
+	}
+	return FixingSchedule{Hour: t.Hour(), Minute: t.Minute(), Location: loc}, nil
+}
+
+// At returns the fixing instant on the day of t in the schedule's timezone.
+func (f FixingSchedule) At(t time.Time) time.Time {
+	t = t.In(f.Location)
+	return time.Date(t.Year(), t.Month(), t.Day(), f.Hour, f.Minute, 0, 0, f.Location)
+}
+
+// Next returns the first fixing instant strictly after t.
+func (f FixingSchedule) Next(t time.Time) time.Time {
+	next := f.At(t)
+	if !next.After(t) {
+		t = t.In(f.Location)
+		next = time.Date(t.Year(), t.Month(), t.Day()+1, f.Hour, f.Minute, 0, 0, f.Location)
+	}
+	return next
+}
+
+// WithFixings captures the daily fixing on schedule into repo once
+// StartFixingScheduler runs, and serves stored fixings.
+func WithFixings(repo domain.FixingRepository, schedule FixingSchedule) ExchangeOption {
+	return func(s *ExchangeService) {
+		s.fixings = repo
+		s.fixingSchedule = schedule
+	}
+}
+
+// FixingsEnabled reports whether fixings are captured and served.
+func (s *ExchangeService) FixingsEnabled() bool {
+	return s.fixings != nil
+}
+
+// StartFixingScheduler captures the fixing at every scheduled instant until
+// ctx is done. A capture that fails is retried a few times, a minute apart;
+// a fixing missed entirely is logged rather than taken late, since rates by
+// then no longer reflect the fixing time. An operator can still take it
+// with CaptureMissedFixing.
+func (s *ExchangeService) StartFixingScheduler(ctx context.Context) {
+	if s.fixings == nil {
+		return
+	}
+
+	clk := s.updater.Clock
+	for {
+		at := s.fixingSchedule.Next(clk.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-clk.After(at.Sub(clk.Now())):
+		}
+
+		for attempt := 1; ; attempt++ {
+			_, err := s.CaptureFixing(ctx, at)
+			if err == nil {
+				break
+			}
+			if attempt == fixingAttempts {
+				s.logger.Error("Fixing missed",
+					zap.String("date", at.Format("2006-01-02")),
+					zap.Int("attempts", attempt),
+					zap.Error(err))
+				break
+			}
+			s.logger.Warn("Fixing capture failed, retrying",
+				zap.String("date", at.Format("2006-01-02")),
+				zap.Int("attempt", attempt),
+				zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-clk.After(fixingRetryDelay):
+			}
+		}
+	}
+}
+
+// CaptureFixing takes the fixing for the day of at from the rate provider,
+// with a table for every base currency the updater tracks. A fixing is
+// captured whole or not at all, and only once per date; corrections go
+// through CorrectFixing.
+func (s *ExchangeService) CaptureFixing(ctx context.Context, at time.Time) (*domain.Fixing, error) {
+	return s.captureFixing(ctx, at, fixingActor, "")
+}
+
+// CaptureMissedFixing takes the fixing for date, whose scheduled instant
+// has passed without one, from the rates at the time of the call. actor
+// and reason are recorded on the fixing, since its rates postdate the
+// fixing time.
+func (s *ExchangeService) CaptureMissedFixing(ctx context.Context, date string, req *domain.FixingCaptureRequest, actor string) (*domain.Fixing, error) {
+	if strings.TrimSpace(actor) == "" {
+		return nil, domain.ValidationErrorf("actor is required")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, domain.ValidationErrorf("reason is required")
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, s.fixingSchedule.Location)
+	if err != nil {
+		return nil, domain.ValidationErrorf("invalid date format, expected YYYY-MM-DD")
+	}
+	if s.fixings == nil {
+		return nil, domain.Errorf(domain.ErrNotFound, "fixings are not enabled")
+	}
+
+	at := s.fixingSchedule.At(day)
+	if at.After(s.updater.Clock.Now()) {
+		return nil, domain.ValidationErrorf("fixing for %s is not due until %s", date, at.Format(time.RFC3339))
+	}
+	return s.captureFixing(ctx, at, actor, req.Reason)
+}
+
+func (s *ExchangeService) captureFixing(ctx context.Context, at time.Time, actor, reason string) (*domain.Fixing, error) {
+	if s.fixings == nil {
+		return nil, domain.Errorf(domain.ErrNotFound, "fixings are not enabled")
+	}
+
+	at = at.In(s.fixingSchedule.Location)
+	date := at.Format("2006-01-02")
+
+	currencies := trackedCurrencies()
+	rates := make(map[string]map[string]float64, len(currencies))
+	for _, baseCurrency := range currencies {
+		table, err := s.fetchLatestRates(ctx, baseCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("fixing %s: rates for %s: %w", date, baseCurrency, upstreamError(err))
+		}
+		rates[baseCurrency] = table
+	}
+
+	s.fixingMu.Lock()
+	defer s.fixingMu.Unlock()
+
+	fixing := &domain.Fixing{
+		Date:      date,
+		Version:   1,
+		FixedAt:   at,
+		Timezone:  s.fixingSchedule.Location.String(),
+		Rates:     rates,
+		CreatedBy: actor,
+		CreatedAt: s.updater.Clock.Now(),
+		Reason:    reason,
+	}
+	if err := s.fixings.Add(fixing); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, domain.Errorf(domain.ErrConflict, "fixing for %s already captured", date)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Fixing captured",
+		zap.String("date", date),
+		zap.Time("fixed_at", at),
+		zap.String("actor", actor))
+	return fixing, nil
+}
+
+// GetFixing returns the given version of the fixing for date, or the
+// current one when version is zero.
+func (s *ExchangeService) GetFixing(date string, version int) (*domain.Fixing, error) {
+	versions, err := s.FixingHistory(date)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == 0 {
+		version = len(versions)
+	}
+	if version < 1 || version > len(versions) {
+		return nil, domain.Errorf(domain.ErrNotFound, "fixing %s has no version %d", date, version)
+	}
+	return &versions[version-1], nil
+}
+
+// FixingHistory returns every version of the fixing for date, oldest first.
+func (s *ExchangeService) FixingHistory(date string) ([]domain.Fixing, error) {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, domain.ValidationErrorf("invalid date format, expected YYYY-MM-DD")
+	}
+	if s.fixings == nil {
+		return nil, domain.Errorf(domain.ErrNotFound, "no fixing for %s", date)
+	}
+
+	versions, err := s.fixings.Versions(date)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, domain.Errorf(domain.ErrNotFound, "no fixing for %s", date)
+	}
+	return versions, nil
+}
+
+// CorrectFixing adds a version of the fixing for date with the rates in req
+// replacing those of the current version. Earlier versions are kept as the
+// audit history.
+func (s *ExchangeService) CorrectFixing(date string, req *domain.FixingCorrectionRequest, actor string) (*domain.Fixing, error) {
+	if strings.TrimSpace(actor) == "" {
+		return nil, domain.ValidationErrorf("actor is required")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, domain.ValidationErrorf("reason is required")
+	}
+	if len(req.Rates) == 0 {
+		return nil, domain.ValidationErrorf("rates are required")
+	}
+	for baseCurrency, table := range req.Rates {
+		for currency, rate := range table {
+			if !utils.IsValidCurrency(baseCurrency) || !utils.IsValidCurrency(currency) || baseCurrency == currency {
+				return nil, domain.Errorf(domain.ErrUnsupportedCurrency, "unsupported currency pair: %s to %s", baseCurrency, currency)
+			}
+			if rate <= 0 {
+				return nil, domain.ValidationErrorf("rate for %s%s must be positive", baseCurrency, currency)
+			}
+		}
+	}
+
+	s.fixingMu.Lock()
+	defer s.fixingMu.Unlock()
+
+	current, err := s.GetFixing(date, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]map[string]float64, len(current.Rates))
+	for baseCurrency, table := range current.Rates {
+		rates[baseCurrency] = make(map[string]float64, len(table))
+		for currency, rate := range table {
+			rates[baseCurrency][currency] = rate
+		}
+	}
+	for baseCurrency, table := range req.Rates {
+		if rates[baseCurrency] == nil {
+			rates[baseCurrency] = make(map[string]float64, len(table))
+		}
+		for currency, rate := range table {
+			rates[baseCurrency][currency] = rate
+		}
+	}
+
+	fixing := &domain.Fixing{
+		Date:      date,
+		Version:   current.Version + 1,
+		FixedAt:   current.FixedAt,
+		Timezone:  current.Timezone,
+		Rates:     rates,
+		CreatedBy: actor,
+		CreatedAt: s.updater.Clock.Now(),
+		Reason:    req.Reason,
+	}
+	if err := s.fixings.Add(fixing); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Fixing corrected",
+		zap.String("date", date),
+		zap.Int("version", fixing.Version),
+		zap.String("actor", actor),
+		zap.String("reason", req.Reason))
+	return fixing, nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+type markupProfileKey struct{}
+
+// WithMarkupProfile returns a copy of ctx pricing requests with the named
+// markup profile.
+func WithMarkupProfile(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, markupProfileKey{}, name)
+}
+
+// MarkupProfileFromContext returns the profile name set by
+// WithMarkupProfile, or "" when there is none.
+func MarkupProfileFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(markupProfileKey{}).(string)
+	return name
+}
+
+// MarkupService resolves the markup a caller pays on a pair. Profiles are
+// fixed at construction; requests without a profile of their own use the
+// default one.
+type MarkupService struct {
+	profiles       map[string]domain.MarkupProfile
+	defaultProfile string
+	logger         *zap.Logger
+}
+
+func NewMarkupService(profiles []domain.MarkupProfile, defaultProfile string, logger *zap.Logger) (*MarkupService, error) {
+	s := &MarkupService{
+		profiles:       make(map[string]domain.MarkupProfile, len(profiles)),
+		defaultProfile: defaultProfile,
+		logger:         logger,
+	}
+
+	for _, profile := range profiles {
+		if err := normalizeProfile(&profile); err != nil {
+			return nil, err
+		}
+		if _, exists := s.profiles[profile.Name]; exists {
+			return nil, domain.ValidationErrorf("duplicate markup profile: %s", profile.Name)
+		}
+		s.profiles[profile.Name] = profile
+	}
+
+	if defaultProfile != "" {
+		if _, exists := s.profiles[defaultProfile]; !exists {
+			return nil, domain.ValidationErrorf("default markup profile %s is not defined", defaultProfile)
+		}
+	}
+
+	return s, nil
+}
+
+// Profiles returns every profile sorted by name.
+func (s *MarkupService) Profiles() []domain.MarkupProfile {
+	profiles := make([]domain.MarkupProfile, 0, len(s.profiles))
+	for _, profile := range s.profiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles
+}
+
+func (s *MarkupService) Profile(name string) (*domain.MarkupProfile, error) {
+	profile, exists := s.profiles[name]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	return &profile, nil
+}
+
+func (s *MarkupService) DefaultProfile() string {
+	return s.defaultProfile
+}
+
+// Resolve returns the profile pricing the request carried by ctx. An unknown
+// profile falls back to the default so a stale key setting never fails a
+// request. It returns nil when no profile applies.
+func (s *MarkupService) Resolve(ctx context.Context) *domain.MarkupProfile {
+	name := MarkupProfileFromContext(ctx)
+	if name != "" {
+		if profile, exists := s.profiles[name]; exists {
+			return &profile
+		}
+		requestid.Logger(ctx, s.logger).Warn("Unknown markup profile, using default",
+			zap.String("profile", name),
+			zap.String("default", s.defaultProfile))
+	}
+
+	if s.defaultProfile == "" {
+		return nil
+	}
+	profile := s.profiles[s.defaultProfile]
+	return &profile
+}
+
+// MarkupBps returns the markup profile charges on from/to in basis points.
+// A rule naming the pair, in either direction, wins over a rule naming one of
+// its currencies, which wins over the profile default. Within each kind the
+// first matching rule applies.
+func MarkupBps(profile *domain.MarkupProfile, from, to string) float64 {
+	for _, rule := range profile.Rules {
+		for _, pair := range rule.Pairs {
+			if pair == from+to || pair == to+from {
+				return rule.Bps()
+			}
+		}
+	}
+	for _, rule := range profile.Rules {
+		for _, currency := range rule.Currencies {
+			if currency == from || currency == to {
+				return rule.Bps()
+			}
+		}
+	}
+	return profile.Default.Bps()
+}
+
+// Spread returns the bid and ask around mid for a markup in basis points.
+func Spread(mid, bps float64) (bid, ask float64) {
+	margin := bps / 10000
+	return mid * (1 - margin), mid * (1 + margin)
+}
+
+func normalizeProfile(profile *domain.MarkupProfile) error {
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		return domain.ValidationErrorf("markup profile name is required")
+	}
+	if err := validateMarkup(profile.Name, profile.Default); err != nil {
+		return err
+	}
+
+	rules := make([]domain.MarkupRule, len(profile.Rules))
+	for i, rule := range profile.Rules {
+		if err := validateMarkup(profile.Name, rule.Markup); err != nil {
+			return err
+		}
+		if len(rule.Pairs) == 0 && len(rule.Currencies) == 0 {
+			return domain.ValidationErrorf("markup profile %s: rule %d names no pairs or currencies", profile.Name, i)
+		}
+
+		rules[i] = domain.MarkupRule{Markup: rule.Markup}
+		for _, pair := range rule.Pairs {
+			from, to, err := utils.ParsePair(pair)
+			if err != nil {
+				return err
+			}
+			rules[i].Pairs = append(rules[i].Pairs, from+to)
+		}
+		for _, currency := range rule.Currencies {
+			currency = strings.ToUpper(strings.TrimSpace(currency))
+			if !utils.IsValidCurrency(currency) {
+				return domain.Errorf(domain.ErrUnsupportedCurrency, "markup profile %s: unsupported currency %s", profile.Name, currency)
+			}
+			rules[i].Currencies = append(rules[i].Currencies, currency)
+		}
+	}
+	profile.Rules = rules
+
+	return nil
+}
+
+func validateMarkup(profile string, markup domain.Markup) error {
+	if markup.BasisPoints != 0 && markup.Percent != 0 {
+		return domain.ValidationErrorf("markup profile %s: set bps or percent, not both", profile)
+	}
+	if markup.BasisPoints < 0 || markup.Percent < 0 {
+		return domain.ValidationErrorf("markup profile %s: markup cannot be negative", profile)
+	}
+	if markup.Bps() >= 10000 {
+		return domain.ValidationErrorf("markup profile %s: markup must be below 100%%", profile)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	overrideCreated = "created"
+	overrideUpdated = "updated"
+	overrideDeleted = "deleted"
+)
+
+// OverrideService manages manual rate overrides. Every change is recorded in
+// an audit trail naming the actor and the reason.
+type OverrideService struct {
+	repo   domain.RateOverrideRepository
+	logger *zap.Logger
+
+	mu sync.Mutex
+}
+
+func NewOverrideService(repo domain.RateOverrideRepository, logger *zap.Logger) *OverrideService {
+	return &OverrideService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *OverrideService) Create(req *domain.RateOverrideRequest, actor string) (*domain.RateOverride, error) {
+	override := &domain.RateOverride{ID: utils.NewID()}
+	if err := applyOverrideRequest(override, req, actor); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkOverlap(override); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	override.CreatedBy = actor
+	override.CreatedAt = now
+	override.UpdatedAt = now
+	if err := s.repo.Create(override, auditEntry(overrideCreated, actor, req.Reason, override)); err != nil {
+		return nil, err
+	}
+
+	s.logChange(overrideCreated, actor, req.Reason, override)
+	return override, nil
+}
+
+func (s *OverrideService) Get(id string) (*domain.RateOverride, error) {
+	return s.repo.Get(id)
+}
+
+// List returns all overrides, or only those for pair when it is set.
+func (s *OverrideService) List(pair string) ([]domain.RateOverride, error) {
+	overrides, err := s.repo.List()
+	if err != nil || pair == "" {
+		return overrides, err
+	}
+
+	pair = strings.ToUpper(pair)
+	filtered := []domain.RateOverride{}
+	for _, override := range overrides {
+		if override.Pair == pair {
+			filtered = append(filtered, override)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *OverrideService) Update(id string, req *domain.RateOverrideRequest, actor string) (*domain.RateOverride, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	override, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyOverrideRequest(override, req, actor); err != nil {
+		return nil, err
+	}
+	if err := s.checkOverlap(override); err != nil {
+		return nil, err
+	}
+
+	override.UpdatedBy = actor
+	override.UpdatedAt = time.Now()
+	if err := s.repo.Update(override, auditEntry(overrideUpdated, actor, req.Reason, override)); err != nil {
+		return nil, err
+	}
+
+	s.logChange(overrideUpdated, actor, req.Reason, override)
+	return override, nil
+}
+
+func (s *OverrideService) Delete(id, actor, reason string) error {
+	if strings.TrimSpace(actor) == "" {
+		return domain.ValidationErrorf("actor is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	override, err := s.repo.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id, auditEntry(overrideDeleted, actor, reason, override)); err != nil {
+		return err
+	}
+
+	s.logChange(overrideDeleted, actor, reason, override)
+	return nil
+}
+
+// Audit returns the audit trail, oldest first, optionally for one override.
+func (s *OverrideService) Audit(overrideID string) ([]domain.OverrideAuditEntry, error) {
+	entries, err := s.repo.ListAudit()
+	if err != nil || overrideID == "" {
+		return entries, err
+	}
+
+	filtered := []domain.OverrideAuditEntry{}
+	for _, entry := range entries {
+		if entry.OverrideID == overrideID {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
+
+// Lookup returns the override in force for from/to on date, or nil. An
+// override of the inverse pair applies inverted, unless from/to has its own.
+func (s *OverrideService) Lookup(from, to, date string) *domain.RateOverride {
+	return lookupOverride(from, to, date, s.lookupPair)
+}
+
+func (s *OverrideService) lookupPair(pair, date string) *domain.RateOverride {
+	overrides, err := s.repo.ListPair(pair)
+	if err != nil {
+		s.logger.Error("Failed to list rate overrides", zap.String("pair", pair), zap.Error(err))
+		return nil
+	}
+	return coveringOverride(overrides, date)
+}
+
+// OverrideSnapshot holds the overrides as they stood at one moment.
+type OverrideSnapshot struct {
+	byPair map[string][]domain.RateOverride
+}
+
+// AsOf rebuilds the overrides in force at at by replaying the audit trail,
+// so an override since changed or deleted still applies to rates asked for
+// as of a time it was in place.
+func (s *OverrideService) AsOf(at time.Time) (*OverrideSnapshot, error) {
+	entries, err := s.repo.ListAudit()
+	if err != nil {
+		return nil, err
+	}
+
+	state := make(map[string]domain.RateOverride)
+	for _, entry := range entries {
+		if entry.Timestamp.After(at) {
+			continue
+		}
+		switch entry.Action {
+		case overrideCreated, overrideUpdated:
+			state[entry.OverrideID] = entry.Override
+		case overrideDeleted:
+			delete(state, entry.OverrideID)
+		}
+	}
+
+	snapshot := &OverrideSnapshot{byPair: make(map[string][]domain.RateOverride)}
+	for _, override := range state {
+		snapshot.byPair[override.Pair] = append(snapshot.byPair[override.Pair], override)
+	}
+	return snapshot, nil
+}
+
+// Lookup returns the override in force for from/to on date in the snapshot,
+// or nil, resolving inverse pairs as OverrideService.Lookup does.
+func (o *OverrideSnapshot) Lookup(from, to, date string) *domain.RateOverride {
+	return lookupOverride(from, to, date, func(pair, date string) *domain.RateOverride {
+		return coveringOverride(o.byPair[pair], date)
+	})
+}
+
+// lookupOverride resolves from/to on date with find, falling back to the
+// inverse pair's override inverted.
+func lookupOverride(from, to, date string, find func(pair, date string) *domain.RateOverride) *domain.RateOverride {
+	if override := find(from+to, date); override != nil {
+		return override
+	}
+
+	inverse := find(to+from, date)
+	if inverse == nil {
+		return nil
+	}
+	inverse.Pair = from + to
+	inverse.FromCurrency = from
+	inverse.ToCurrency = to
+	inverse.Rate = 1 / inverse.Rate
+	return inverse
+}
+
+func coveringOverride(overrides []domain.RateOverride, date string) *domain.RateOverride {
+	for i := range overrides {
+		if overrides[i].Covers(date) {
+			override := overrides[i]
+			return &override
+		}
+	}
+	return nil
+}
+
+// checkOverlap rejects a range that intersects another override of the same
+// pair, so a lookup never has to choose between two rates.
+func (s *OverrideService) checkOverlap(override *domain.RateOverride) error {
+	overrides, err := s.repo.ListPair(override.Pair)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range overrides {
+		if other.ID == override.ID {
+			continue
+		}
+		if override.StartDate <= other.EndDate && other.StartDate <= override.EndDate {
+			return domain.ValidationErrorf("override overlaps %s (%s to %s)", other.ID, other.StartDate, other.EndDate)
+		}
+	}
+	return nil
+}
+
+func auditEntry(action, actor, reason string, override *domain.RateOverride) *domain.OverrideAuditEntry {
+	return &domain.OverrideAuditEntry{
+		ID:         utils.NewID(),
+		OverrideID: override.ID,
+		Action:     action,
+		Actor:      actor,
+		Reason:     reason,
+		Override:   *override,
+		Timestamp:  time.Now(),
+	}
+}
+
+func (s *OverrideService) logChange(action, actor, reason string, override *domain.RateOverride) {
+	s.logger.Info("Rate override "+action,
+		zap.String("override_id", override.ID),
+		zap.String("pair", override.Pair),
+		zap.Float64("rate", override.Rate),
+		zap.String("actor", actor),
+		zap.String("reason", reason))
+}
+
+func applyOverrideRequest(override *domain.RateOverride, req *domain.RateOverrideRequest, actor string) error {
+	if strings.TrimSpace(actor) == "" {
+		return domain.ValidationErrorf("actor is required")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return domain.ValidationErrorf("reason is required")
+	}
+	if req.Rate <= 0 {
+		return domain.ValidationErrorf("rate must be positive")
+	}
+
+	from, to, err := utils.ParsePair(req.Pair)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return domain.ValidationErrorf("pair must use two different currencies")
+	}
+
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return domain.ValidationErrorf("start_date must be YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return domain.ValidationErrorf("end_date must be YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return domain.ValidationErrorf("end_date cannot be before start_date")
+	}
+
+	override.Pair = from + to
+	override.FromCurrency = from
+	override.ToCurrency = to
+	override.Rate = req.Rate
+	override.StartDate = req.StartDate
+	override.EndDate = req.EndDate
+	override.Reason = req.Reason
+	return nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// quoteRetention keeps quotes around after expiry so executing a stale quote
+// reports "expired" rather than "not found".
+const quoteRetention = 24 * time.Hour
+
+// quotePruneEvery is how often Create drops quotes past retention.
+const quotePruneEvery = time.Hour
+
+// QuoteService issues rate quotes that lock the current rate for a fixed
+// time.
+type QuoteService struct {
+	exchange *ExchangeService
+	store    domain.QuoteRepository
+	clock    clock.Clock
+	ttl      time.Duration
+	logger   *zap.Logger
+
+	mu     sync.Mutex
+	pruned time.Time
+}
+
+func NewQuoteService(exchange *ExchangeService, store domain.QuoteRepository, clk clock.Clock, ttl time.Duration, logger *zap.Logger) *QuoteService {
+	return &QuoteService{
+		exchange: exchange,
+		store:    store,
+		clock:    clk,
+		ttl:      ttl,
+		logger:   logger,
+	}
+}
+
+func (s *QuoteService) Create(ctx context.Context, req *domain.QuoteRequest) (*domain.Quote, error) {
+	if req.Amount < 0 {
+		return nil, domain.ValidationErrorf("amount cannot be negative")
+	}
+	amount := req.Amount
+	if amount == 0 {
+		amount = 1
+	}
+
+	conversion, err := s.exchange.ConvertCurrency(ctx, &domain.ConversionRequest{
+		From:   req.From,
+		To:     req.To,
+		Amount: amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	quote := &domain.Quote{
+		ID:              utils.NewID(),
+		Pair:            conversion.FromCurrency + conversion.ToCurrency,
+		FromCurrency:    conversion.FromCurrency,
+		ToCurrency:      conversion.ToCurrency,
+		Rate:            conversion.Rate,
+		Amount:          amount,
+		ConvertedAmount: conversion.Amount,
+		Source:          conversion.Source,
+		Pricing:         conversion.Pricing,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(s.ttl),
+	}
+	s.prune(now)
+	if err := s.store.Create(quote); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+func (s *QuoteService) Get(id string) (*domain.Quote, error) {
+	return s.store.Get(id)
+}
+
+// Execute converts at the locked rate. A quote can be executed once and only
+// before it expires; the store checks both in one step.
+func (s *QuoteService) Execute(ctx context.Context, id string) (*domain.Quote, error) {
+	quote, err := s.store.Execute(id, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Quote executed",
+		zap.String("quote_id", id),
+		zap.String("pair", quote.Pair),
+		zap.Float64("rate", quote.Rate),
+		zap.Float64("amount", quote.Amount))
+	return quote, nil
+}
+
+// prune drops quotes past retention, at most every quotePruneEvery.
+func (s *QuoteService) prune(now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.pruned) < quotePruneEvery {
+		s.mu.Unlock()
+		return
+	}
+	s.pruned = now
+	s.mu.Unlock()
+
+	if err := s.store.Prune(now.Add(-quoteRetention)); err != nil {
+		s.logger.Warn("Failed to prune expired quotes", zap.Error(err))
+	}
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// guardHistoryDays is how many daily closes per pair the jump check keeps.
+const guardHistoryDays = 30
+
+// guardConfirmTolerance is how close, as a fraction, consecutive rejected
+// rates must stay to count as the same move.
+const guardConfirmTolerance = 0.01
+
+type GuardOptions struct {
+	// InverseTolerance is how far USDINR times INRUSD may stray from 1, as a
+	// fraction (0.05 is 5%).
+	InverseTolerance float64
+	// MaxSigma rejects a day-over-day move larger than this many standard
+	// deviations of the pair's recent daily moves.
+	MaxSigma float64
+	// MinHistory is how many daily moves a pair needs before moves are
+	// judged against them.
+	MinHistory int
+	// ConfirmAfter accepts a rejected move once the pair is quoted at that
+	// level this many times in a row, and re-baselines the pair on it. Zero
+	// leaves the move rejected until someone accepts it on review.
+	ConfirmAfter int
+}
+
+type dailyClose struct {
+	date string
+	rate float64
+}
+
+type pendingMove struct {
+	rate  float64
+	count int
+}
+
+// RateGuard vets upstream rates before they are cached. A rejected rate is
+// replaced by the last good one, logged as an alert and recorded for review.
+type RateGuard struct {
+	repo    domain.RateAnomalyRepository
+	clock   clock.Clock
+	options GuardOptions
+	logger  *zap.Logger
+
+	mu       sync.Mutex
+	lastGood map[string]float64
+	history  map[string][]dailyClose
+	pending  map[string]pendingMove
+}
+
+func NewRateGuard(repo domain.RateAnomalyRepository, clk clock.Clock, options GuardOptions, logger *zap.Logger) *RateGuard {
+	return &RateGuard{
+		repo:     repo,
+		clock:    clk,
+		options:  options,
+		logger:   logger,
+		lastGood: make(map[string]float64),
+		history:  make(map[string][]dailyClose),
+		pending:  make(map[string]pendingMove),
+	}
+}
+
+// FilterRates vets every rate in a table quoted against base and returns the
+// table to cache. Rejected rates fall back to their last good value, or are
+// left out when there is none. rates itself is not modified.
+func (g *RateGuard) FilterRates(ctx context.Context, base string, rates map[string]float64) map[string]float64 {
+	filtered := make(map[string]float64, len(rates))
+	for currency, rate := range rates {
+		if accepted, ok := g.FilterRate(ctx, base, currency, rate); ok {
+			filtered[currency] = accepted
+		}
+	}
+	return filtered
+}
+
+// FilterRate vets one rate. It returns the rate to use and false when the
+// rate was rejected and there is no last good value to serve instead. A move
+// that keeps being rejected is accepted once confirmed, see ConfirmAfter.
+func (g *RateGuard) FilterRate(ctx context.Context, from, to string, rate float64) (float64, bool) {
+	if from == to {
+		return rate, true
+	}
+
+	g.mu.Lock()
+	pair := from + to
+	check, detail := g.check(from, to, rate)
+	if check == "" {
+		delete(g.pending, pair)
+		g.accept(pair, rate)
+		g.mu.Unlock()
+		return rate, true
+	}
+
+	if check != domain.AnomalyNonPositive && g.confirm(pair, rate) {
+		requestid.Logger(ctx, g.logger).Warn("Rate move confirmed, re-baselining pair",
+			zap.String("pair", pair),
+			zap.Float64("rate", rate),
+			zap.Float64("last_good", g.lastGood[pair]),
+			zap.Int("confirmations", g.options.ConfirmAfter))
+		g.rebaseline(from, to, rate)
+		g.mu.Unlock()
+		return rate, true
+	}
+
+	lastGood, ok := g.lastGood[pair]
+	g.mu.Unlock()
+
+	// Recording writes the anomaly store, so it happens outside g.mu and
+	// never holds up other rates being vetted.
+	now := g.clock.Now()
+	g.record(ctx, &domain.RateAnomaly{
+		ID:         utils.NewID(),
+		Pair:       pair,
+		Check:      check,
+		Rate:       rate,
+		LastGood:   lastGood,
+		Detail:     detail,
+		DetectedAt: now,
+		LastSeenAt: now,
+		Count:      1,
+	})
+	return lastGood, ok
+}
+
+// List returns recorded anomalies, newest first, optionally for one pair
+// and only those nobody has reviewed yet.
+func (g *RateGuard) List(pair string, unreviewed bool) ([]domain.RateAnomaly, error) {
+	anomalies, err := g.repo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	pair = strings.ToUpper(pair)
+	result := make([]domain.RateAnomaly, 0, len(anomalies))
+	for i := len(anomalies) - 1; i >= 0; i-- {
+		if pair != "" && anomalies[i].Pair != pair {
+			continue
+		}
+		if unreviewed && anomalies[i].ReviewedAt != nil {
+			continue
+		}
+		result = append(result, anomalies[i])
+	}
+	return result, nil
+}
+
+// Review marks an anomaly as looked at by actor. With accept, the rejected
+// rate is taken as genuine: the pair and its inverse are re-baselined on it,
+// so the next refresh serves the new level.
+func (g *RateGuard) Review(id, actor string, accept bool) (*domain.RateAnomaly, error) {
+	if strings.TrimSpace(actor) == "" {
+		return nil, domain.ValidationErrorf("the reviewing user is required")
+	}
+
+	anomaly, err := g.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if accept && !(anomaly.Rate > 0 && !math.IsInf(anomaly.Rate, 1)) {
+		return nil, domain.ValidationErrorf("rate %v is not a positive number and cannot be accepted", anomaly.Rate)
+	}
+
+	now := g.clock.Now()
+	anomaly.ReviewedBy = actor
+	anomaly.ReviewedAt = &now
+	anomaly.Accepted = accept
+	if err := g.repo.Update(anomaly); err != nil {
+		return nil, err
+	}
+
+	if accept {
+		g.mu.Lock()
+		g.rebaseline(anomaly.Pair[:3], anomaly.Pair[3:], anomaly.Rate)
+		g.mu.Unlock()
+
+		g.logger.Info("Rate anomaly accepted, re-baselining pair",
+			zap.String("anomaly_id", anomaly.ID),
+			zap.String("pair", anomaly.Pair),
+			zap.Float64("rate", anomaly.Rate),
+			zap.String("actor", actor))
+	}
+	return anomaly, nil
+}
+
+// check returns the failed check and why, or "" when rate passes. Callers
+// hold g.mu.
+func (g *RateGuard) check(from, to string, rate float64) (string, string) {
+	if !(rate > 0) || math.IsInf(rate, 1) {
+		return domain.AnomalyNonPositive, fmt.Sprintf("rate %v is not a positive number", rate)
+	}
+
+	if inverse, ok := g.lastGood[to+from]; ok && g.options.InverseTolerance > 0 {
+		product := rate * inverse
+		if math.Abs(product-1) > g.options.InverseTolerance {
+			return domain.AnomalyInverse, fmt.Sprintf("%s%s x %s%s = %.4f, expected 1 within %.2f%%", from, to, to, from, product, g.options.InverseTolerance*100)
+		}
+	}
+
+	if g.options.MaxSigma > 0 {
+		if sigmas, ok := g.jump(from+to, rate); ok && sigmas > g.options.MaxSigma {
+			return domain.AnomalyJump, fmt.Sprintf("day-over-day move of %.1f sigma exceeds %.1f", sigmas, g.options.MaxSigma)
+		}
+	}
+
+	return "", ""
+}
+
+// jump measures the move from the previous day's close to rate in standard
+// deviations of the pair's daily log returns. It reports false until the
+// pair has MinHistory returns with some variation.
+func (g *RateGuard) jump(pair string, rate float64) (float64, bool) {
+	closes := g.history[pair]
+	if n := len(closes); n > 0 && closes[n-1].date == g.today() {
+		closes = closes[:n-1]
+	}
+	if len(closes) < 2 || len(closes)-1 < g.options.MinHistory {
+		return 0, false
+	}
+
+	returns := make([]float64, len(closes)-1)
+	var mean float64
+	for i := 1; i < len(closes); i++ {
+		returns[i-1] = math.Log(closes[i].rate / closes[i-1].rate)
+		mean += returns[i-1]
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	sigma := math.Sqrt(variance / float64(len(returns)))
+	if sigma == 0 {
+		return 0, false
+	}
+
+	move := math.Log(rate / closes[len(closes)-1].rate)
+	return math.Abs(move-mean) / sigma, true
+}
+
+// accept stores rate as the pair's last good value and today's close.
+// Callers hold g.mu.
+func (g *RateGuard) accept(pair string, rate float64) {
+	g.lastGood[pair] = rate
+
+	today := g.today()
+	closes := g.history[pair]
+	if n := len(closes); n > 0 && closes[n-1].date == today {
+		closes[n-1].rate = rate
+	} else {
+		closes = append(closes, dailyClose{date: today, rate: rate})
+	}
+	if len(closes) > guardHistoryDays {
+		closes = closes[len(closes)-guardHistoryDays:]
+	}
+	g.history[pair] = closes
+}
+
+// confirm counts rate towards a pending move for pair and reports whether
+// the move is now confirmed. Callers hold g.mu.
+func (g *RateGuard) confirm(pair string, rate float64) bool {
+	if g.options.ConfirmAfter <= 0 {
+		return false
+	}
+
+	move := g.pending[pair]
+	if move.count > 0 && math.Abs(rate/move.rate-1) <= guardConfirmTolerance {
+		move.count++
+	} else {
+		move.count = 1
+	}
+	move.rate = rate
+
+	if move.count >= g.options.ConfirmAfter {
+		delete(g.pending, pair)
+		return true
+	}
+	g.pending[pair] = move
+	return false
+}
+
+// rebaseline restarts the history of from/to at rate, and of its inverse at
+// 1/rate when the inverse is tracked, so neither is judged against the old
+// level. Callers hold g.mu.
+func (g *RateGuard) rebaseline(from, to string, rate float64) {
+	pair, inverse := from+to, to+from
+
+	delete(g.history, pair)
+	delete(g.pending, pair)
+	g.accept(pair, rate)
+
+	if _, ok := g.lastGood[inverse]; ok {
+		delete(g.history, inverse)
+		delete(g.pending, inverse)
+		g.accept(inverse, 1/rate)
+	}
+}
+
+func (g *RateGuard) record(ctx context.Context, anomaly *domain.RateAnomaly) {
+	log := requestid.Logger(ctx, g.logger)
+
+	recorded, err := g.repo.Record(anomaly)
+	if err != nil {
+		log.Warn("Failed to record rate anomaly", zap.String("anomaly_id", anomaly.ID), zap.Error(err))
+		recorded = anomaly
+	}
+
+	log.Error("Rate anomaly rejected",
+		zap.String("anomaly_id", recorded.ID),
+		zap.String("pair", recorded.Pair),
+		zap.String("check", recorded.Check),
+		zap.Float64("rate", recorded.Rate),
+		zap.Float64("last_good", recorded.LastGood),
+		zap.Int("count", recorded.Count),
+		zap.String("detail", recorded.Detail))
+}
+
+func (g *RateGuard) today() string {
+	return g.clock.Now().Format("2006-01-02")
+}
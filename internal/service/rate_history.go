@@ -0,0 +1,102 @@
+package service
+
+import (
+	"time"
+
+	"exchange-rate-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// observe records rates fetched from the provider in the rate history.
+// Failing to record is logged but never fails the request that fetched
+// them.
+func (s *ExchangeService) observe(rates ...domain.ExchangeRate) {
+	if s.history == nil || len(rates) == 0 {
+		return
+	}
+
+	now := time.Now()
+	observations := make([]domain.RateObservation, len(rates))
+	for i, rate := range rates {
+		observations[i] = domain.RateObservation{
+			Pair:         rate.FromCurrency + rate.ToCurrency,
+			FromCurrency: rate.FromCurrency,
+			ToCurrency:   rate.ToCurrency,
+			Date:         rate.Date,
+			Rate:         rate.Rate,
+			Timestamp:    rate.Timestamp,
+			ObservedAt:   now,
+		}
+	}
+
+	if err := s.history.Record(observations); err != nil {
+		s.logger.Error("Failed to record rate observations", zap.Int("count", len(observations)), zap.Error(err))
+	}
+}
+
+// overridesAsOf returns the overrides in force at asOf, or nil when the
+// service applies none or the audit trail cannot be read.
+func (s *ExchangeService) overridesAsOf(asOf time.Time) *OverrideSnapshot {
+	if s.overrides == nil {
+		return nil
+	}
+
+	snapshot, err := s.overrides.AsOf(asOf)
+	if err != nil {
+		s.logger.Error("Failed to replay rate overrides", zap.Time("as_of", asOf), zap.Error(err))
+		return nil
+	}
+	return snapshot
+}
+
+// rateAsOf returns the rate for from/to on date as the service knew it at
+// asOf, or the latest rate known then when date is empty. An override in
+// force at asOf, taken from overrides, replaces the market rate.
+func (s *ExchangeService) rateAsOf(from, to, date string, asOf time.Time, overrides *OverrideSnapshot) (*domain.ExchangeRate, error) {
+	if date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, domain.ValidationErrorf("invalid date format, expected YYYY-MM-DD")
+		}
+	}
+	var observation *domain.RateObservation
+	var err error
+	if s.history == nil {
+		err = domain.Errorf(domain.ErrNotFound, "rate history is not kept")
+	} else {
+		observation, err = s.history.AsOf(from, to, date, asOf)
+	}
+
+	if overrides != nil {
+		day := date
+		switch {
+		case day != "":
+		case err == nil:
+			day = observation.Date
+		default:
+			day = asOf.UTC().Format("2006-01-02")
+		}
+		if override := overrides.Lookup(from, to, day); override != nil {
+			return &domain.ExchangeRate{
+				FromCurrency: from,
+				ToCurrency:   to,
+				Rate:         override.Rate,
+				Timestamp:    override.UpdatedAt,
+				Date:         day,
+				Source:       domain.SourceOverride,
+			}, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ExchangeRate{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         observation.Rate,
+		Timestamp:    observation.Timestamp,
+		Date:         observation.Date,
+		ObservedAt:   &observation.ObservedAt,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/utils"
+)
+
+// historicalRoute is the route whose cost scales with the days requested.
+const historicalRoute = "/api/v1/historical"
+
+type bucketState struct {
+	Tokens float64   `json:"tokens"`
+	Last   time.Time `json:"last"`
+}
+
+// RateLimiter implements token buckets whose state lives in a
+// CacheRepository. With a process-local cache each replica enforces its
+// limits on its own; with a domain.SharedCache the buckets are updated
+// under its lock and the limits hold across replicas.
+type RateLimiter struct {
+	mu    sync.Mutex
+	store domain.CacheRepository
+}
+
+func NewRateLimiter(store domain.CacheRepository) *RateLimiter {
+	return &RateLimiter{
+		store: store,
+	}
+}
+
+// Take consumes cost tokens from the bucket identified by key. The bucket
+// holds limit tokens and refills completely over window.
+func (l *RateLimiter) Take(key string, cost, limit int, window time.Duration) domain.RateLimitResult {
+	if limit <= 0 {
+		return domain.RateLimitResult{Allowed: true}
+	}
+	if cost < 1 {
+		cost = 1
+	}
+	if cost > limit {
+		cost = limit
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cacheKey := fmt.Sprintf("ratelimit_%s", key)
+	if shared, ok := l.store.(domain.SharedCache); ok {
+		// Without the lock the bucket is still updated, at worst losing a
+		// concurrent take on another replica.
+		if unlock, err := shared.Lock(cacheKey); err == nil {
+			defer unlock()
+		}
+	}
+
+	now := time.Now()
+	capacity := float64(limit)
+	perSecond := capacity / window.Seconds()
+
+	state := bucketState{Tokens: capacity, Last: now}
+	l.store.Get(cacheKey, &state)
+
+	elapsed := now.Sub(state.Last).Seconds()
+	if elapsed > 0 {
+		state.Tokens = math.Min(capacity, state.Tokens+elapsed*perSecond)
+	}
+	state.Last = now
+
+	result := domain.RateLimitResult{Limit: limit}
+	if state.Tokens >= float64(cost) {
+		state.Tokens -= float64(cost)
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsDuration((float64(cost) - state.Tokens) / perSecond)
+	}
+
+	result.Remaining = int(math.Floor(state.Tokens))
+	result.Reset = secondsDuration((capacity - state.Tokens) / perSecond)
+
+	l.store.Set(cacheKey, state, window)
+
+	return result
+}
+
+// RouteCost returns how many tokens a request to route consumes: its weight,
+// one unless listed in weights, times the days from startDate to endDate, up
+// to utils.MaxHistoryDays, for historical ranges since each day may trigger
+// its own upstream fetch. A range that does not parse costs the weight
+// alone; validation rejects it later.
+func RouteCost(weights map[string]int, route, startDate, endDate string) int {
+	cost, exists := weights[route]
+	if !exists {
+		cost = 1
+	}
+
+	if route == historicalRoute {
+		if days, err := utils.CountDays(startDate, endDate, utils.MaxHistoryDays); err == nil {
+			cost *= days
+		}
+	}
+
+	return cost
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/utils"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// defaultUpdateCron is the schedule of the default update group.
+const defaultUpdateCron = "0 */4 * * *"
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// UpdateSchedule is a validated update group.
+type UpdateSchedule struct {
+	Name   string
+	Bases  []string
+	Pairs  [][2]string
+	Demand bool
+
+	group    domain.UpdateGroup
+	schedule cron.Schedule
+}
+
+// Next returns the first scheduled run after t.
+func (u UpdateSchedule) Next(t time.Time) time.Time {
+	return u.schedule.Next(t)
+}
+
+// DefaultUpdateGroup refreshes every enabled base currency the service
+// tracks on cronExpr in timezone, or every four hours UTC when cronExpr is
+// empty.
+func DefaultUpdateGroup(cronExpr, timezone string) domain.UpdateGroup {
+	if cronExpr == "" {
+		cronExpr = defaultUpdateCron
+	}
+	return domain.UpdateGroup{
+		Name:     "default",
+		Cron:     cronExpr,
+		Timezone: timezone,
+		Bases:    trackedCurrencies(),
+	}
+}
+
+// DemandUpdateGroup refreshes the hot set on cronExpr in timezone, or
+// every four hours UTC when cronExpr is empty. Pairs and bases outside the
+// hot set are left to be fetched when a client asks for them.
+func DemandUpdateGroup(cronExpr, timezone string) domain.UpdateGroup {
+	if cronExpr == "" {
+		cronExpr = defaultUpdateCron
+	}
+	return domain.UpdateGroup{
+		Name:     "demand",
+		Cron:     cronExpr,
+		Timezone: timezone,
+		Demand:   true,
+	}
+}
+
+// AddDemandGroup returns groups with a demand group on cronExpr in timezone
+// added, unless one of them already follows demand. The other groups keep
+// their bases refreshed, and WebSocket and webhook subscribers notified,
+// whatever the demand and after a restart empties the hot set.
+func AddDemandGroup(groups []domain.UpdateGroup, cronExpr, timezone string) []domain.UpdateGroup {
+	for _, group := range groups {
+		if group.Demand {
+			return groups
+		}
+	}
+	return append(groups, DemandUpdateGroup(cronExpr, timezone))
+}
+
+// ParseUpdateGroups validates groups and parses their cron expressions.
+func ParseUpdateGroups(groups []domain.UpdateGroup) ([]UpdateSchedule, error) {
+	schedules := make([]UpdateSchedule, 0, len(groups))
+	seen := make(map[string]bool, len(groups))
+
+	for _, group := range groups {
+		if group.Name == "" {
+			return nil, fmt.Errorf("update group name is required")
+		}
+		if seen[group.Name] {
+			return nil, fmt.Errorf("duplicate update group %q", group.Name)
+		}
+		seen[group.Name] = true
+
+		if strings.HasPrefix(group.Cron, "TZ=") || strings.HasPrefix(group.Cron, "CRON_TZ=") {
+			return nil, fmt.Errorf("update group %q: set timezone instead of a TZ prefix", group.Name)
+		}
+		schedule, err := cronParser.Parse(group.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("update group %q: %w", group.Name, err)
+		}
+		loc := time.UTC
+		if group.Timezone != "" {
+			if loc, err = time.LoadLocation(group.Timezone); err != nil {
+				return nil, fmt.Errorf("update group %q: unknown timezone %q: %w", group.Name, group.Timezone, err)
+			}
+		}
+		if spec, ok := schedule.(*cron.SpecSchedule); ok {
+			spec.Location = loc
+		}
+		group.Timezone = loc.String()
+
+		u := UpdateSchedule{Name: group.Name, Demand: group.Demand, group: group, schedule: schedule}
+		for _, base := range group.Bases {
+			base = strings.ToUpper(base)
+			if !utils.IsValidCurrency(base) {
+				return nil, fmt.Errorf("update group %q: unsupported currency %s", group.Name, base)
+			}
+			u.Bases = append(u.Bases, base)
+		}
+		for _, pair := range group.Pairs {
+			from, to, err := utils.ParsePair(pair)
+			if err != nil {
+				return nil, fmt.Errorf("update group %q: %w", group.Name, err)
+			}
+			u.Pairs = append(u.Pairs, [2]string{from, to})
+		}
+		if len(u.Bases) == 0 && len(u.Pairs) == 0 && !u.Demand {
+			return nil, fmt.Errorf("update group %q has no bases or pairs", group.Name)
+		}
+
+		schedules = append(schedules, u)
+	}
+
+	return schedules, nil
+}
+
+type UpdaterOptions struct {
+	Schedules []UpdateSchedule
+	// Runs remembers when each group last ran. Without it every group runs
+	// once as soon as the updater starts.
+	Runs domain.UpdateRunRepository
+	// Jitter delays each run by a random amount up to this long, so
+	// replicas do not all hit the provider at the same instant.
+	Jitter time.Duration
+	Clock  clock.Clock
+}
+
+// WithUpdater replaces the default update schedule used by
+// StartRateUpdater.
+func WithUpdater(options UpdaterOptions) ExchangeOption {
+	return func(s *ExchangeService) {
+		if len(options.Schedules) == 0 {
+			options.Schedules = s.updater.Schedules
+		}
+		if options.Clock == nil {
+			options.Clock = clock.System{}
+		}
+		s.updater = options
+	}
+}
+
+func defaultUpdaterOptions() UpdaterOptions {
+	schedules, err := ParseUpdateGroups([]domain.UpdateGroup{DefaultUpdateGroup("", "")})
+	if err != nil {
+		panic(err)
+	}
+	return UpdaterOptions{Schedules: schedules, Clock: clock.System{}}
+}
+
+// UpdateStatus returns each update group with its last run and when it is
+// next due.
+func (s *ExchangeService) UpdateStatus() []domain.UpdateGroupStatus {
+	now := s.updater.Clock.Now()
+	schedules := s.updateSchedules()
+
+	s.lastRunMu.Lock()
+	defer s.lastRunMu.Unlock()
+
+	statuses := make([]domain.UpdateGroupStatus, 0, len(schedules))
+	for _, u := range schedules {
+		status := domain.UpdateGroupStatus{UpdateGroup: u.group, NextRun: u.Next(now)}
+		if last, ok := s.lastRuns[u.Name]; ok {
+			status.LastRun = &last
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// SetUpdateSchedules replaces the update groups of a running updater. Runs
+// in progress finish first; each new group then starts as it would after a
+// restart.
+func (s *ExchangeService) SetUpdateSchedules(schedules []UpdateSchedule) {
+	s.updaterMu.Lock()
+	s.updater.Schedules = schedules
+	s.updaterMu.Unlock()
+
+	select {
+	case s.updaterReload <- struct{}{}:
+	default:
+	}
+}
+
+func (s *ExchangeService) updateSchedules() []UpdateSchedule {
+	s.updaterMu.Lock()
+	defer s.updaterMu.Unlock()
+	return s.updater.Schedules
+}
+
+// StartRateUpdater refreshes each update group on its schedule until ctx is
+// done, then waits for runs in progress. A group whose last run is still
+// going when the next is due skips that run. A group that missed runs while
+// the service was down runs once on start, not once per missed run.
+func (s *ExchangeService) StartRateUpdater(ctx context.Context) {
+	for {
+		groupsCtx, stop := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		for _, schedule := range s.updateSchedules() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.runUpdateGroup(ctx, groupsCtx, schedule)
+			}()
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-s.updaterReload:
+			s.logger.Info("Update schedule reloaded")
+		}
+		stop()
+		wg.Wait()
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// runUpdateGroup schedules runs of u until stop is done. Runs use ctx, so a
+// schedule change lets them finish.
+func (s *ExchangeService) runUpdateGroup(ctx, stop context.Context, u UpdateSchedule) {
+	clk := s.updater.Clock
+
+	var running atomic.Bool
+	var runs sync.WaitGroup
+	defer runs.Wait()
+
+	next := s.firstUpdate(u)
+	for {
+		select {
+		case <-stop.Done():
+			return
+		case <-clk.After(next.Sub(clk.Now()) + s.updateJitter()):
+		}
+
+		slot := next
+		next = u.Next(clk.Now())
+
+		if !running.CompareAndSwap(false, true) {
+			s.logger.Warn("Skipping scheduled refresh, previous run still in progress",
+				zap.String("group", u.Name),
+				zap.Time("scheduled", slot))
+			continue
+		}
+
+		runs.Add(1)
+		go func() {
+			defer runs.Done()
+			defer running.Store(false)
+			s.runUpdate(ctx, u, slot)
+		}()
+	}
+}
+
+// firstUpdate returns when u should first run: now if it never ran or
+// missed a run, otherwise its next scheduled time.
+func (s *ExchangeService) firstUpdate(u UpdateSchedule) time.Time {
+	now := s.updater.Clock.Now()
+	if s.updater.Runs == nil {
+		return now
+	}
+
+	last, err := s.updater.Runs.LastRun(u.Name)
+	if err != nil {
+		s.logger.Warn("Failed to read last refresh", zap.String("group", u.Name), zap.Error(err))
+		return now
+	}
+	if last.IsZero() {
+		return now
+	}
+	s.setLastRun(u.Name, last)
+	if next := u.Next(last); next.After(now) {
+		return next
+	}
+	return now
+}
+
+func (s *ExchangeService) runUpdate(ctx context.Context, u UpdateSchedule, slot time.Time) {
+	start := time.Now()
+	bases, pairs := s.updateTargets(u)
+
+	for _, base := range bases {
+		s.updateRates(ctx, base)
+	}
+	for _, pair := range pairs {
+		if err := s.refreshPair(ctx, pair[0], pair[1]); err != nil {
+			s.logger.Error("Failed to update rate",
+				zap.String("group", u.Name),
+				zap.String("pair", pair[0]+pair[1]),
+				zap.Error(err))
+		}
+	}
+
+	s.setLastRun(u.Name, slot)
+	if s.updater.Runs != nil {
+		if err := s.updater.Runs.SetLastRun(u.Name, slot); err != nil {
+			s.logger.Error("Failed to record last refresh", zap.String("group", u.Name), zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Scheduled refresh finished",
+		zap.String("group", u.Name),
+		zap.Int("bases", len(bases)),
+		zap.Int("pairs", len(pairs)),
+		zap.Duration("duration", time.Since(start)))
+}
+
+// updateTargets returns the bases and pairs a run of u refreshes: its own,
+// plus the current hot set for a demand group. Pairs whose base is
+// refreshed anyway are left out, since the base's table covers them.
+func (s *ExchangeService) updateTargets(u UpdateSchedule) ([]string, [][2]string) {
+	candidateBases := u.Bases
+	candidatePairs := u.Pairs
+	if u.Demand && s.demand != nil {
+		candidateBases = append([]string(nil), candidateBases...)
+		candidatePairs = append([][2]string(nil), candidatePairs...)
+
+		hot := s.demand.HotSet()
+		for _, stat := range hot.Bases {
+			candidateBases = append(candidateBases, stat.Key)
+		}
+		for _, stat := range hot.Pairs {
+			candidatePairs = append(candidatePairs, [2]string{stat.Key[:3], stat.Key[3:]})
+		}
+	}
+
+	var bases []string
+	seenBases := make(map[string]bool)
+	for _, base := range candidateBases {
+		if !seenBases[base] {
+			seenBases[base] = true
+			bases = append(bases, base)
+		}
+	}
+
+	var pairs [][2]string
+	seenPairs := make(map[[2]string]bool)
+	for _, pair := range candidatePairs {
+		if !seenBases[pair[0]] && !seenPairs[pair] {
+			seenPairs[pair] = true
+			pairs = append(pairs, pair)
+		}
+	}
+	return bases, pairs
+}
+
+func (s *ExchangeService) setLastRun(group string, at time.Time) {
+	s.lastRunMu.Lock()
+	defer s.lastRunMu.Unlock()
+	s.lastRuns[group] = at
+}
+
+func (s *ExchangeService) updateJitter() time.Duration {
+	if s.updater.Jitter <= 0 {
+		return 0
+	}
+	return rand.N(s.updater.Jitter)
+}
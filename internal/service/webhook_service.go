@@ -0,0 +1,427 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ConditionAbove     = "above"
+	ConditionBelow     = "below"
+	ConditionChangePct = "change_pct"
+)
+
+type WebhookService struct {
+	repo        domain.WebhookRepository
+	client      *http.Client
+	logger      *zap.Logger
+	maxAttempts int
+	baseBackoff time.Duration
+
+	mu    sync.Mutex
+	state map[string]*domain.WebhookState
+	wg    sync.WaitGroup
+}
+
+func NewWebhookService(repo domain.WebhookRepository, logger *zap.Logger, maxAttempts int, baseBackoff time.Duration) *WebhookService {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	s := &WebhookService{
+		repo:        repo,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		logger:      logger,
+		maxAttempts: maxAttempts,
+		baseBackoff: baseBackoff,
+		state:       make(map[string]*domain.WebhookState),
+	}
+
+	// Conditions pick up where they left off, so a threshold crossed by the
+	// first snapshot after a restart still fires.
+	states, err := repo.States()
+	if err != nil {
+		logger.Error("Failed to load webhook state", zap.Error(err))
+	}
+	for id, state := range states {
+		s.state[id] = &state
+	}
+	return s
+}
+
+func (s *WebhookService) Create(sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	if err := validateSubscription(sub); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sub.ID = utils.NewID()
+	sub.CreatedAt = now
+	sub.UpdatedAt = now
+
+	if err := s.repo.Create(sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) Get(id string) (*domain.WebhookSubscription, error) {
+	return s.repo.Get(id)
+}
+
+func (s *WebhookService) List() ([]domain.WebhookSubscription, error) {
+	return s.repo.List()
+}
+
+func (s *WebhookService) Update(id string, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	existing, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if sub.Secret == "" {
+		sub.Secret = existing.Secret
+	}
+	if err := validateSubscription(sub); err != nil {
+		return nil, err
+	}
+
+	sub.ID = id
+	sub.CreatedAt = existing.CreatedAt
+	sub.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(sub); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	delete(s.state, id)
+	s.mu.Unlock()
+	s.saveStates()
+
+	return sub, nil
+}
+
+func (s *WebhookService) Delete(id string) error {
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.state, id)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *WebhookService) ListDeadLetters() ([]domain.WebhookDeadLetter, error) {
+	return s.repo.ListDeadLetters()
+}
+
+// ReplayDeadLetter sends a dead letter's event once more to its
+// subscription's current URL, signed with its current secret. A delivered
+// letter is removed; a failed one records the attempt and the error, and
+// ErrUpstreamUnavailable is returned.
+func (s *WebhookService) ReplayDeadLetter(ctx context.Context, id string) (*domain.WebhookDeadLetter, error) {
+	letter, err := s.repo.GetDeadLetter(id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.Errorf(domain.ErrNotFound, "dead letter not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.repo.Get(letter.SubscriptionID)
+	if err != nil {
+		return nil, domain.Errorf(domain.ErrNotFound, "webhook subscription %s no longer exists", letter.SubscriptionID)
+	}
+
+	body, err := json.Marshal(letter.Event)
+	if err != nil {
+		return nil, err
+	}
+
+	letter.Attempts++
+	if sendErr := s.send(ctx, *sub, body); sendErr != nil {
+		letter.LastError = sendErr.Error()
+		letter.FailedAt = time.Now()
+		if err := s.repo.UpdateDeadLetter(letter); err != nil {
+			s.logger.Error("Failed to update webhook dead letter", zap.String("dead_letter_id", id), zap.Error(err))
+		}
+		return nil, domain.Errorf(domain.ErrUpstreamUnavailable, "replay failed: %s", sendErr)
+	}
+
+	if err := s.repo.DeleteDeadLetter(id); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Webhook dead letter replayed",
+		zap.String("dead_letter_id", id),
+		zap.String("subscription_id", sub.ID),
+		zap.String("event_id", letter.Event.ID))
+	return letter, nil
+}
+
+// Run evaluates subscriptions against every snapshot received on updates
+// until ctx is cancelled or the channel is closed.
+func (s *WebhookService) Run(ctx context.Context, updates <-chan domain.RateUpdate) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			s.Evaluate(ctx, update)
+		}
+	}
+}
+
+// Evaluate checks every subscription against a refreshed snapshot and
+// dispatches deliveries for the ones whose condition fired.
+func (s *WebhookService) Evaluate(ctx context.Context, update domain.RateUpdate) {
+	subs, err := s.repo.List()
+	if err != nil {
+		s.logger.Error("Failed to list webhook subscriptions", zap.Error(err))
+		return
+	}
+
+	evaluated := false
+	defer func() {
+		if evaluated {
+			s.saveStates()
+		}
+	}()
+
+	for _, sub := range subs {
+		from, to, err := utils.ParsePair(sub.Pair)
+		if err != nil || from != update.BaseCurrency {
+			continue
+		}
+
+		rate, exists := update.Rates[to]
+		if !exists {
+			continue
+		}
+
+		evaluated = true
+		event, fired := s.check(sub, rate, update.Timestamp)
+		if !fired {
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(sub domain.WebhookSubscription, event domain.WebhookEvent) {
+			defer s.wg.Done()
+			s.deliver(ctx, sub, event)
+		}(sub, event)
+	}
+}
+
+// saveStates persists the state of every subscription's condition. Failing
+// to save is logged; evaluation goes on from memory.
+func (s *WebhookService) saveStates() {
+	s.mu.Lock()
+	states := make(map[string]domain.WebhookState, len(s.state))
+	for id, state := range s.state {
+		states[id] = *state
+	}
+	s.mu.Unlock()
+
+	if err := s.repo.SaveStates(states); err != nil {
+		s.logger.Error("Failed to save webhook state", zap.Error(err))
+	}
+}
+
+// Wait blocks until all in-flight deliveries have finished.
+func (s *WebhookService) Wait() {
+	s.wg.Wait()
+}
+
+func (s *WebhookService) check(sub domain.WebhookSubscription, rate float64, ts time.Time) (domain.WebhookEvent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	day := ts.UTC().Format("2006-01-02")
+	state, exists := s.state[sub.ID]
+	if !exists {
+		s.state[sub.ID] = &domain.WebhookState{LastRate: rate, Day: day, DayRef: rate}
+		return domain.WebhookEvent{}, false
+	}
+
+	if state.Day != day {
+		state.Day = day
+		state.DayRef = state.LastRate
+	}
+
+	previous := state.LastRate
+	state.LastRate = rate
+
+	event := domain.WebhookEvent{
+		ID:             utils.NewID(),
+		SubscriptionID: sub.ID,
+		Pair:           sub.Pair,
+		Condition:      sub.Condition.Type,
+		Threshold:      sub.Condition.Threshold,
+		Rate:           rate,
+		ReferenceRate:  previous,
+		Timestamp:      ts,
+	}
+
+	switch sub.Condition.Type {
+	case ConditionAbove:
+		return event, previous < sub.Condition.Threshold && rate >= sub.Condition.Threshold
+	case ConditionBelow:
+		return event, previous > sub.Condition.Threshold && rate <= sub.Condition.Threshold
+	case ConditionChangePct:
+		if state.DayRef == 0 || state.FiredDay == day {
+			return event, false
+		}
+		change := (rate - state.DayRef) / state.DayRef * 100
+		if math.Abs(change) < sub.Condition.Threshold {
+			return event, false
+		}
+		state.FiredDay = day
+		event.ReferenceRate = state.DayRef
+		event.ChangePercent = change
+		return event, true
+	}
+
+	return event, false
+}
+
+func (s *WebhookService) deliver(ctx context.Context, sub domain.WebhookSubscription, event domain.WebhookEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to encode webhook event", zap.Error(err))
+		return
+	}
+
+	var lastErr error
+	attempts := 0
+	for attempts < s.maxAttempts {
+		if attempts > 0 && !sleepContext(ctx, s.baseBackoff<<(attempts-1)) {
+			break
+		}
+		attempts++
+
+		if lastErr = s.send(ctx, sub, body); lastErr == nil {
+			s.logger.Info("Webhook delivered",
+				zap.String("subscription_id", sub.ID),
+				zap.String("event_id", event.ID),
+				zap.Int("attempt", attempts))
+			return
+		}
+
+		s.logger.Warn("Webhook delivery failed",
+			zap.String("subscription_id", sub.ID),
+			zap.Int("attempt", attempts),
+			zap.Error(lastErr))
+	}
+
+	letter := &domain.WebhookDeadLetter{
+		ID:             utils.NewID(),
+		SubscriptionID: sub.ID,
+		URL:            sub.URL,
+		Event:          event,
+		Attempts:       attempts,
+		LastError:      lastErr.Error(),
+		FailedAt:       time.Now(),
+	}
+	if err := s.repo.AddDeadLetter(letter); err != nil {
+		s.logger.Error("Failed to record webhook dead letter", zap.Error(err))
+	}
+}
+
+func (s *WebhookService) send(ctx context.Context, sub domain.WebhookSubscription, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", SignWebhookPayload(sub.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature value for a delivery:
+// an HMAC-SHA256 over "<timestamp>.<body>" keyed with the subscription secret.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func validateSubscription(sub *domain.WebhookSubscription) error {
+	from, to, err := utils.ParsePair(sub.Pair)
+	if err != nil {
+		return err
+	}
+	sub.Pair = from + to
+
+	switch sub.Condition.Type {
+	case ConditionAbove, ConditionBelow:
+		if sub.Condition.Threshold <= 0 {
+			return domain.ValidationErrorf("threshold must be positive")
+		}
+	case ConditionChangePct:
+		if sub.Condition.Threshold <= 0 || sub.Condition.Threshold > 100 {
+			return domain.ValidationErrorf("change_pct threshold must be between 0 and 100")
+		}
+	default:
+		return domain.ValidationErrorf("condition type must be one of above, below, change_pct")
+	}
+
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.ValidationErrorf("url must be an absolute http or https URL")
+	}
+
+	if sub.Secret == "" {
+		return domain.ValidationErrorf("secret is required")
+	}
+
+	return nil
+}
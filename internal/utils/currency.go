@@ -1,7 +1,68 @@
 package utils
 
-import "exchange-rate-service/internal/domain"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"exchange-rate-service/internal/domain"
+)
+
+// enabledCurrencies, when set, narrows the supported currencies to those
+// the service accepts.
+var enabledCurrencies atomic.Pointer[map[string]bool]
+
+// SetEnabledCurrencies restricts the currencies the service accepts to
+// currencies, which must all be supported. An empty list enables every
+// supported currency. It is safe to call while requests are served.
+func SetEnabledCurrencies(currencies []string) error {
+	if len(currencies) == 0 {
+		enabledCurrencies.Store(nil)
+		return nil
+	}
+
+	enabled := make(map[string]bool, len(currencies))
+	for _, currency := range currencies {
+		currency = strings.ToUpper(currency)
+		if !domain.SupportedCurrencies[currency] {
+			return fmt.Errorf("unsupported currency %s", currency)
+		}
+		enabled[currency] = true
+	}
+	enabledCurrencies.Store(&enabled)
+	return nil
+}
+
+// EnabledCurrencies returns the currencies the service accepts, sorted.
+func EnabledCurrencies() []string {
+	currencies := make([]string, 0, len(domain.SupportedCurrencies))
+	for currency := range domain.SupportedCurrencies {
+		if IsValidCurrency(currency) {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies)
+	return currencies
+}
 
 func IsValidCurrency(currency string) bool {
+	if enabled := enabledCurrencies.Load(); enabled != nil {
+		return (*enabled)[currency]
+	}
 	return domain.SupportedCurrencies[currency]
 }
+
+func ParsePair(pair string) (string, string, error) {
+	pair = strings.ToUpper(strings.TrimSpace(pair))
+	if len(pair) != 6 {
+		return "", "", domain.ValidationErrorf("invalid currency pair: %s", pair)
+	}
+
+	from, to := pair[:3], pair[3:]
+	if !IsValidCurrency(from) || !IsValidCurrency(to) {
+		return "", "", domain.Errorf(domain.ErrUnsupportedCurrency, "unsupported currency pair: %s", pair)
+	}
+
+	return from, to, nil
+}
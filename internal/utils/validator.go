@@ -1,47 +1,97 @@
 package utils
 
 import (
-	"fmt"
 	"time"
+
+	"exchange-rate-service/internal/domain"
 )
 
+// MaxHistoryDays is how far back historical dates may go.
+const MaxHistoryDays = 90
+
 func ValidateDate(dateStr string) error {
 	if dateStr == "" {
-		return fmt.Errorf("date cannot be empty")
+		return domain.ValidationErrorf("date cannot be empty")
 	}
 
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
-		return fmt.Errorf("invalid date format, expected YYYY-MM-DD")
+		return domain.ValidationErrorf("invalid date format, expected YYYY-MM-DD")
 	}
 
 	now := time.Now()
-	maxPastDate := now.AddDate(0, 0, -90)
+	maxPastDate := now.AddDate(0, 0, -MaxHistoryDays)
 
 	if date.After(now) {
-		return fmt.Errorf("date cannot be in the future")
+		return domain.Errorf(domain.ErrDateOutOfRange, "date cannot be in the future")
 	}
 
 	if date.Before(maxPastDate) {
-		return fmt.Errorf("date cannot be more than 90 days in the past")
+		return domain.Errorf(domain.ErrDateOutOfRange, "date cannot be more than %d days in the past", MaxHistoryDays)
+	}
+
+	return nil
+}
+
+// ValidateDateSpan checks that startDate to endDate covers at most maxDays
+// days, both ends included.
+func ValidateDateSpan(startDate, endDate string, maxDays int) error {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return domain.ValidationErrorf("invalid start date format")
+	}
+
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return domain.ValidationErrorf("invalid end date format")
+	}
+
+	if end.Sub(start) >= time.Duration(maxDays)*24*time.Hour {
+		return domain.Errorf(domain.ErrDateOutOfRange, "date range cannot span more than %d days", maxDays)
 	}
 
 	return nil
 }
 
+// CountDays returns how many days startDate to endDate covers, both ends
+// included, capped at maxDays. Unlike GetDateRange it does not build the
+// dates, so it is safe on unvalidated input.
+func CountDays(startDate, endDate string, maxDays int) (int, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return 0, domain.ValidationErrorf("invalid start date format")
+	}
+
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return 0, domain.ValidationErrorf("invalid end date format")
+	}
+
+	if start.After(end) {
+		return 0, domain.ValidationErrorf("start date cannot be after end date")
+	}
+
+	// Sub saturates for spans over about 290 years, which the cap covers.
+	days := int(end.Sub(start)/(24*time.Hour)) + 1
+	if days > maxDays {
+		days = maxDays
+	}
+	return days, nil
+}
+
 func GetDateRange(startDate, endDate string) ([]string, error) {
 	start, err := time.Parse("2006-01-02", startDate)
 	if err != nil {
-		return nil, fmt.Errorf("invalid start date format")
+		return nil, domain.ValidationErrorf("invalid start date format")
 	}
 
 	end, err := time.Parse("2006-01-02", endDate)
 	if err != nil {
-		return nil, fmt.Errorf("invalid end date format")
+		return nil, domain.ValidationErrorf("invalid end date format")
 	}
 
 	if start.After(end) {
-		return nil, fmt.Errorf("start date cannot be after end date")
+		return nil, domain.ValidationErrorf("start date cannot be after end date")
 	}
 
 	var dates []string
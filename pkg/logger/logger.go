@@ -6,21 +6,30 @@ import (
 )
 
 func NewLogger(level string) *zap.Logger {
+	return NewLoggerAt(zap.NewAtomicLevelAt(ParseLevel(level)))
+}
+
+// NewLoggerAt builds a logger whose level follows level, so it can be
+// changed while the service runs.
+func NewLoggerAt(level zap.AtomicLevel) *zap.Logger {
 	config := zap.NewProductionConfig()
+	config.Level = level
 
+	logger, _ := config.Build()
+	return logger
+}
+
+// ParseLevel maps debug, info, warn and error to their zap levels. Anything
+// else is info.
+func ParseLevel(level string) zapcore.Level {
 	switch level {
 	case "debug":
-		config.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
-	case "info":
-		config.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+		return zapcore.DebugLevel
 	case "warn":
-		config.Level = zap.NewAtomicLevelAt(zapcore.WarnLevel)
+		return zapcore.WarnLevel
 	case "error":
-		config.Level = zap.NewAtomicLevelAt(zapcore.ErrorLevel)
+		return zapcore.ErrorLevel
 	default:
-		config.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+		return zapcore.InfoLevel
 	}
-
-	logger, _ := config.Build()
-	return logger
 }
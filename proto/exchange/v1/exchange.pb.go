@@ -0,0 +1,1388 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: exchange/v1/exchange.proto
+
+package exchangev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ConvertRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	From  string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To    string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// Defaults to 1 when zero.
+	Amount float64 `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Optional YYYY-MM-DD date for a historical conversion.
+	Date          string `protobuf:"bytes,4,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ConvertRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ConvertRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type ConvertResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Amount       float64                `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	FromCurrency string                 `protobuf:"bytes,2,opt,name=from_currency,json=fromCurrency,proto3" json:"from_currency,omitempty"`
+	ToCurrency   string                 `protobuf:"bytes,3,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`
+	Rate         float64                `protobuf:"fixed64,4,opt,name=rate,proto3" json:"rate,omitempty"`
+	Date         string                 `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`
+	Timestamp    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Set when the rate did not come from the market, e.g. override.
+	Source string `protobuf:"bytes,7,opt,name=source,proto3" json:"source,omitempty"`
+	// Set when the caller's API key has a markup profile.
+	Pricing *Pricing `protobuf:"bytes,8,opt,name=pricing,proto3" json:"pricing,omitempty"`
+	// How providers agreed on the rate, with RATE_PROVIDER=consensus.
+	Consensus     *Consensus `protobuf:"bytes,9,opt,name=consensus,proto3" json:"consensus,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertResponse) Reset() {
+	*x = ConvertResponse{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertResponse) ProtoMessage() {}
+
+func (x *ConvertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertResponse.ProtoReflect.Descriptor instead.
+func (*ConvertResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConvertResponse) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetFromCurrency() string {
+	if x != nil {
+		return x.FromCurrency
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetToCurrency() string {
+	if x != nil {
+		return x.ToCurrency
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ConvertResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetPricing() *Pricing {
+	if x != nil {
+		return x.Pricing
+	}
+	return nil
+}
+
+func (x *ConvertResponse) GetConsensus() *Consensus {
+	if x != nil {
+		return x.Consensus
+	}
+	return nil
+}
+
+type Pricing struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Profile   string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	MidRate   float64                `protobuf:"fixed64,2,opt,name=mid_rate,json=midRate,proto3" json:"mid_rate,omitempty"`
+	BidRate   float64                `protobuf:"fixed64,3,opt,name=bid_rate,json=bidRate,proto3" json:"bid_rate,omitempty"`
+	AskRate   float64                `protobuf:"fixed64,4,opt,name=ask_rate,json=askRate,proto3" json:"ask_rate,omitempty"`
+	MarkupBps float64                `protobuf:"fixed64,5,opt,name=markup_bps,json=markupBps,proto3" json:"markup_bps,omitempty"`
+	// Amount at the mid rate; the client receives gross_amount - fee.
+	GrossAmount   float64 `protobuf:"fixed64,6,opt,name=gross_amount,json=grossAmount,proto3" json:"gross_amount,omitempty"`
+	Fee           float64 `protobuf:"fixed64,7,opt,name=fee,proto3" json:"fee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pricing) Reset() {
+	*x = Pricing{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pricing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pricing) ProtoMessage() {}
+
+func (x *Pricing) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pricing.ProtoReflect.Descriptor instead.
+func (*Pricing) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Pricing) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *Pricing) GetMidRate() float64 {
+	if x != nil {
+		return x.MidRate
+	}
+	return 0
+}
+
+func (x *Pricing) GetBidRate() float64 {
+	if x != nil {
+		return x.BidRate
+	}
+	return 0
+}
+
+func (x *Pricing) GetAskRate() float64 {
+	if x != nil {
+		return x.AskRate
+	}
+	return 0
+}
+
+func (x *Pricing) GetMarkupBps() float64 {
+	if x != nil {
+		return x.MarkupBps
+	}
+	return 0
+}
+
+func (x *Pricing) GetGrossAmount() float64 {
+	if x != nil {
+		return x.GrossAmount
+	}
+	return 0
+}
+
+func (x *Pricing) GetFee() float64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+type Consensus struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Method string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	// Providers whose quotes were accepted.
+	Sources       int32    `protobuf:"varint,2,opt,name=sources,proto3" json:"sources,omitempty"`
+	Queried       int32    `protobuf:"varint,3,opt,name=queried,proto3" json:"queried,omitempty"`
+	Rejected      []string `protobuf:"bytes,4,rep,name=rejected,proto3" json:"rejected,omitempty"`
+	Spread        float64  `protobuf:"fixed64,5,opt,name=spread,proto3" json:"spread,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Consensus) Reset() {
+	*x = Consensus{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Consensus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Consensus) ProtoMessage() {}
+
+func (x *Consensus) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Consensus.ProtoReflect.Descriptor instead.
+func (*Consensus) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Consensus) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Consensus) GetSources() int32 {
+	if x != nil {
+		return x.Sources
+	}
+	return 0
+}
+
+func (x *Consensus) GetQueried() int32 {
+	if x != nil {
+		return x.Queried
+	}
+	return 0
+}
+
+func (x *Consensus) GetRejected() []string {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
+func (x *Consensus) GetSpread() float64 {
+	if x != nil {
+		return x.Spread
+	}
+	return 0
+}
+
+type BatchConvertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conversions   []*ConvertRequest      `protobuf:"bytes,1,rep,name=conversions,proto3" json:"conversions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchConvertRequest) Reset() {
+	*x = BatchConvertRequest{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchConvertRequest) ProtoMessage() {}
+
+func (x *BatchConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchConvertRequest.ProtoReflect.Descriptor instead.
+func (*BatchConvertRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchConvertRequest) GetConversions() []*ConvertRequest {
+	if x != nil {
+		return x.Conversions
+	}
+	return nil
+}
+
+type ConversionError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// gRPC status code name, e.g. INVALID_ARGUMENT.
+	Code          string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConversionError) Reset() {
+	*x = ConversionError{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversionError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversionError) ProtoMessage() {}
+
+func (x *ConversionError) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversionError.ProtoReflect.Descriptor instead.
+func (*ConversionError) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ConversionError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ConversionError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type BatchConvertResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Outcome:
+	//
+	//	*BatchConvertResult_Result
+	//	*BatchConvertResult_Error
+	Outcome       isBatchConvertResult_Outcome `protobuf_oneof:"outcome"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchConvertResult) Reset() {
+	*x = BatchConvertResult{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchConvertResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchConvertResult) ProtoMessage() {}
+
+func (x *BatchConvertResult) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchConvertResult.ProtoReflect.Descriptor instead.
+func (*BatchConvertResult) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchConvertResult) GetOutcome() isBatchConvertResult_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return nil
+}
+
+func (x *BatchConvertResult) GetResult() *ConvertResponse {
+	if x != nil {
+		if x, ok := x.Outcome.(*BatchConvertResult_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *BatchConvertResult) GetError() *ConversionError {
+	if x != nil {
+		if x, ok := x.Outcome.(*BatchConvertResult_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isBatchConvertResult_Outcome interface {
+	isBatchConvertResult_Outcome()
+}
+
+type BatchConvertResult_Result struct {
+	Result *ConvertResponse `protobuf:"bytes,1,opt,name=result,proto3,oneof"`
+}
+
+type BatchConvertResult_Error struct {
+	Error *ConversionError `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchConvertResult_Result) isBatchConvertResult_Outcome() {}
+
+func (*BatchConvertResult_Error) isBatchConvertResult_Outcome() {}
+
+type BatchConvertResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per requested conversion, in request order.
+	Results       []*BatchConvertResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchConvertResponse) Reset() {
+	*x = BatchConvertResponse{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchConvertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchConvertResponse) ProtoMessage() {}
+
+func (x *BatchConvertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchConvertResponse.ProtoReflect.Descriptor instead.
+func (*BatchConvertResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchConvertResponse) GetResults() []*BatchConvertResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetLatestRatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to USD when empty.
+	BaseCurrency  string `protobuf:"bytes,1,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestRatesRequest) Reset() {
+	*x = GetLatestRatesRequest{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestRatesRequest) ProtoMessage() {}
+
+func (x *GetLatestRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestRatesRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestRatesRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetLatestRatesRequest) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+type GetLatestRatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseCurrency  string                 `protobuf:"bytes,1,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	Rates         map[string]float64     `protobuf:"bytes,2,rep,name=rates,proto3" json:"rates,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Date          string                 `protobuf:"bytes,4,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestRatesResponse) Reset() {
+	*x = GetLatestRatesResponse{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestRatesResponse) ProtoMessage() {}
+
+func (x *GetLatestRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestRatesResponse.ProtoReflect.Descriptor instead.
+func (*GetLatestRatesResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetLatestRatesResponse) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *GetLatestRatesResponse) GetRates() map[string]float64 {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *GetLatestRatesResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *GetLatestRatesResponse) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type GetHistoricalRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	StartDate     string                 `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoricalRatesRequest) Reset() {
+	*x = GetHistoricalRatesRequest{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoricalRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoricalRatesRequest) ProtoMessage() {}
+
+func (x *GetHistoricalRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoricalRatesRequest.ProtoReflect.Descriptor instead.
+func (*GetHistoricalRatesRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetHistoricalRatesRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GetHistoricalRatesRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *GetHistoricalRatesRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetHistoricalRatesRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+type ExchangeRate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromCurrency  string                 `protobuf:"bytes,1,opt,name=from_currency,json=fromCurrency,proto3" json:"from_currency,omitempty"`
+	ToCurrency    string                 `protobuf:"bytes,2,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`
+	Rate          float64                `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Date          string                 `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExchangeRate) Reset() {
+	*x = ExchangeRate{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeRate) ProtoMessage() {}
+
+func (x *ExchangeRate) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeRate.ProtoReflect.Descriptor instead.
+func (*ExchangeRate) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExchangeRate) GetFromCurrency() string {
+	if x != nil {
+		return x.FromCurrency
+	}
+	return ""
+}
+
+func (x *ExchangeRate) GetToCurrency() string {
+	if x != nil {
+		return x.ToCurrency
+	}
+	return ""
+}
+
+func (x *ExchangeRate) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *ExchangeRate) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ExchangeRate) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type GetHistoricalRatesResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	FromCurrency string                 `protobuf:"bytes,1,opt,name=from_currency,json=fromCurrency,proto3" json:"from_currency,omitempty"`
+	ToCurrency   string                 `protobuf:"bytes,2,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`
+	// Ordered by date.
+	Rates         []*ExchangeRate `protobuf:"bytes,3,rep,name=rates,proto3" json:"rates,omitempty"`
+	StartDate     string          `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string          `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoricalRatesResponse) Reset() {
+	*x = GetHistoricalRatesResponse{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoricalRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoricalRatesResponse) ProtoMessage() {}
+
+func (x *GetHistoricalRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoricalRatesResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoricalRatesResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetHistoricalRatesResponse) GetFromCurrency() string {
+	if x != nil {
+		return x.FromCurrency
+	}
+	return ""
+}
+
+func (x *GetHistoricalRatesResponse) GetToCurrency() string {
+	if x != nil {
+		return x.ToCurrency
+	}
+	return ""
+}
+
+func (x *GetHistoricalRatesResponse) GetRates() []*ExchangeRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *GetHistoricalRatesResponse) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetHistoricalRatesResponse) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+type ListCurrenciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCurrenciesRequest) Reset() {
+	*x = ListCurrenciesRequest{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCurrenciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCurrenciesRequest) ProtoMessage() {}
+
+func (x *ListCurrenciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCurrenciesRequest.ProtoReflect.Descriptor instead.
+func (*ListCurrenciesRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{13}
+}
+
+type ListCurrenciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currencies    []string               `protobuf:"bytes,1,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCurrenciesResponse) Reset() {
+	*x = ListCurrenciesResponse{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCurrenciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCurrenciesResponse) ProtoMessage() {}
+
+func (x *ListCurrenciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCurrenciesResponse.ProtoReflect.Descriptor instead.
+func (*ListCurrenciesResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListCurrenciesResponse) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+type WatchRatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Pairs such as USDINR. Empty watches every pair.
+	Pairs         []string `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRatesRequest) Reset() {
+	*x = WatchRatesRequest{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRatesRequest) ProtoMessage() {}
+
+func (x *WatchRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRatesRequest.ProtoReflect.Descriptor instead.
+func (*WatchRatesRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchRatesRequest) GetPairs() []string {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+type PairRate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pair          string                 `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	FromCurrency  string                 `protobuf:"bytes,2,opt,name=from_currency,json=fromCurrency,proto3" json:"from_currency,omitempty"`
+	ToCurrency    string                 `protobuf:"bytes,3,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`
+	Rate          float64                `protobuf:"fixed64,4,opt,name=rate,proto3" json:"rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PairRate) Reset() {
+	*x = PairRate{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PairRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PairRate) ProtoMessage() {}
+
+func (x *PairRate) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PairRate.ProtoReflect.Descriptor instead.
+func (*PairRate) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PairRate) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *PairRate) GetFromCurrency() string {
+	if x != nil {
+		return x.FromCurrency
+	}
+	return ""
+}
+
+func (x *PairRate) GetToCurrency() string {
+	if x != nil {
+		return x.ToCurrency
+	}
+	return ""
+}
+
+func (x *PairRate) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+type RateUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// SNAPSHOT for the initial rates, UPDATE for changes.
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Rates         []*PairRate            `protobuf:"bytes,2,rep,name=rates,proto3" json:"rates,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateUpdate) Reset() {
+	*x = RateUpdate{}
+	mi := &file_exchange_v1_exchange_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateUpdate) ProtoMessage() {}
+
+func (x *RateUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_v1_exchange_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateUpdate.ProtoReflect.Descriptor instead.
+func (*RateUpdate) Descriptor() ([]byte, []int) {
+	return file_exchange_v1_exchange_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RateUpdate) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RateUpdate) GetRates() []*PairRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *RateUpdate) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+var File_exchange_v1_exchange_proto protoreflect.FileDescriptor
+
+const file_exchange_v1_exchange_proto_rawDesc = "" +
+	"\n" +
+	"\x1aexchange/v1/exchange.proto\x12\vexchange.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"`\n" +
+	"\x0eConvertRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x12\n" +
+	"\x04date\x18\x04 \x01(\tR\x04date\"\xcf\x02\n" +
+	"\x0fConvertResponse\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12#\n" +
+	"\rfrom_currency\x18\x02 \x01(\tR\ffromCurrency\x12\x1f\n" +
+	"\vto_currency\x18\x03 \x01(\tR\n" +
+	"toCurrency\x12\x12\n" +
+	"\x04rate\x18\x04 \x01(\x01R\x04rate\x12\x12\n" +
+	"\x04date\x18\x05 \x01(\tR\x04date\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06source\x18\a \x01(\tR\x06source\x12.\n" +
+	"\apricing\x18\b \x01(\v2\x14.exchange.v1.PricingR\apricing\x124\n" +
+	"\tconsensus\x18\t \x01(\v2\x16.exchange.v1.ConsensusR\tconsensus\"\xc8\x01\n" +
+	"\aPricing\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x12\x19\n" +
+	"\bmid_rate\x18\x02 \x01(\x01R\amidRate\x12\x19\n" +
+	"\bbid_rate\x18\x03 \x01(\x01R\abidRate\x12\x19\n" +
+	"\bask_rate\x18\x04 \x01(\x01R\aaskRate\x12\x1d\n" +
+	"\n" +
+	"markup_bps\x18\x05 \x01(\x01R\tmarkupBps\x12!\n" +
+	"\fgross_amount\x18\x06 \x01(\x01R\vgrossAmount\x12\x10\n" +
+	"\x03fee\x18\a \x01(\x01R\x03fee\"\x8b\x01\n" +
+	"\tConsensus\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x18\n" +
+	"\asources\x18\x02 \x01(\x05R\asources\x12\x18\n" +
+	"\aqueried\x18\x03 \x01(\x05R\aqueried\x12\x1a\n" +
+	"\brejected\x18\x04 \x03(\tR\brejected\x12\x16\n" +
+	"\x06spread\x18\x05 \x01(\x01R\x06spread\"T\n" +
+	"\x13BatchConvertRequest\x12=\n" +
+	"\vconversions\x18\x01 \x03(\v2\x1b.exchange.v1.ConvertRequestR\vconversions\"?\n" +
+	"\x0fConversionError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x8d\x01\n" +
+	"\x12BatchConvertResult\x126\n" +
+	"\x06result\x18\x01 \x01(\v2\x1c.exchange.v1.ConvertResponseH\x00R\x06result\x124\n" +
+	"\x05error\x18\x02 \x01(\v2\x1c.exchange.v1.ConversionErrorH\x00R\x05errorB\t\n" +
+	"\aoutcome\"Q\n" +
+	"\x14BatchConvertResponse\x129\n" +
+	"\aresults\x18\x01 \x03(\v2\x1f.exchange.v1.BatchConvertResultR\aresults\"<\n" +
+	"\x15GetLatestRatesRequest\x12#\n" +
+	"\rbase_currency\x18\x01 \x01(\tR\fbaseCurrency\"\x8b\x02\n" +
+	"\x16GetLatestRatesResponse\x12#\n" +
+	"\rbase_currency\x18\x01 \x01(\tR\fbaseCurrency\x12D\n" +
+	"\x05rates\x18\x02 \x03(\v2..exchange.v1.GetLatestRatesResponse.RatesEntryR\x05rates\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x12\n" +
+	"\x04date\x18\x04 \x01(\tR\x04date\x1a8\n" +
+	"\n" +
+	"RatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"y\n" +
+	"\x19GetHistoricalRatesRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x03 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x04 \x01(\tR\aendDate\"\xb6\x01\n" +
+	"\fExchangeRate\x12#\n" +
+	"\rfrom_currency\x18\x01 \x01(\tR\ffromCurrency\x12\x1f\n" +
+	"\vto_currency\x18\x02 \x01(\tR\n" +
+	"toCurrency\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\x01R\x04rate\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x12\n" +
+	"\x04date\x18\x05 \x01(\tR\x04date\"\xcd\x01\n" +
+	"\x1aGetHistoricalRatesResponse\x12#\n" +
+	"\rfrom_currency\x18\x01 \x01(\tR\ffromCurrency\x12\x1f\n" +
+	"\vto_currency\x18\x02 \x01(\tR\n" +
+	"toCurrency\x12/\n" +
+	"\x05rates\x18\x03 \x03(\v2\x19.exchange.v1.ExchangeRateR\x05rates\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x05 \x01(\tR\aendDate\"\x17\n" +
+	"\x15ListCurrenciesRequest\"8\n" +
+	"\x16ListCurrenciesResponse\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x01 \x03(\tR\n" +
+	"currencies\")\n" +
+	"\x11WatchRatesRequest\x12\x14\n" +
+	"\x05pairs\x18\x01 \x03(\tR\x05pairs\"x\n" +
+	"\bPairRate\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12#\n" +
+	"\rfrom_currency\x18\x02 \x01(\tR\ffromCurrency\x12\x1f\n" +
+	"\vto_currency\x18\x03 \x01(\tR\n" +
+	"toCurrency\x12\x12\n" +
+	"\x04rate\x18\x04 \x01(\x01R\x04rate\"\x87\x01\n" +
+	"\n" +
+	"RateUpdate\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12+\n" +
+	"\x05rates\x18\x02 \x03(\v2\x15.exchange.v1.PairRateR\x05rates\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp2\x92\x04\n" +
+	"\x0fExchangeService\x12D\n" +
+	"\aConvert\x12\x1b.exchange.v1.ConvertRequest\x1a\x1c.exchange.v1.ConvertResponse\x12S\n" +
+	"\fBatchConvert\x12 .exchange.v1.BatchConvertRequest\x1a!.exchange.v1.BatchConvertResponse\x12Y\n" +
+	"\x0eGetLatestRates\x12\".exchange.v1.GetLatestRatesRequest\x1a#.exchange.v1.GetLatestRatesResponse\x12e\n" +
+	"\x12GetHistoricalRates\x12&.exchange.v1.GetHistoricalRatesRequest\x1a'.exchange.v1.GetHistoricalRatesResponse\x12Y\n" +
+	"\x0eListCurrencies\x12\".exchange.v1.ListCurrenciesRequest\x1a#.exchange.v1.ListCurrenciesResponse\x12G\n" +
+	"\n" +
+	"WatchRates\x12\x1e.exchange.v1.WatchRatesRequest\x1a\x17.exchange.v1.RateUpdate0\x01B4Z2exchange-rate-service/proto/exchange/v1;exchangev1b\x06proto3"
+
+var (
+	file_exchange_v1_exchange_proto_rawDescOnce sync.Once
+	file_exchange_v1_exchange_proto_rawDescData []byte
+)
+
+func file_exchange_v1_exchange_proto_rawDescGZIP() []byte {
+	file_exchange_v1_exchange_proto_rawDescOnce.Do(func() {
+		file_exchange_v1_exchange_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_exchange_v1_exchange_proto_rawDesc), len(file_exchange_v1_exchange_proto_rawDesc)))
+	})
+	return file_exchange_v1_exchange_proto_rawDescData
+}
+
+var file_exchange_v1_exchange_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_exchange_v1_exchange_proto_goTypes = []any{
+	(*ConvertRequest)(nil),             // 0: exchange.v1.ConvertRequest
+	(*ConvertResponse)(nil),            // 1: exchange.v1.ConvertResponse
+	(*Pricing)(nil),                    // 2: exchange.v1.Pricing
+	(*Consensus)(nil),                  // 3: exchange.v1.Consensus
+	(*BatchConvertRequest)(nil),        // 4: exchange.v1.BatchConvertRequest
+	(*ConversionError)(nil),            // 5: exchange.v1.ConversionError
+	(*BatchConvertResult)(nil),         // 6: exchange.v1.BatchConvertResult
+	(*BatchConvertResponse)(nil),       // 7: exchange.v1.BatchConvertResponse
+	(*GetLatestRatesRequest)(nil),      // 8: exchange.v1.GetLatestRatesRequest
+	(*GetLatestRatesResponse)(nil),     // 9: exchange.v1.GetLatestRatesResponse
+	(*GetHistoricalRatesRequest)(nil),  // 10: exchange.v1.GetHistoricalRatesRequest
+	(*ExchangeRate)(nil),               // 11: exchange.v1.ExchangeRate
+	(*GetHistoricalRatesResponse)(nil), // 12: exchange.v1.GetHistoricalRatesResponse
+	(*ListCurrenciesRequest)(nil),      // 13: exchange.v1.ListCurrenciesRequest
+	(*ListCurrenciesResponse)(nil),     // 14: exchange.v1.ListCurrenciesResponse
+	(*WatchRatesRequest)(nil),          // 15: exchange.v1.WatchRatesRequest
+	(*PairRate)(nil),                   // 16: exchange.v1.PairRate
+	(*RateUpdate)(nil),                 // 17: exchange.v1.RateUpdate
+	nil,                                // 18: exchange.v1.GetLatestRatesResponse.RatesEntry
+	(*timestamppb.Timestamp)(nil),      // 19: google.protobuf.Timestamp
+}
+var file_exchange_v1_exchange_proto_depIdxs = []int32{
+	19, // 0: exchange.v1.ConvertResponse.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 1: exchange.v1.ConvertResponse.pricing:type_name -> exchange.v1.Pricing
+	3,  // 2: exchange.v1.ConvertResponse.consensus:type_name -> exchange.v1.Consensus
+	0,  // 3: exchange.v1.BatchConvertRequest.conversions:type_name -> exchange.v1.ConvertRequest
+	1,  // 4: exchange.v1.BatchConvertResult.result:type_name -> exchange.v1.ConvertResponse
+	5,  // 5: exchange.v1.BatchConvertResult.error:type_name -> exchange.v1.ConversionError
+	6,  // 6: exchange.v1.BatchConvertResponse.results:type_name -> exchange.v1.BatchConvertResult
+	18, // 7: exchange.v1.GetLatestRatesResponse.rates:type_name -> exchange.v1.GetLatestRatesResponse.RatesEntry
+	19, // 8: exchange.v1.GetLatestRatesResponse.timestamp:type_name -> google.protobuf.Timestamp
+	19, // 9: exchange.v1.ExchangeRate.timestamp:type_name -> google.protobuf.Timestamp
+	11, // 10: exchange.v1.GetHistoricalRatesResponse.rates:type_name -> exchange.v1.ExchangeRate
+	16, // 11: exchange.v1.RateUpdate.rates:type_name -> exchange.v1.PairRate
+	19, // 12: exchange.v1.RateUpdate.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 13: exchange.v1.ExchangeService.Convert:input_type -> exchange.v1.ConvertRequest
+	4,  // 14: exchange.v1.ExchangeService.BatchConvert:input_type -> exchange.v1.BatchConvertRequest
+	8,  // 15: exchange.v1.ExchangeService.GetLatestRates:input_type -> exchange.v1.GetLatestRatesRequest
+	10, // 16: exchange.v1.ExchangeService.GetHistoricalRates:input_type -> exchange.v1.GetHistoricalRatesRequest
+	13, // 17: exchange.v1.ExchangeService.ListCurrencies:input_type -> exchange.v1.ListCurrenciesRequest
+	15, // 18: exchange.v1.ExchangeService.WatchRates:input_type -> exchange.v1.WatchRatesRequest
+	1,  // 19: exchange.v1.ExchangeService.Convert:output_type -> exchange.v1.ConvertResponse
+	7,  // 20: exchange.v1.ExchangeService.BatchConvert:output_type -> exchange.v1.BatchConvertResponse
+	9,  // 21: exchange.v1.ExchangeService.GetLatestRates:output_type -> exchange.v1.GetLatestRatesResponse
+	12, // 22: exchange.v1.ExchangeService.GetHistoricalRates:output_type -> exchange.v1.GetHistoricalRatesResponse
+	14, // 23: exchange.v1.ExchangeService.ListCurrencies:output_type -> exchange.v1.ListCurrenciesResponse
+	17, // 24: exchange.v1.ExchangeService.WatchRates:output_type -> exchange.v1.RateUpdate
+	19, // [19:25] is the sub-list for method output_type
+	13, // [13:19] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_exchange_v1_exchange_proto_init() }
+func file_exchange_v1_exchange_proto_init() {
+	if File_exchange_v1_exchange_proto != nil {
+		return
+	}
+	file_exchange_v1_exchange_proto_msgTypes[6].OneofWrappers = []any{
+		(*BatchConvertResult_Result)(nil),
+		(*BatchConvertResult_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_exchange_v1_exchange_proto_rawDesc), len(file_exchange_v1_exchange_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_exchange_v1_exchange_proto_goTypes,
+		DependencyIndexes: file_exchange_v1_exchange_proto_depIdxs,
+		MessageInfos:      file_exchange_v1_exchange_proto_msgTypes,
+	}.Build()
+	File_exchange_v1_exchange_proto = out.File
+	file_exchange_v1_exchange_proto_goTypes = nil
+	file_exchange_v1_exchange_proto_depIdxs = nil
+}
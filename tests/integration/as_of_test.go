@@ -0,0 +1,175 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAsOfQueriesReturnRatesAsTheyWereKnown(t *testing.T) {
+	logger := zap.NewNop()
+	history, err := repository.NewObservationRepository("", 0)
+	require.NoError(t, err)
+	cacheRepo := repository.NewCacheRepository()
+	apiRepo := newStubRatesRepository()
+	exchangeService := service.NewExchangeService(cacheRepo, apiRepo, logger, service.WithRateHistory(history))
+	router := api.NewRouter(exchangeService, logger)
+
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	asOf := func(t time.Time) string { return url.QueryEscape(t.UTC().Format(time.RFC3339Nano)) }
+
+	beforeAny := time.Now()
+	w := keyRequest(router, "/api/v1/convert?from=USD&to=INR&date="+yesterday+"&as_of="+asOf(beforeAny), "", "")
+	assert.Equal(t, http.StatusNotFound, w.Code, "nothing was known yet")
+
+	w = keyRequest(router, "/api/v1/convert?from=USD&to=INR&date="+yesterday, "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	time.Sleep(5 * time.Millisecond)
+	original := time.Now()
+	time.Sleep(5 * time.Millisecond)
+
+	apiRepo.set("USD", "INR", 83.90)
+	require.NoError(t, cacheRepo.Clear())
+	w = keyRequest(router, "/api/v1/convert?from=USD&to=INR&date="+yesterday, "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var conversion domain.ConversionResponse
+	w = keyRequest(router, "/api/v1/convert?from=USD&to=INR&amount=10&date="+yesterday+"&as_of="+asOf(original), "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conversion))
+	assert.Equal(t, 83.25, conversion.Rate, "the revision was not known yet")
+	assert.Equal(t, 832.5, conversion.Amount)
+	require.NotNil(t, conversion.ObservedAt)
+	assert.True(t, conversion.ObservedAt.Before(original))
+
+	w = keyRequest(router, "/api/v1/convert?from=USD&to=INR&date="+yesterday+"&as_of="+asOf(time.Now()), "", "")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conversion))
+	assert.Equal(t, 83.90, conversion.Rate)
+
+	calls := apiRepo.callCount("GetHistoricalRate")
+	w = keyRequest(router, "/api/v1/historical?from=USD&to=INR&start_date="+yesterday+"&end_date="+yesterday+"&as_of="+asOf(original), "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var historical domain.HistoricalRatesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &historical))
+	assert.Equal(t, 83.25, historical.Rates[yesterday].Rate)
+	require.NotNil(t, historical.AsOf)
+	assert.Equal(t, calls, apiRepo.callCount("GetHistoricalRate"), "as_of queries never reach the provider")
+
+	w = keyRequest(router, "/api/v1/convert?from=USD&to=INR&as_of=yesterday", "", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAsOfLatestConversionUsesRatesFetchedByThen(t *testing.T) {
+	logger := zap.NewNop()
+	history, err := repository.NewObservationRepository("", 0)
+	require.NoError(t, err)
+	cacheRepo := repository.NewCacheRepository()
+	apiRepo := newStubRatesRepository()
+	exchangeService := service.NewExchangeService(cacheRepo, apiRepo, logger, service.WithRateHistory(history))
+	router := api.NewRouter(exchangeService, logger)
+
+	_, err = exchangeService.RefreshRates(context.Background(), "USD")
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	refreshed := time.Now()
+	time.Sleep(5 * time.Millisecond)
+
+	apiRepo.set("USD", "EUR", 0.9)
+	_, err = exchangeService.RefreshRates(context.Background(), "USD")
+	require.NoError(t, err)
+
+	w := keyRequest(router, "/api/v1/convert?from=USD&to=EUR&as_of="+url.QueryEscape(refreshed.UTC().Format(time.RFC3339Nano)), "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var conversion domain.ConversionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conversion))
+	assert.Equal(t, 0.85, conversion.Rate)
+}
+
+func TestAsOfHistoricalRangeIsBounded(t *testing.T) {
+	logger := zap.NewNop()
+	history, err := repository.NewObservationRepository("", 0)
+	require.NoError(t, err)
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger, service.WithRateHistory(history))
+	router := api.NewRouter(exchangeService, logger)
+
+	asOf := url.QueryEscape(time.Now().UTC().Format(time.RFC3339))
+
+	w := keyRequest(router, "/api/v1/historical?from=USD&to=INR&start_date=2024-01-01&end_date=2024-12-31&as_of="+asOf, "", "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = keyRequest(router, "/api/v1/historical?from=USD&to=INR&start_date=2024-01-01&end_date=2025-01-01&as_of="+asOf, "", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = keyRequest(router, "/api/v1/historical?from=USD&to=INR&start_date=0001-01-01&end_date=9999-12-31&as_of="+asOf, "", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var resp domain.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, domain.CodeDateOutOfRange, resp.Error)
+}
+
+func TestAsOfAppliesOverridesInForceThen(t *testing.T) {
+	logger := zap.NewNop()
+	history, err := repository.NewObservationRepository("", 0)
+	require.NoError(t, err)
+	overrideRepo, err := repository.NewOverrideRepository("")
+	require.NoError(t, err)
+	overrideService := service.NewOverrideService(overrideRepo, logger)
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger,
+		service.WithRateHistory(history), service.WithOverrides(overrideService))
+	router := api.NewRouter(exchangeService, logger)
+
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	asOf := func(t time.Time) string { return url.QueryEscape(t.UTC().Format(time.RFC3339Nano)) }
+
+	w := keyRequest(router, "/api/v1/convert?from=USD&to=INR&date="+yesterday, "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	time.Sleep(5 * time.Millisecond)
+	beforeOverride := time.Now()
+	time.Sleep(5 * time.Millisecond)
+
+	created, err := overrideService.Create(&domain.RateOverrideRequest{Pair: "USDINR", Rate: 80, StartDate: yesterday, EndDate: yesterday, Reason: "contract"}, "alice")
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	inForce := time.Now()
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, overrideService.Delete(created.ID, "bob", "contract ended"))
+
+	var conversion domain.ConversionResponse
+	w = keyRequest(router, "/api/v1/convert?from=USD&to=INR&date="+yesterday+"&as_of="+asOf(inForce), "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conversion))
+	assert.Equal(t, 80.0, conversion.Rate, "the override was in force")
+	assert.Equal(t, domain.SourceOverride, conversion.Source)
+
+	w = keyRequest(router, "/api/v1/convert?from=INR&to=USD&date="+yesterday+"&as_of="+asOf(inForce), "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conversion))
+	assert.InDelta(t, 1/80.0, conversion.Rate, 1e-12)
+
+	for _, at := range []time.Time{beforeOverride, time.Now()} {
+		w = keyRequest(router, "/api/v1/convert?from=USD&to=INR&date="+yesterday+"&as_of="+asOf(at), "", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		conversion = domain.ConversionResponse{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conversion))
+		assert.Equal(t, 83.25, conversion.Rate)
+		assert.NotEqual(t, domain.SourceOverride, conversion.Source)
+	}
+
+	w = keyRequest(router, "/api/v1/historical?from=USD&to=INR&start_date="+yesterday+"&end_date="+yesterday+"&as_of="+asOf(inForce), "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var historical domain.HistoricalRatesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &historical))
+	assert.Equal(t, 80.0, historical.Rates[yesterday].Rate)
+}
@@ -0,0 +1,212 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testAdminToken = "admin-secret"
+
+func newAuthRouter(t *testing.T) *gin.Engine {
+	return newAuthRouterIn(t, "")
+}
+
+// newAuthRouterIn keeps keys and usage in dir, or in memory when dir is
+// empty.
+func newAuthRouterIn(t *testing.T, dir string) *gin.Engine {
+	t.Helper()
+
+	logger := zap.NewNop()
+	cacheRepo := repository.NewCacheRepository()
+	exchangeService := service.NewExchangeService(cacheRepo, newStubRatesRepository(), logger)
+
+	var keyPath, usagePath string
+	if dir != "" {
+		keyPath, usagePath = filepath.Join(dir, "api_keys.json"), filepath.Join(dir, "api_key_usage.jsonl")
+	}
+	keyRepo, err := repository.NewAPIKeyRepository(keyPath)
+	require.NoError(t, err)
+	keyService := service.NewAPIKeyService(keyRepo, newUsageStore(t, usagePath), service.NewRateLimiter(repository.NewCacheRepository()), logger, 60, 1000)
+
+	return api.NewRouter(exchangeService, logger,
+		api.WithAPIKeys(keyService, true),
+		api.WithAdminToken(testAdminToken),
+		api.WithCacheAdmin(service.NewCacheAdminService(cacheRepo, exchangeService, logger)),
+	)
+}
+
+func newUsageStore(t *testing.T, path string) *repository.APIKeyUsageRepository {
+	t.Helper()
+
+	store, err := repository.NewAPIKeyUsageRepository(path, 32*24*time.Hour)
+	require.NoError(t, err)
+	return store
+}
+
+func adminRequest(router *gin.Engine, method, url string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+
+	req, _ := http.NewRequest(method, url, &buf)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func createAPIKey(t *testing.T, router *gin.Engine, req domain.APIKeyRequest) domain.CreatedAPIKey {
+	t.Helper()
+
+	w := adminRequest(router, "POST", "/admin/keys", req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created domain.CreatedAPIKey
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	return created
+}
+
+func keyRequest(router *gin.Engine, url string, header, value string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", url, nil)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	router := newAuthRouter(t)
+	created := createAPIKey(t, router, domain.APIKeyRequest{Name: "partner-a"})
+
+	assert.NotEmpty(t, created.Key)
+	assert.Empty(t, created.Hash)
+	assert.Equal(t, 60, created.RateLimit)
+
+	tests := []struct {
+		name           string
+		url            string
+		header         string
+		value          string
+		expectedStatus int
+	}{
+		{"Missing key", "/api/v1/currencies", "", "", http.StatusUnauthorized},
+		{"Unknown key", "/api/v1/currencies", "X-API-Key", "ers_nope", http.StatusUnauthorized},
+		{"Header key", "/api/v1/currencies", "X-API-Key", created.Key, http.StatusOK},
+		{"Bearer key", "/api/v1/currencies", "Authorization", "Bearer " + created.Key, http.StatusOK},
+		{"Root convert alias", "/convert?from=USD&to=INR", "", "", http.StatusUnauthorized},
+		{"Health stays open", "/health", "", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := keyRequest(router, tt.url, tt.header, tt.value)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+
+	w := adminRequest(router, "DELETE", "/admin/keys/"+created.ID, nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = keyRequest(router, "/api/v1/currencies", "X-API-Key", created.Key)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAPIKeyRateLimitAndQuota(t *testing.T) {
+	router := newAuthRouter(t)
+
+	limited := createAPIKey(t, router, domain.APIKeyRequest{Name: "burst", RateLimit: 2})
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, keyRequest(router, "/api/v1/currencies", "X-API-Key", limited.Key).Code)
+	}
+	w := keyRequest(router, "/api/v1/currencies", "X-API-Key", limited.Key)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.True(t, retryAfter > 0 && retryAfter <= 30)
+	assert.Contains(t, w.Body.String(), "rate_limited")
+
+	quota := createAPIKey(t, router, domain.APIKeyRequest{Name: "quota", DailyQuota: 1})
+	assert.Equal(t, http.StatusOK, keyRequest(router, "/api/v1/currencies", "X-API-Key", quota.Key).Code)
+	w = keyRequest(router, "/api/v1/currencies", "X-API-Key", quota.Key)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "quota_exceeded")
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	w = adminRequest(router, "GET", "/admin/keys/"+limited.ID+"/usage?days=1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report domain.APIKeyUsageReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	require.Len(t, report.Usage, 1)
+	assert.Equal(t, 2, report.Usage[0].Requests)
+	assert.Equal(t, 1, report.Usage[0].RateLimited)
+}
+
+func TestAPIKeyQuotaSurvivesCacheClearAndRestart(t *testing.T) {
+	dir := t.TempDir()
+	router := newAuthRouterIn(t, dir)
+
+	quota := createAPIKey(t, router, domain.APIKeyRequest{Name: "quota", DailyQuota: 1})
+	require.Equal(t, http.StatusOK, keyRequest(router, "/api/v1/currencies", "X-API-Key", quota.Key).Code)
+
+	w := adminRequest(router, "GET", "/admin/cache?prefix=apikey_", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), quota.ID, "usage is not in the rates cache")
+
+	w = adminRequest(router, "DELETE", "/admin/cache?prefix=apikey_", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusTooManyRequests, keyRequest(router, "/api/v1/currencies", "X-API-Key", quota.Key).Code)
+
+	restarted := newAuthRouterIn(t, dir)
+	w = keyRequest(restarted, "/api/v1/currencies", "X-API-Key", quota.Key)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "quota_exceeded")
+
+	w = adminRequest(restarted, "GET", "/admin/keys/"+quota.ID+"/usage?days=1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report domain.APIKeyUsageReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	require.Len(t, report.Usage, 1)
+	assert.Equal(t, 1, report.Usage[0].Requests)
+	assert.Equal(t, 2, report.Usage[0].OverQuota)
+}
+
+func TestAdminRoutesRequireToken(t *testing.T) {
+	router := newAuthRouter(t)
+
+	req, _ := http.NewRequest("GET", "/admin/keys", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req.Header.Set("X-Admin-Token", "wrong")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = adminRequest(router, "GET", "/admin/keys", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = adminRequest(router, "GET", "/admin/keys/missing", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
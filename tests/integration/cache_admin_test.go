@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newCacheAdminRouter(t *testing.T) (*gin.Engine, *stubRatesRepository) {
+	t.Helper()
+
+	logger := zap.NewNop()
+	cacheRepo := repository.NewCacheRepository()
+	apiRepo := newStubRatesRepository()
+	exchangeService := service.NewExchangeService(cacheRepo, apiRepo, logger)
+
+	return api.NewRouter(exchangeService, logger,
+		api.WithAdminToken(testAdminToken),
+		api.WithCacheAdmin(service.NewCacheAdminService(cacheRepo, exchangeService, logger)),
+	), apiRepo
+}
+
+func convertedRate(t *testing.T, router *gin.Engine, from, to string) float64 {
+	t.Helper()
+
+	w := keyRequest(router, "/api/v1/convert?from="+from+"&to="+to, "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp domain.ConversionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Rate
+}
+
+func listCacheEntries(t *testing.T, router *gin.Engine, prefix string) []domain.CacheEntry {
+	t.Helper()
+
+	w := adminRequest(router, "GET", "/admin/cache?prefix="+prefix, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Entries []domain.CacheEntry `json:"entries"`
+		Count   int                 `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Entries, resp.Count)
+	return resp.Entries
+}
+
+func TestCacheAdminRequiresToken(t *testing.T) {
+	router, _ := newCacheAdminRouter(t)
+
+	w := keyRequest(router, "/admin/cache", "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestCacheAdminInspection(t *testing.T) {
+	router, _ := newCacheAdminRouter(t)
+	convertedRate(t, router, "USD", "INR")
+	convertedRate(t, router, "EUR", "GBP")
+
+	entries := listCacheEntries(t, router, "")
+	require.Len(t, entries, 2)
+	assert.Equal(t, "rate_EUR_GBP_latest", entries[0].Key)
+	assert.Equal(t, "rate_USD_INR_latest", entries[1].Key)
+	assert.Nil(t, entries[0].Value)
+	assert.InDelta(t, 3600, entries[0].TTLSeconds, 5)
+	assert.GreaterOrEqual(t, entries[0].AgeSeconds, int64(0))
+
+	entries = listCacheEntries(t, router, "rate_USD_")
+	require.Len(t, entries, 1)
+
+	w := adminRequest(router, "GET", "/admin/cache/rate_USD_INR_latest", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var entry domain.CacheEntry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entry))
+	assert.Equal(t, 83.25, entry.Value.(map[string]interface{})["rate"])
+
+	w = adminRequest(router, "GET", "/admin/cache/missing", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCacheAdminInvalidation(t *testing.T) {
+	router, apiRepo := newCacheAdminRouter(t)
+	convertedRate(t, router, "USD", "INR")
+	convertedRate(t, router, "USD", "EUR")
+	convertedRate(t, router, "EUR", "GBP")
+
+	apiRepo.set("USD", "INR", 84.1)
+	assert.Equal(t, 83.25, convertedRate(t, router, "USD", "INR"))
+
+	w := adminRequest(router, "DELETE", "/admin/cache/rate_USD_INR_latest", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, 84.1, convertedRate(t, router, "USD", "INR"))
+
+	w = adminRequest(router, "DELETE", "/admin/cache/rate_USD_INR_missing", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = adminRequest(router, "DELETE", "/admin/cache?prefix=rate_USD_", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"deleted":2}`, w.Body.String())
+	assert.Len(t, listCacheEntries(t, router, ""), 1)
+
+	w = adminRequest(router, "DELETE", "/admin/cache", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, listCacheEntries(t, router, ""), 1)
+}
+
+func TestCacheAdminRefresh(t *testing.T) {
+	router, apiRepo := newCacheAdminRouter(t)
+	convertedRate(t, router, "USD", "INR")
+	apiRepo.set("USD", "INR", 84.1)
+
+	w := adminRequest(router, "POST", "/admin/cache/refresh?base=USD", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp domain.CacheRefreshResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"USD"}, resp.Refreshed)
+	assert.Empty(t, resp.Failed)
+	assert.Equal(t, 1, apiRepo.callCount("GetAllLatestRates"))
+
+	entries := listCacheEntries(t, router, "")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "latest_rates_USD", entries[0].Key)
+	assert.Equal(t, 84.1, convertedRate(t, router, "USD", "INR"))
+
+	w = adminRequest(router, "POST", "/admin/cache/refresh", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.ElementsMatch(t, []string{"USD", "EUR", "GBP", "INR", "JPY"}, resp.Refreshed)
+	assert.Len(t, listCacheEntries(t, router, "latest_rates_"), 5)
+
+	w = adminRequest(router, "POST", "/admin/cache/refresh?base=XYZ", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,212 @@
+package integration
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newNegotiationRouter() *gin.Engine {
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger)
+	return api.NewRouter(exchangeService, logger)
+}
+
+func negotiate(router *gin.Engine, method, url, accept, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func readCSV(t *testing.T, w *httptest.ResponseRecorder) [][]string {
+	t.Helper()
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestLatestRatesAsCSV(t *testing.T) {
+	router := newNegotiationRouter()
+
+	w := negotiate(router, "GET", "/api/v1/latest?base=USD", "text/csv", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+
+	today := time.Now().Format("2006-01-02")
+	assert.Equal(t, [][]string{
+		{"base_currency", "currency", "rate", "date", "bid", "ask", "source"},
+		{"USD", "EUR", "0.85", today, "", "", ""},
+		{"USD", "GBP", "0.73", today, "", "", ""},
+		{"USD", "INR", "83.25", today, "", "", ""},
+		{"USD", "JPY", "110.5", today, "", "", ""},
+	}, readCSV(t, w))
+
+	again := negotiate(router, "GET", "/api/v1/latest?base=USD", "text/csv", "")
+	assert.Equal(t, w.Body.String(), again.Body.String())
+
+	w = negotiate(router, "GET", "/api/v1/latest?base=JPY", "text/csv", "")
+	assert.Contains(t, w.Body.String(), "JPY,USD,0.009,")
+}
+
+func TestHistoricalRatesAsXML(t *testing.T) {
+	router := newNegotiationRouter()
+
+	start := time.Now().AddDate(0, 0, -2).Format("2006-01-02")
+	end := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	url := "/api/v1/historical?from=USD&to=INR&start_date=" + start + "&end_date=" + end
+
+	for _, tt := range []struct{ name, url, accept string }{
+		{"Accept header", url, "application/xml"},
+		{"Text XML", url, "text/xml"},
+		{"Format override", url + "&format=xml", "application/json"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := negotiate(router, "GET", tt.url, tt.accept, "")
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+
+			var doc struct {
+				XMLName xml.Name `xml:"historical_rates"`
+				Rates   []struct {
+					Date string `xml:"date"`
+					From string `xml:"from_currency"`
+					To   string `xml:"to_currency"`
+					Rate string `xml:"rate"`
+				} `xml:"rate"`
+			}
+			require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &doc))
+			require.Len(t, doc.Rates, 2)
+			assert.Equal(t, start, doc.Rates[0].Date)
+			assert.Equal(t, end, doc.Rates[1].Date)
+			assert.Equal(t, "USD", doc.Rates[0].From)
+			assert.Equal(t, "83.25", doc.Rates[0].Rate)
+		})
+	}
+
+	w := negotiate(router, "GET", url+"&format=json", "text/csv", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	w = negotiate(router, "GET", url+"&format=yaml", "", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBatchConvert(t *testing.T) {
+	router := newNegotiationRouter()
+	body := `{"conversions":[{"from":"USD","to":"INR","amount":100},{"from":"USD","to":"XYZ"},{"from":"EUR","to":"GBP"}]}`
+
+	w := negotiate(router, "POST", "/api/v1/convert/batch", "", body)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp domain.BatchConversionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, 3, resp.Count)
+	require.NotNil(t, resp.Results[0].Result)
+	assert.Equal(t, 8325.0, resp.Results[0].Result.Amount)
+	assert.Nil(t, resp.Results[1].Result)
+	require.NotNil(t, resp.Results[1].Error)
+	assert.Equal(t, domain.CodeUnsupportedCurrency, resp.Results[1].Error.Error)
+	assert.Equal(t, http.StatusBadRequest, resp.Results[1].Error.Status)
+	assert.Equal(t, 0.86, resp.Results[2].Result.Rate)
+
+	w = negotiate(router, "POST", "/api/v1/convert/batch?format=csv", "", body)
+	require.Equal(t, http.StatusOK, w.Code)
+	records := readCSV(t, w)
+	require.Len(t, records, 4)
+	assert.Equal(t, []string{"from_currency", "to_currency", "amount", "rate", "converted_amount", "date", "bid_rate", "fee", "profile", "error"}, records[0])
+	assert.Equal(t, []string{"USD", "INR", "100", "83.25", "8325"}, records[1][:5])
+	assert.Equal(t, []string{"", "", "", ""}, records[1][6:], "unpriced conversions leave the pricing columns empty")
+	assert.Equal(t, []string{"USD", "XYZ", "1", "", ""}, records[2][:5])
+	assert.Contains(t, records[2][9], "unsupported currency pair")
+
+	w = negotiate(router, "POST", "/api/v1/convert/batch", "", `{"conversions":[]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLatestRatesTableCarriesPricingAndSource(t *testing.T) {
+	w := negotiate(newMarkupRouter(t, false), "GET", "/api/v1/latest?base=USD", "text/csv", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	rows := readCSV(t, w)
+	require.Len(t, rows, 5)
+	assert.Equal(t, []string{"base_currency", "currency", "rate", "date", "bid", "ask", "source"}, rows[0])
+	assert.Equal(t, "INR", rows[3][1])
+	bid, err := strconv.ParseFloat(rows[3][4], 64)
+	require.NoError(t, err)
+	ask, err := strconv.ParseFloat(rows[3][5], 64)
+	require.NoError(t, err)
+	assert.InDelta(t, 82.4175, bid, 1e-9)
+	assert.InDelta(t, 84.0825, ask, 1e-9)
+
+	router, _ := newOverrideRouter(t)
+	today := time.Now().Format("2006-01-02")
+	w = overrideRequest(router, "POST", "/admin/overrides", "alice", domain.RateOverrideRequest{
+		Pair: "USDINR", Rate: 80, StartDate: today, EndDate: today, Reason: "contract rate",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = negotiate(router, "GET", "/api/v1/latest?base=USD", "text/csv", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	rows = readCSV(t, w)
+	assert.Equal(t, []string{"base_currency", "currency", "rate", "date", "bid", "ask", "source"}, rows[0])
+	assert.Equal(t, []string{"USD", "EUR", "0.85", today, "", "", ""}, rows[1])
+	assert.Equal(t, []string{"USD", "INR", "80", today, "", "", "override"}, rows[3])
+
+	w = negotiate(router, "GET", "/api/v1/latest?base=USD&format=xml", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<source>override</source>")
+}
+
+func TestBatchTableCarriesPricing(t *testing.T) {
+	body := `{"conversions":[{"from":"USD","to":"INR","amount":100}]}`
+	w := negotiate(newMarkupRouter(t, false), "POST", "/api/v1/convert/batch?format=csv", "", body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	rows := readCSV(t, w)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"USD", "INR", "100", "83.25"}, rows[1][:4])
+	bid, err := strconv.ParseFloat(rows[1][6], 64)
+	require.NoError(t, err)
+	assert.InDelta(t, 82.4175, bid, 1e-9)
+	fee, err := strconv.ParseFloat(rows[1][7], 64)
+	require.NoError(t, err)
+	assert.InDelta(t, 83.25, fee, 1e-9)
+	assert.Equal(t, "retail", rows[1][8])
+	assert.Empty(t, rows[1][9])
+}
+
+func TestHistoricalTableHasSource(t *testing.T) {
+	w := negotiate(newNegotiationRouter(), "GET", "/api/v1/historical?from=USD&to=INR&start_date="+time.Now().AddDate(0, 0, -1).Format("2006-01-02")+"&end_date="+time.Now().Format("2006-01-02"), "text/csv", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	rows := readCSV(t, w)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"date", "from_currency", "to_currency", "rate", "source"}, rows[0])
+	for _, row := range rows[1:] {
+		assert.Len(t, row, 5)
+	}
+}
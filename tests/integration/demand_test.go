@@ -0,0 +1,203 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDemandGroupRefreshesHotSet(t *testing.T) {
+	logger := zap.NewNop()
+	stub := newStubRatesRepository()
+	clk := clock.NewFake(updaterStart)
+	demand := service.NewDemandTracker(clk, service.DemandOptions{TopPairs: 1, TopBases: 1, HalfLife: time.Hour, MinScore: 1})
+
+	schedules, err := service.ParseUpdateGroups([]domain.UpdateGroup{
+		service.DemandUpdateGroup("@hourly", ""),
+	})
+	require.NoError(t, err)
+	runs, err := repository.NewUpdateRunRepository("")
+	require.NoError(t, err)
+	require.NoError(t, runs.SetLastRun("demand", updaterStart.Add(-10*time.Minute)))
+
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), stub, logger,
+		service.WithDemand(demand),
+		service.WithUpdater(service.UpdaterOptions{Schedules: schedules, Runs: runs, Clock: clk}))
+	router := api.NewRouter(exchangeService, logger, api.WithAdminToken(testAdminToken), api.WithDemand(demand))
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := exchangeService.ConvertCurrency(ctx, &domain.ConversionRequest{From: "USD", To: "INR", Amount: 1})
+		require.NoError(t, err)
+	}
+	_, err = exchangeService.ConvertCurrency(ctx, &domain.ConversionRequest{From: "GBP", To: "JPY", Amount: 1})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = exchangeService.GetLatestRates(ctx, "EUR")
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, stub.callCount("GetLatestRate"))
+	require.Equal(t, 1, stub.callCount("GetAllLatestRates"))
+
+	updaterCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		exchangeService.StartRateUpdater(updaterCtx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	require.Eventually(t, func() bool { return clk.Waiters() == 1 }, time.Second, time.Millisecond)
+	clk.Set(time.Date(2025, 9, 1, 11, 0, 0, 0, time.UTC))
+	require.Eventually(t, func() bool {
+		last, _ := runs.LastRun("demand")
+		return last.Equal(time.Date(2025, 9, 1, 11, 0, 0, 0, time.UTC))
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, 3, stub.callCount("GetLatestRate"), "only the hot pair is refreshed")
+	assert.Equal(t, 2, stub.callCount("GetAllLatestRates"), "only the hot base is refreshed")
+
+	w := adminRequest(router, "GET", "/admin/demand", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		HotPairs  []domain.DemandStat        `json:"hot_pairs"`
+		HotBases  []domain.DemandStat        `json:"hot_bases"`
+		Schedules []domain.UpdateGroupStatus `json:"schedules"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.HotPairs, 1)
+	assert.Equal(t, "USDINR", body.HotPairs[0].Key)
+	assert.Equal(t, int64(2), body.HotPairs[0].Requests)
+	require.Len(t, body.HotBases, 1)
+	assert.Equal(t, "EUR", body.HotBases[0].Key)
+
+	require.Len(t, body.Schedules, 1)
+	status := body.Schedules[0]
+	assert.Equal(t, "demand", status.Name)
+	assert.True(t, status.Demand)
+	assert.Equal(t, "UTC", status.Timezone)
+	require.NotNil(t, status.LastRun)
+	assert.True(t, status.LastRun.Equal(time.Date(2025, 9, 1, 11, 0, 0, 0, time.UTC)))
+	assert.True(t, status.NextRun.Equal(time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestDemandEndpointDisabledWithoutTracker(t *testing.T) {
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger)
+	router := api.NewRouter(exchangeService, logger, api.WithAdminToken(testAdminToken))
+
+	w := adminRequest(router, "GET", "/admin/demand", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDemandTrackingKeepsStreamsUpdated(t *testing.T) {
+	logger := zap.NewNop()
+	clk := clock.NewFake(updaterStart)
+	demand := service.NewDemandTracker(clk, service.DemandOptions{TopPairs: 1, TopBases: 1, HalfLife: time.Hour, MinScore: 1})
+
+	groups := service.AddDemandGroup([]domain.UpdateGroup{service.DefaultUpdateGroup("", "")}, "*/15 * * * *", "")
+	require.Len(t, groups, 2)
+	assert.Len(t, service.AddDemandGroup(groups, "@hourly", ""), 2, "a demand group is added once")
+
+	schedules, err := service.ParseUpdateGroups(groups)
+	require.NoError(t, err)
+	runs, err := repository.NewUpdateRunRepository("")
+	require.NoError(t, err)
+
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger,
+		service.WithDemand(demand),
+		service.WithUpdater(service.UpdaterOptions{Schedules: schedules, Runs: runs, Clock: clk}))
+
+	server := httptest.NewServer(api.NewRouter(exchangeService, logger, api.WithDemand(demand)))
+	defer server.Close()
+
+	conn := dialWebSocket(t, server)
+	require.NoError(t, conn.WriteJSON(domain.StreamRequest{Action: "subscribe", Pairs: []string{"USDINR"}}))
+	require.Equal(t, "subscribed", readStreamMessage(t, conn).Type)
+
+	updaterCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		exchangeService.StartRateUpdater(updaterCtx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	msg := readStreamMessage(t, conn)
+	assert.Equal(t, "update", msg.Type, "the default group runs while the hot set is empty")
+	require.Len(t, msg.Rates, 1)
+	assert.Equal(t, "USDINR", msg.Rates[0].Pair)
+	assert.Equal(t, 83.25, msg.Rates[0].Rate)
+}
+
+func TestDemandGroupSkipsPairsOfRefreshedBases(t *testing.T) {
+	logger := zap.NewNop()
+	stub := newStubRatesRepository()
+	clk := clock.NewFake(updaterStart)
+	demand := service.NewDemandTracker(clk, service.DemandOptions{TopPairs: 2, TopBases: 1, HalfLife: time.Hour, MinScore: 1})
+
+	schedules, err := service.ParseUpdateGroups([]domain.UpdateGroup{
+		service.DemandUpdateGroup("@hourly", ""),
+	})
+	require.NoError(t, err)
+	runs, err := repository.NewUpdateRunRepository("")
+	require.NoError(t, err)
+	require.NoError(t, runs.SetLastRun("demand", updaterStart.Add(-10*time.Minute)))
+
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), stub, logger,
+		service.WithDemand(demand),
+		service.WithUpdater(service.UpdaterOptions{Schedules: schedules, Runs: runs, Clock: clk}))
+
+	ctx := context.Background()
+	for _, pair := range [][2]string{{"USD", "INR"}, {"USD", "INR"}, {"EUR", "GBP"}, {"EUR", "GBP"}} {
+		_, err := exchangeService.ConvertCurrency(ctx, &domain.ConversionRequest{From: pair[0], To: pair[1], Amount: 1})
+		require.NoError(t, err)
+	}
+	for i := 0; i < 2; i++ {
+		_, err = exchangeService.GetLatestRates(ctx, "USD")
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, stub.callCount("GetLatestRate"))
+	require.Equal(t, 1, stub.callCount("GetAllLatestRates"))
+
+	updaterCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		exchangeService.StartRateUpdater(updaterCtx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	require.Eventually(t, func() bool { return clk.Waiters() == 1 }, time.Second, time.Millisecond)
+	clk.Set(time.Date(2025, 9, 1, 11, 0, 0, 0, time.UTC))
+	require.Eventually(t, func() bool {
+		last, _ := runs.LastRun("demand")
+		return last.Equal(time.Date(2025, 9, 1, 11, 0, 0, 0, time.UTC))
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, 2, stub.callCount("GetAllLatestRates"), "the hot base is refreshed")
+	assert.Equal(t, 3, stub.callCount("GetLatestRate"), "USDINR comes with the USD table; only EURGBP is fetched")
+}
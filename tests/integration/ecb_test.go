@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newECBServer serves the fixture feeds in testdata/ecb and counts requests.
+func newECBServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	files := http.FileServer(http.Dir("testdata/ecb"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestECBLatestRatesRebased(t *testing.T) {
+	server, requests := newECBServer(t)
+	repo := repository.NewECBRepository(server.URL, zap.NewNop())
+	ctx := context.Background()
+
+	rate, err := repo.GetLatestRate(ctx, "EUR", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, 1.16, rate.Rate)
+	assert.Equal(t, "2025-09-02", rate.Date)
+
+	rate, err = repo.GetLatestRate(ctx, "USD", "INR")
+	require.NoError(t, err)
+	assert.InDelta(t, 88.0, rate.Rate, 1e-9)
+
+	rates, err := repo.GetAllLatestRates(ctx, "GBP")
+	require.NoError(t, err)
+	assert.Len(t, rates, 4, "only supported currencies other than the base")
+	assert.InDelta(t, 1/0.865, rates["EUR"], 1e-9)
+	assert.InDelta(t, 1.16/0.865, rates["USD"], 1e-9)
+	assert.NotContains(t, rates, "CHF")
+	assert.NotContains(t, rates, "GBP")
+
+	assert.Equal(t, int32(1), requests.Load(), "the parsed feed is reused")
+}
+
+func TestECBHistoricalRates(t *testing.T) {
+	server, _ := newECBServer(t)
+	repo := repository.NewECBRepository(server.URL, zap.NewNop())
+	ctx := context.Background()
+
+	rate, err := repo.GetHistoricalRate(ctx, "USD", "EUR", "2025-09-01")
+	require.NoError(t, err)
+	assert.InDelta(t, 1/1.17, rate.Rate, 1e-9)
+	assert.Equal(t, "2025-09-01", rate.Date)
+
+	weekend, err := repo.GetHistoricalRate(ctx, "EUR", "JPY", "2025-08-31")
+	require.NoError(t, err)
+	assert.Equal(t, 171.6, weekend.Rate, "weekends use the previous publication")
+	assert.Equal(t, "2025-08-31", weekend.Date)
+
+	_, err = repo.GetHistoricalRate(ctx, "EUR", "USD", "2025-08-01")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestECBFeedFailure(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	repo := repository.NewECBRepository(server.URL, zap.NewNop())
+	_, err := repo.GetLatestRate(context.Background(), "EUR", "USD")
+	assert.Error(t, err)
+}
+
+func TestConvertWithECBProvider(t *testing.T) {
+	server, _ := newECBServer(t)
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), repository.NewECBRepository(server.URL, logger), logger)
+	router := api.NewRouter(exchangeService, logger)
+
+	w := keyRequest(router, "/api/v1/convert?from=EUR&to=INR&amount=10", "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp domain.ConversionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 102.08, resp.Rate)
+	assert.InDelta(t, 1020.8, resp.Amount, 1e-9)
+	assert.Equal(t, "2025-09-02", resp.Date)
+}
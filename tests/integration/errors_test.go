@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type failingRatesRepository struct{}
+
+var errProviderDown = errors.New("dial tcp 10.0.0.1:443: connect: connection refused")
+
+func (failingRatesRepository) GetLatestRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	return nil, errProviderDown
+}
+
+func (failingRatesRepository) GetHistoricalRate(ctx context.Context, from, to, date string) (*domain.ExchangeRate, error) {
+	return nil, errProviderDown
+}
+
+func (failingRatesRepository) GetAllLatestRates(ctx context.Context, baseCurrency string) (map[string]float64, error) {
+	return nil, errProviderDown
+}
+
+func newErrorRouter(apiRepo domain.ExchangeRepository) *gin.Engine {
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger)
+	return api.NewRouter(exchangeService, logger)
+}
+
+func errorRequest(router *gin.Engine, url string, headers map[string]string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", url, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestErrorCodesAndStatuses(t *testing.T) {
+	router := newErrorRouter(newStubRatesRepository())
+	down := newErrorRouter(failingRatesRepository{})
+
+	tests := []struct {
+		name   string
+		router *gin.Engine
+		url    string
+		status int
+		code   string
+	}{
+		{"Unsupported base currency", router, "/api/v1/latest?base=XYZ", http.StatusBadRequest, domain.CodeUnsupportedCurrency},
+		{"Unsupported pair", router, "/api/v1/convert?from=USD&to=XYZ", http.StatusBadRequest, domain.CodeUnsupportedCurrency},
+		{"Date too old", router, "/api/v1/convert?from=USD&to=INR&date=2000-01-01", http.StatusBadRequest, domain.CodeDateOutOfRange},
+		{"Range too old", router, "/api/v1/historical?from=USD&to=INR&start_date=2000-01-02&end_date=2000-01-01", http.StatusBadRequest, domain.CodeDateOutOfRange},
+		{"Missing parameter", router, "/convert?from=USD", http.StatusBadRequest, domain.CodeValidation},
+		{"Upstream down on convert", down, "/api/v1/convert?from=USD&to=INR", http.StatusServiceUnavailable, domain.CodeUpstreamUnavailable},
+		{"Upstream down on latest", down, "/api/v1/latest", http.StatusServiceUnavailable, domain.CodeUpstreamUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := errorRequest(tt.router, tt.url, nil)
+			require.Equal(t, tt.status, w.Code)
+
+			var resp domain.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.code, resp.Error)
+			assert.Equal(t, tt.status, resp.Status)
+			assert.NotEmpty(t, resp.RequestID)
+			assert.Equal(t, w.Header().Get("X-Request-ID"), resp.RequestID)
+			assert.NotContains(t, resp.Message, "10.0.0.1")
+		})
+	}
+}
+
+func TestProblemDetails(t *testing.T) {
+	router := newErrorRouter(newStubRatesRepository())
+
+	w := errorRequest(router, "/api/v1/latest?base=XYZ", map[string]string{
+		"Accept":       "application/problem+json",
+		"X-Request-ID": "ticket-4711",
+	})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "ticket-4711", w.Header().Get("X-Request-ID"))
+
+	var details problem.Details
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+	assert.Equal(t, problem.Details{
+		Type:      "urn:exchange-rate-service:problem:unsupported_currency",
+		Title:     "Bad Request",
+		Status:    http.StatusBadRequest,
+		Detail:    "unsupported base currency: XYZ",
+		Instance:  "/api/v1/latest",
+		Code:      domain.CodeUnsupportedCurrency,
+		RequestID: "ticket-4711",
+	}, details)
+}
+
+func TestRequestIDIsGeneratedWhenMissingOrMalformed(t *testing.T) {
+	router := newErrorRouter(newStubRatesRepository())
+
+	first := errorRequest(router, "/health", nil).Header().Get("X-Request-ID")
+	second := errorRequest(router, "/health", nil).Header().Get("X-Request-ID")
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+
+	w := errorRequest(router, "/health", map[string]string{"X-Request-ID": "bad id\r\nwith spaces"})
+	assert.NotEqual(t, "bad id\r\nwith spaces", w.Header().Get("X-Request-ID"))
+	assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
+}
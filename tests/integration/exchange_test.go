@@ -21,7 +21,7 @@ import (
 func TestConvertEndpoint(t *testing.T) {
 	logger := zap.NewNop()
 	cacheRepo := repository.NewCacheRepository()
-	apiRepo := repository.NewExchangeAPIRepository("", "")
+	apiRepo := repository.NewExchangeAPIRepository("", "", newSnapshotRepository(t), logger)
 	exchangeService := service.NewExchangeService(cacheRepo, apiRepo, logger)
 
 	router := api.NewRouter(exchangeService, logger)
@@ -62,7 +62,7 @@ func TestConvertEndpoint(t *testing.T) {
 func TestHealthEndpoint(t *testing.T) {
 	logger := zap.NewNop()
 	cacheRepo := repository.NewCacheRepository()
-	apiRepo := repository.NewExchangeAPIRepository("", "")
+	apiRepo := repository.NewExchangeAPIRepository("", "", newSnapshotRepository(t), logger)
 	exchangeService := service.NewExchangeService(cacheRepo, apiRepo, logger)
 
 	router := api.NewRouter(exchangeService, logger)
@@ -0,0 +1,130 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newSnapshotRepository serves the versioned demo rates in fixtures/rates.
+func newSnapshotRepository(t *testing.T) *repository.FileRatesRepository {
+	t.Helper()
+
+	repo, err := repository.NewFileRatesRepository(filepath.Join("..", "..", "fixtures", "rates"), zap.NewNop())
+	require.NoError(t, err)
+	return repo
+}
+
+func writeSnapshot(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+func TestFileRatesSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	writeSnapshot(t, dir, "2025-09-01.json", `{"USD": {"INR": 83.0, "EUR": 0.85}}`)
+	writeSnapshot(t, dir, "2025-09-03.csv", "base_currency,currency,rate\nUSD,INR,84.5\nEUR,USD,1.2\n")
+	writeSnapshot(t, dir, "notes.txt", "ignored")
+
+	repo, err := repository.NewFileRatesRepository(dir, zap.NewNop())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	latest, err := repo.GetLatestRate(ctx, "USD", "INR")
+	require.NoError(t, err)
+	assert.Equal(t, 84.5, latest.Rate)
+	assert.Equal(t, "2025-09-03", latest.Date)
+
+	inverse, err := repo.GetLatestRate(ctx, "USD", "EUR")
+	require.NoError(t, err)
+	assert.InDelta(t, 1/1.2, inverse.Rate, 1e-9, "missing tables are derived from the opposite quote")
+
+	between, err := repo.GetHistoricalRate(ctx, "USD", "INR", "2025-09-02")
+	require.NoError(t, err)
+	assert.Equal(t, 83.0, between.Rate, "dates between snapshots use the earlier one")
+	assert.Equal(t, "2025-09-02", between.Date)
+
+	_, err = repo.GetHistoricalRate(ctx, "USD", "INR", "2025-08-31")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	rates, err := repo.GetAllLatestRates(ctx, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"INR": 84.5, "EUR": 1 / 1.2}, rates)
+}
+
+func TestFileRatesWatchPicksUpNewSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	writeSnapshot(t, dir, "2025-09-01.json", `{"USD": {"INR": 83.0}}`)
+
+	repo, err := repository.NewFileRatesRepository(dir, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go repo.Watch(ctx, 10*time.Millisecond)
+
+	writeSnapshot(t, dir, "2025-09-02.json", `{"USD": {"INR": 85.0}}`)
+	assert.Eventually(t, func() bool {
+		rate, err := repo.GetLatestRate(context.Background(), "USD", "INR")
+		return err == nil && rate.Rate == 85.0
+	}, time.Second, 10*time.Millisecond)
+
+	writeSnapshot(t, dir, "2025-09-03.json", `{not json`)
+	time.Sleep(50 * time.Millisecond)
+	rate, err := repo.GetLatestRate(context.Background(), "USD", "INR")
+	require.NoError(t, err)
+	assert.Equal(t, 85.0, rate.Rate, "a broken snapshot keeps the last good set")
+}
+
+func TestExchangeAPIFallsBackToSnapshots(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+	ctx := context.Background()
+
+	repo := repository.NewExchangeAPIRepository("", upstream.URL, newSnapshotRepository(t), zap.NewNop())
+	rate, err := repo.GetLatestRate(ctx, "USD", "INR")
+	require.NoError(t, err)
+	assert.Equal(t, 83.25, rate.Rate)
+
+	bare := repository.NewExchangeAPIRepository("", upstream.URL, nil, zap.NewNop())
+	_, err = bare.GetLatestRate(ctx, "USD", "INR")
+	assert.Error(t, err, "without a fallback provider failures surface")
+}
+
+func TestExchangeAPIRejectsStaleFallbackRates(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+	ctx := context.Background()
+
+	repo := repository.NewExchangeAPIRepository("", upstream.URL, newSnapshotRepository(t), zap.NewNop())
+	repo.SetFallbackMaxAge(48 * time.Hour)
+
+	_, err := repo.GetLatestRate(ctx, "USD", "INR")
+	assert.ErrorIs(t, err, domain.ErrStaleData)
+	assert.Equal(t, domain.CodeStaleData, domain.ErrorCode(err))
+
+	_, err = repo.GetAllLatestRates(ctx, "USD")
+	assert.ErrorIs(t, err, domain.ErrStaleData)
+
+	rate, err := repo.GetHistoricalRate(ctx, "USD", "INR", "2025-09-02")
+	require.NoError(t, err, "the snapshot is recent enough for the day asked about")
+	assert.Equal(t, 83.25, rate.Rate)
+
+	_, err = repo.GetHistoricalRate(ctx, "USD", "INR", "2025-09-05")
+	assert.ErrorIs(t, err, domain.ErrStaleData)
+}
@@ -0,0 +1,199 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newFixingRouter(t *testing.T) (*gin.Engine, *service.ExchangeService, *stubRatesRepository, *clock.Fake) {
+	t.Helper()
+
+	logger := zap.NewNop()
+	fixingRepo, err := repository.NewFixingRepository("")
+	require.NoError(t, err)
+	schedule, err := service.ParseFixingSchedule("16:00", "Europe/Paris")
+	require.NoError(t, err)
+
+	apiRepo := newStubRatesRepository()
+	clk := clock.NewFake(time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC))
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger,
+		service.WithFixings(fixingRepo, schedule),
+		service.WithUpdater(service.UpdaterOptions{Clock: clk}))
+
+	return api.NewRouter(exchangeService, logger, api.WithAdminToken(testAdminToken)), exchangeService, apiRepo, clk
+}
+
+func TestFixingsAreCapturedOnceAndCorrectedWithHistory(t *testing.T) {
+	router, exchangeService, apiRepo, _ := newFixingRouter(t)
+	fixedAt := time.Date(2025, 9, 1, 14, 0, 0, 0, time.UTC)
+
+	w := keyRequest(router, "/api/v1/fixings/2025-09-01", "", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	captured, err := exchangeService.CaptureFixing(context.Background(), fixedAt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, captured.Version)
+	assert.Equal(t, "scheduler", captured.CreatedBy)
+	assert.Len(t, captured.Rates, 5)
+
+	apiRepo.set("USD", "INR", 90)
+	_, err = exchangeService.CaptureFixing(context.Background(), fixedAt)
+	assert.ErrorIs(t, err, domain.ErrConflict, "a fixing is immutable once captured")
+
+	w = keyRequest(router, "/api/v1/fixings/2025-09-01", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var fixing domain.Fixing
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fixing))
+	assert.Equal(t, 83.25, fixing.Rates["USD"]["INR"])
+	assert.Equal(t, "Europe/Paris", fixing.Timezone)
+	assert.True(t, fixedAt.Equal(fixing.FixedAt))
+
+	correction := domain.FixingCorrectionRequest{
+		Rates:  map[string]map[string]float64{"USD": {"INR": 83.3}},
+		Reason: "provider republished the 16:00 print",
+	}
+	w = overrideRequest(router, "POST", "/admin/fixings/2025-09-01/corrections", "", correction)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "corrections need an actor")
+
+	w = overrideRequest(router, "POST", "/admin/fixings/2025-09-01/corrections", "alice", correction)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var corrected domain.Fixing
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &corrected))
+	assert.Equal(t, 2, corrected.Version)
+	assert.Equal(t, 83.3, corrected.Rates["USD"]["INR"])
+	assert.Equal(t, 0.85, corrected.Rates["USD"]["EUR"], "rates not in the correction carry over")
+	assert.True(t, fixedAt.Equal(corrected.FixedAt))
+
+	w = keyRequest(router, "/api/v1/fixings/2025-09-01", "", "")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fixing))
+	assert.Equal(t, 2, fixing.Version)
+	assert.Equal(t, 83.3, fixing.Rates["USD"]["INR"])
+
+	w = keyRequest(router, "/api/v1/fixings/2025-09-01?version=1", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fixing))
+	assert.Equal(t, 83.25, fixing.Rates["USD"]["INR"], "the original is kept")
+
+	w = keyRequest(router, "/api/v1/fixings/2025-09-01?version=3", "", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = overrideRequest(router, "GET", "/admin/fixings/2025-09-01/history", "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var history struct {
+		Versions []domain.Fixing `json:"versions"`
+		Count    int             `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	require.Equal(t, 2, history.Count)
+	assert.Equal(t, []string{"scheduler", "alice"}, []string{history.Versions[0].CreatedBy, history.Versions[1].CreatedBy})
+	assert.Equal(t, "provider republished the 16:00 print", history.Versions[1].Reason)
+}
+
+func TestFixingCorrectionValidation(t *testing.T) {
+	router, exchangeService, _, _ := newFixingRouter(t)
+	_, err := exchangeService.CaptureFixing(context.Background(), time.Date(2025, 9, 1, 14, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		date   string
+		req    domain.FixingCorrectionRequest
+		status int
+	}{
+		{"No reason", "2025-09-01", domain.FixingCorrectionRequest{Rates: map[string]map[string]float64{"USD": {"INR": 83}}}, http.StatusBadRequest},
+		{"No rates", "2025-09-01", domain.FixingCorrectionRequest{Reason: "typo"}, http.StatusBadRequest},
+		{"Negative rate", "2025-09-01", domain.FixingCorrectionRequest{Rates: map[string]map[string]float64{"USD": {"INR": -1}}, Reason: "typo"}, http.StatusBadRequest},
+		{"Unsupported currency", "2025-09-01", domain.FixingCorrectionRequest{Rates: map[string]map[string]float64{"USD": {"XYZ": 1}}, Reason: "typo"}, http.StatusBadRequest},
+		{"No fixing for date", "2025-09-02", domain.FixingCorrectionRequest{Rates: map[string]map[string]float64{"USD": {"INR": 83}}, Reason: "typo"}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := overrideRequest(router, "POST", "/admin/fixings/"+tt.date+"/corrections", "alice", tt.req)
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestMissedFixingCapturedByOperator(t *testing.T) {
+	router, _, apiRepo, _ := newFixingRouter(t)
+	apiRepo.set("USD", "INR", 84)
+
+	capture := domain.FixingCaptureRequest{Reason: "provider was down at 16:00"}
+	w := overrideRequest(router, "POST", "/admin/fixings/2025-09-01/capture", "", capture)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "an actor is required")
+	w = overrideRequest(router, "POST", "/admin/fixings/2025-09-01/capture", "alice", domain.FixingCaptureRequest{})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "a reason is required")
+	w = overrideRequest(router, "POST", "/admin/fixings/2025-09-02/capture", "alice", capture)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "today's fixing is not due yet")
+
+	w = overrideRequest(router, "POST", "/admin/fixings/2025-09-01/capture", "alice", capture)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var fixing domain.Fixing
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fixing))
+	assert.Equal(t, 1, fixing.Version)
+	assert.Equal(t, "alice", fixing.CreatedBy)
+	assert.Equal(t, capture.Reason, fixing.Reason)
+	assert.Equal(t, 84.0, fixing.Rates["USD"]["INR"])
+	assert.True(t, fixing.FixedAt.Equal(time.Date(2025, 9, 1, 14, 0, 0, 0, time.UTC)))
+	assert.True(t, fixing.CreatedAt.Equal(time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC)))
+
+	w = overrideRequest(router, "POST", "/admin/fixings/2025-09-01/capture", "alice", capture)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestFixingSchedulerFollowsClock(t *testing.T) {
+	_, exchangeService, _, clk := newFixingRouter(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		exchangeService.StartFixingScheduler(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	require.Eventually(t, func() bool { return clk.Waiters() == 1 }, time.Second, time.Millisecond)
+	_, err := exchangeService.GetFixing("2025-09-02", 0)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	clk.Set(time.Date(2025, 9, 2, 14, 0, 0, 0, time.UTC))
+	require.Eventually(t, func() bool {
+		_, err := exchangeService.GetFixing("2025-09-02", 0)
+		return err == nil
+	}, time.Second, time.Millisecond)
+
+	fixing, err := exchangeService.GetFixing("2025-09-02", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "scheduler", fixing.CreatedBy)
+	assert.True(t, fixing.CreatedAt.Equal(time.Date(2025, 9, 2, 14, 0, 0, 0, time.UTC)))
+}
+
+func TestFixingAdminRoutesNeedFixings(t *testing.T) {
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger)
+	router := api.NewRouter(exchangeService, logger, api.WithAdminToken(testAdminToken))
+
+	w := overrideRequest(router, "GET", "/admin/fixings/2025-09-01/history", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "404 page not found", w.Body.String(), "the route is not registered")
+	w = overrideRequest(router, "POST", "/admin/fixings/2025-09-01/capture", "alice", domain.FixingCaptureRequest{Reason: "missed"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
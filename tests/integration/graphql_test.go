@@ -0,0 +1,214 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type graphqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func newGraphQLRouter(apiRepo *stubRatesRepository, opts ...api.Option) *gin.Engine {
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger)
+	return api.NewRouter(exchangeService, logger, opts...)
+}
+
+func postGraphQL(t *testing.T, router *gin.Engine, query string, variables map[string]interface{}) graphqlResponse {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp graphqlResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestGraphQLCombinedQuery(t *testing.T) {
+	apiRepo := newStubRatesRepository()
+	router := newGraphQLRouter(apiRepo)
+
+	start := time.Now().AddDate(0, 0, -2).Format("2006-01-02")
+	end := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	resp := postGraphQL(t, router, `query($start: String!, $end: String!) {
+		currencies
+		latest(base: "USD") { baseCurrency rates(symbols: ["INR", "EUR"]) { currency rate } }
+		inr: convert(from: "USD", to: "INR", amount: 100) { amount rate }
+		gbp: convert(from: "USD", to: "GBP", amount: 10) { amount rate }
+		same: convert(from: "USD", to: "USD", amount: 5) { amount }
+		historical(from: "USD", to: "INR", startDate: $start, endDate: $end) { rates { date rate } }
+	}`, map[string]interface{}{"start": start, "end": end})
+	require.Empty(t, resp.Errors)
+
+	assert.JSONEq(t, `["EUR","GBP","INR","JPY","USD"]`, string(resp.Data["currencies"]))
+	assert.JSONEq(t, `{"baseCurrency":"USD","rates":[{"currency":"EUR","rate":0.85},{"currency":"INR","rate":83.25}]}`, string(resp.Data["latest"]))
+	assert.JSONEq(t, `{"amount":8325,"rate":83.25}`, string(resp.Data["inr"]))
+	assert.JSONEq(t, `{"amount":7.3,"rate":0.73}`, string(resp.Data["gbp"]))
+	assert.JSONEq(t, `{"amount":5}`, string(resp.Data["same"]))
+
+	var historical struct {
+		Rates []struct {
+			Date string  `json:"date"`
+			Rate float64 `json:"rate"`
+		} `json:"rates"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Data["historical"], &historical))
+	require.Len(t, historical.Rates, 2)
+	assert.Equal(t, start, historical.Rates[0].Date)
+	assert.Equal(t, end, historical.Rates[1].Date)
+
+	assert.Equal(t, 1, apiRepo.callCount("GetAllLatestRates"), "latest conversions should share one rate table")
+	assert.Equal(t, 0, apiRepo.callCount("GetLatestRate"))
+}
+
+func TestGraphQLResolverErrors(t *testing.T) {
+	router := newGraphQLRouter(newStubRatesRepository())
+
+	resp := postGraphQL(t, router, `{ convert(from: "USD", to: "XYZ") { amount } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "BAD_USER_INPUT", resp.Errors[0].Extensions["code"])
+
+	resp = postGraphQL(t, router, `{ latest { unknownField } }`, nil)
+	require.NotEmpty(t, resp.Errors)
+	assert.Nil(t, resp.Data)
+
+	req, _ := http.NewRequest("POST", "/graphql", bytes.NewBufferString(`{"query": ""}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGraphQLDepthAndComplexityLimits(t *testing.T) {
+	apiRepo := newStubRatesRepository()
+	router := newGraphQLRouter(apiRepo, api.WithGraphQLLimits(2, 5))
+
+	resp := postGraphQL(t, router, `{ latest { rates { currency } } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "QUERY_TOO_DEEP", resp.Errors[0].Extensions["code"])
+
+	resp = postGraphQL(t, router, `fragment deep on LatestRates { rates { rate } }
+		{ latest { ...deep } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "QUERY_TOO_DEEP", resp.Errors[0].Extensions["code"])
+
+	resp = postGraphQL(t, router, `{
+		a: convert(from: "USD", to: "INR") { amount }
+		b: convert(from: "USD", to: "EUR") { amount }
+		c: convert(from: "USD", to: "GBP") { amount }
+	}`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "QUERY_TOO_COMPLEX", resp.Errors[0].Extensions["code"])
+
+	start := time.Now().AddDate(0, 0, -9).Format("2006-01-02")
+	end := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	resp = postGraphQL(t, router, `query($start: String!, $end: String!) {
+		historical(from: "USD", to: "INR", startDate: $start, endDate: $end) { fromCurrency }
+	}`, map[string]interface{}{"start": start, "end": end})
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "QUERY_TOO_COMPLEX", resp.Errors[0].Extensions["code"])
+
+	assert.Equal(t, 0, apiRepo.callCount("GetAllLatestRates"), "rejected queries must not reach the service")
+
+	resp = postGraphQL(t, router, `{ __schema { queryType { fields { name args { name type { name ofType { name } } } } } } }`, nil)
+	assert.Empty(t, resp.Errors)
+
+	resp = postGraphQL(t, router, `{ convert(from: "USD", to: "INR") { amount } }`, nil)
+	assert.Empty(t, resp.Errors)
+}
+
+func TestGraphQLConversionsCarrySourceAndConsensus(t *testing.T) {
+	router, _ := newOverrideRouter(t)
+	today := time.Now().Format("2006-01-02")
+	w := overrideRequest(router, "POST", "/admin/overrides", "alice", domain.RateOverrideRequest{
+		Pair: "USDINR", Rate: 80, StartDate: today, EndDate: today, Reason: "contract rate",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	resp := postGraphQL(t, router, `{
+		inr: convert(from: "USD", to: "INR") { rate source }
+		eur: convert(from: "USD", to: "EUR") { rate source }
+	}`, nil)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"rate":80,"source":"override"}`, string(resp.Data["inr"]))
+	assert.JSONEq(t, `{"rate":0.85,"source":null}`, string(resp.Data["eur"]))
+
+	logger := zap.NewNop()
+	apiRepo := newStubRatesRepository()
+	consensus, err := repository.NewConsensusRepository([]repository.ConsensusSource{
+		{Name: "a", Repo: apiRepo, Weight: 1},
+		{Name: "b", Repo: newStubRatesRepository(), Weight: 1},
+	}, repository.ConsensusOptions{Method: repository.ConsensusMedian, MaxDeviation: 0.02, MinSources: 1}, logger)
+	require.NoError(t, err)
+	router = api.NewRouter(service.NewExchangeService(repository.NewCacheRepository(), consensus, logger), logger)
+
+	resp = postGraphQL(t, router, `{
+		convert(from: "USD", to: "INR", amount: 2) { amount consensus { method sources queried rejected } }
+	}`, nil)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"amount":166.5,"consensus":{"method":"median","sources":2,"queried":2,"rejected":[]}}`, string(resp.Data["convert"]))
+	assert.Equal(t, 1, apiRepo.callCount("GetLatestRate"), "consensus comes from a single-pair lookup")
+}
+
+func TestGraphQLChargesQueryComplexity(t *testing.T) {
+	apiRepo := newStubRatesRepository()
+	limiter := service.NewRateLimiter(repository.NewCacheRepository())
+	router := newGraphQLRouter(apiRepo,
+		api.WithRateLimit(limiter, 100, time.Minute, nil),
+		api.WithGraphQLLimits(8, 50))
+
+	post := func(query string, variables map[string]interface{}) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+		require.NoError(t, err)
+		req, _ := http.NewRequest("POST", "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	start := time.Now().AddDate(0, 0, -4).Format("2006-01-02")
+	end := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	w := post(`query($start: String!, $end: String!) {
+		historical(from: "USD", to: "INR", startDate: $start, endDate: $end) { fromCurrency }
+	}`, map[string]interface{}{"start": start, "end": end})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "95", w.Header().Get("RateLimit-Remaining"), "four days plus one field")
+
+	began := time.Now()
+	w = post(`{
+		a: historical(from: "USD", to: "INR", startDate: "0001-01-01", endDate: "9999-12-31") { fromCurrency }
+		b: historical(from: "USD", to: "INR", startDate: "0001-01-01", endDate: "9999-12-31") { fromCurrency }
+		c: historical(from: "USD", to: "INR", startDate: "0001-01-01", endDate: "9999-12-31") { fromCurrency }
+	}`, nil)
+	assert.Less(t, time.Since(began), time.Second)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "QUERY_TOO_COMPLEX")
+	assert.Equal(t, "45", w.Header().Get("RateLimit-Remaining"), "charged up to the complexity limit")
+}
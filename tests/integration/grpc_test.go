@@ -0,0 +1,274 @@
+package integration
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/grpcserver"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+	exchangev1 "exchange-rate-service/proto/exchange/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type grpcFixture struct {
+	client          exchangev1.ExchangeServiceClient
+	apiRepo         *stubRatesRepository
+	exchangeService *service.ExchangeService
+}
+
+func newGRPCFixture(t *testing.T, apiKeys *service.APIKeyService) *grpcFixture {
+	t.Helper()
+
+	apiRepo := newStubRatesRepository()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, zap.NewNop())
+
+	f := serveGRPC(t, exchangeService, apiKeys, grpcserver.RateLimit{})
+	f.apiRepo = apiRepo
+	return f
+}
+
+func serveGRPC(t *testing.T, exchangeService *service.ExchangeService, apiKeys *service.APIKeyService, rateLimit grpcserver.RateLimit) *grpcFixture {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpcserver.New(exchangeService, zap.NewNop(), apiKeys, rateLimit)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &grpcFixture{
+		client:          exchangev1.NewExchangeServiceClient(conn),
+		exchangeService: exchangeService,
+	}
+}
+
+func TestGRPCConvert(t *testing.T) {
+	f := newGRPCFixture(t, nil)
+	ctx := context.Background()
+
+	resp, err := f.client.Convert(ctx, &exchangev1.ConvertRequest{From: "USD", To: "INR", Amount: 100})
+	require.NoError(t, err)
+	assert.Equal(t, 8325.0, resp.GetAmount())
+	assert.Equal(t, 83.25, resp.GetRate())
+
+	_, err = f.client.Convert(ctx, &exchangev1.ConvertRequest{From: "USD", To: "XYZ"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = f.client.Convert(ctx, &exchangev1.ConvertRequest{From: "USD"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = f.client.Convert(ctx, &exchangev1.ConvertRequest{From: "USD", To: "INR", Date: "not-a-date"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCBatchConvert(t *testing.T) {
+	f := newGRPCFixture(t, nil)
+
+	resp, err := f.client.BatchConvert(context.Background(), &exchangev1.BatchConvertRequest{
+		Conversions: []*exchangev1.ConvertRequest{
+			{From: "USD", To: "INR", Amount: 2},
+			{From: "USD", To: "XYZ"},
+			{From: "EUR", To: "GBP", Amount: 10},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetResults(), 3)
+
+	assert.Equal(t, 166.5, resp.GetResults()[0].GetResult().GetAmount())
+	assert.Equal(t, "INVALID_ARGUMENT", resp.GetResults()[1].GetError().GetCode())
+	assert.InDelta(t, 8.6, resp.GetResults()[2].GetResult().GetAmount(), 1e-9)
+
+	_, err = f.client.BatchConvert(context.Background(), &exchangev1.BatchConvertRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCRatesAndCurrencies(t *testing.T) {
+	f := newGRPCFixture(t, nil)
+	ctx := context.Background()
+
+	latest, err := f.client.GetLatestRates(ctx, &exchangev1.GetLatestRatesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "USD", latest.GetBaseCurrency())
+	assert.Equal(t, 83.25, latest.GetRates()["INR"])
+
+	_, err = f.client.GetLatestRates(ctx, &exchangev1.GetLatestRatesRequest{BaseCurrency: "XYZ"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	start := time.Now().AddDate(0, 0, -3).Format("2006-01-02")
+	end := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	historical, err := f.client.GetHistoricalRates(ctx, &exchangev1.GetHistoricalRatesRequest{
+		From: "USD", To: "INR", StartDate: start, EndDate: end,
+	})
+	require.NoError(t, err)
+	require.Len(t, historical.GetRates(), 3)
+	assert.Equal(t, start, historical.GetRates()[0].GetDate())
+	assert.Equal(t, end, historical.GetRates()[2].GetDate())
+
+	currencies, err := f.client.ListCurrencies(ctx, &exchangev1.ListCurrenciesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"EUR", "GBP", "INR", "JPY", "USD"}, currencies.GetCurrencies())
+}
+
+func TestGRPCWatchRates(t *testing.T) {
+	f := newGRPCFixture(t, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := f.client.WatchRates(ctx, &exchangev1.WatchRatesRequest{Pairs: []string{"USDINR"}})
+	require.NoError(t, err)
+
+	snapshot, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "SNAPSHOT", snapshot.GetType())
+	require.Len(t, snapshot.GetRates(), 1)
+	assert.Equal(t, 83.25, snapshot.GetRates()[0].GetRate())
+
+	f.apiRepo.set("USD", "INR", 85.0)
+	updaterCtx, stopUpdater := context.WithCancel(context.Background())
+	defer stopUpdater()
+	go f.exchangeService.StartRateUpdater(updaterCtx)
+
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE", update.GetType())
+	require.Len(t, update.GetRates(), 1)
+	assert.Equal(t, "USDINR", update.GetRates()[0].GetPair())
+	assert.Equal(t, 85.0, update.GetRates()[0].GetRate())
+
+	bad, err := f.client.WatchRates(ctx, &exchangev1.WatchRatesRequest{Pairs: []string{"USD"}})
+	require.NoError(t, err)
+	_, err = bad.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCRequiresAPIKey(t *testing.T) {
+	keyRepo, err := repository.NewAPIKeyRepository("")
+	require.NoError(t, err)
+	cacheRepo := repository.NewCacheRepository()
+	keys := service.NewAPIKeyService(keyRepo, newUsageStore(t, ""), service.NewRateLimiter(cacheRepo), zap.NewNop(), 60, 1000)
+	created, err := keys.Create(&domain.APIKeyRequest{Name: "internal-service"})
+	require.NoError(t, err)
+
+	f := newGRPCFixture(t, keys)
+
+	_, err = f.client.ListCurrencies(context.Background(), &exchangev1.ListCurrenciesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", created.Key)
+	_, err = f.client.ListCurrencies(ctx, &exchangev1.ListCurrenciesRequest{})
+	assert.NoError(t, err)
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+created.Key)
+	stream, err := f.client.WatchRates(ctx, &exchangev1.WatchRatesRequest{Pairs: []string{"USDINR"}})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.NoError(t, err)
+}
+
+func TestGRPCErrorsHideUpstreamDetails(t *testing.T) {
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), failingRatesRepository{}, logger)
+	f := serveGRPC(t, exchangeService, nil, grpcserver.RateLimit{})
+
+	_, err := f.client.Convert(context.Background(), &exchangev1.ConvertRequest{From: "USD", To: "INR"})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	assert.NotContains(t, status.Convert(err).Message(), "10.0.0.1")
+	assert.Equal(t, domain.ErrUpstreamUnavailable.Error(), status.Convert(err).Message())
+}
+
+func TestGRPCConversionsCarrySourceAndPricing(t *testing.T) {
+	logger := zap.NewNop()
+	markups, err := service.NewMarkupService(testMarkupProfiles, "retail", logger)
+	require.NoError(t, err)
+	overrideRepo, err := repository.NewOverrideRepository("")
+	require.NoError(t, err)
+	overrides := service.NewOverrideService(overrideRepo, logger)
+
+	today := time.Now().Format("2006-01-02")
+	_, err = overrides.Create(&domain.RateOverrideRequest{Pair: "USDEUR", Rate: 0.9, StartDate: today, EndDate: today, Reason: "contract"}, "alice")
+	require.NoError(t, err)
+
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger,
+		service.WithMarkups(markups), service.WithOverrides(overrides))
+	f := serveGRPC(t, exchangeService, nil, grpcserver.RateLimit{})
+
+	resp, err := f.client.Convert(context.Background(), &exchangev1.ConvertRequest{From: "USD", To: "INR", Amount: 100})
+	require.NoError(t, err)
+	require.NotNil(t, resp.GetPricing())
+	assert.Equal(t, "retail", resp.GetPricing().GetProfile())
+	assert.InDelta(t, 82.4175, resp.GetPricing().GetBidRate(), 1e-9)
+	assert.InDelta(t, 83.25, resp.GetPricing().GetFee(), 1e-9)
+	assert.Empty(t, resp.GetSource())
+
+	batch, err := f.client.BatchConvert(context.Background(), &exchangev1.BatchConvertRequest{
+		Conversions: []*exchangev1.ConvertRequest{{From: "USD", To: "EUR", Amount: 1}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.SourceOverride, batch.GetResults()[0].GetResult().GetSource())
+}
+
+func TestGRPCRateLimitsByRouteCost(t *testing.T) {
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), zap.NewNop())
+	f := serveGRPC(t, exchangeService, nil, grpcserver.RateLimit{
+		Limiter: service.NewRateLimiter(repository.NewCacheRepository()),
+		Limit:   5,
+		Window:  time.Minute,
+		Costs:   map[string]int{"/api/v1/latest": 2},
+	})
+	ctx := context.Background()
+
+	start := time.Now().AddDate(0, 0, -3).Format("2006-01-02")
+	end := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	_, err := f.client.GetHistoricalRates(ctx, &exchangev1.GetHistoricalRatesRequest{From: "USD", To: "INR", StartDate: start, EndDate: end})
+	require.NoError(t, err, "three days cost three tokens")
+
+	_, err = f.client.GetLatestRates(ctx, &exchangev1.GetLatestRatesRequest{BaseCurrency: "USD"})
+	require.NoError(t, err, "latest is weighted two")
+
+	var header metadata.MD
+	_, err = f.client.ListCurrencies(ctx, &exchangev1.ListCurrenciesRequest{}, grpc.Header(&header))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.NotEmpty(t, header.Get("retry-after"))
+}
+
+func TestGRPCChargesAPIKeysByRouteCost(t *testing.T) {
+	keyRepo, err := repository.NewAPIKeyRepository("")
+	require.NoError(t, err)
+	cacheRepo := repository.NewCacheRepository()
+	keys := service.NewAPIKeyService(keyRepo, newUsageStore(t, ""), service.NewRateLimiter(cacheRepo), zap.NewNop(), 5, 1000)
+	created, err := keys.Create(&domain.APIKeyRequest{Name: "internal-service"})
+	require.NoError(t, err)
+
+	f := newGRPCFixture(t, keys)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", created.Key)
+
+	start := time.Now().AddDate(0, 0, -5).Format("2006-01-02")
+	end := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	_, err = f.client.GetHistoricalRates(ctx, &exchangev1.GetHistoricalRatesRequest{From: "USD", To: "INR", StartDate: start, EndDate: end})
+	require.NoError(t, err)
+
+	_, err = f.client.ListCurrencies(ctx, &exchangev1.ListCurrenciesRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "five days used the whole minute's budget")
+}
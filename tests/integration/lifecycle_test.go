@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/lifecycle"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap"
+)
+
+func TestLifecycleStopsEveryWorker(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	logger := zap.NewNop()
+	stub := newStubRatesRepository()
+	cacheRepo := repository.NewCacheRepository()
+	exchangeService := service.NewExchangeService(cacheRepo, stub, logger)
+	webhookRepo, err := repository.NewWebhookRepository("", 0)
+	require.NoError(t, err)
+	webhookService := service.NewWebhookService(webhookRepo, logger, 1, time.Millisecond)
+	rateUpdates, unsubscribe := exchangeService.SubscribeUpdates(16)
+	defer unsubscribe()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := &http.Server{Handler: api.NewRouter(exchangeService, logger)}
+
+	manager := lifecycle.NewManager(logger)
+	manager.Go("cache janitor", func(ctx context.Context) {
+		cacheRepo.StartJanitor(ctx, time.Millisecond)
+	})
+	manager.Go("webhooks", func(ctx context.Context) {
+		webhookService.Run(ctx, rateUpdates)
+		webhookService.Wait()
+	})
+	manager.Go("rate updater", exchangeService.StartRateUpdater)
+	manager.Go("fixing scheduler", exchangeService.StartFixingScheduler)
+	manager.Add(lifecycle.Worker{
+		Name: "HTTP server",
+		Run: func(ctx context.Context) error {
+			if err := srv.Serve(lis); err != http.ErrServerClosed {
+				return err
+			}
+			return nil
+		},
+		Stop: srv.Shutdown,
+	})
+	manager.Start(context.Background())
+
+	require.Eventually(t, func() bool { return stub.callCount("GetAllLatestRates") > 0 }, time.Second, time.Millisecond)
+	client := &http.Client{Transport: &http.Transport{}}
+	resp, err := client.Get("http://" + lis.Addr().String() + "/health")
+	require.NoError(t, err)
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	client.CloseIdleConnections()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, manager.Stop(ctx))
+
+	_, err = client.Get("http://" + lis.Addr().String() + "/health")
+	assert.Error(t, err, "the server no longer accepts connections")
+}
@@ -0,0 +1,129 @@
+package integration
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/grpcserver"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/requestid"
+	"exchange-rate-service/internal/service"
+	exchangev1 "exchange-rate-service/proto/exchange/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func requestIDs(entries []observer.LoggedEntry) map[string]string {
+	ids := make(map[string]string)
+	for _, entry := range entries {
+		if id, ok := entry.ContextMap()["request_id"].(string); ok {
+			ids[entry.Message] = id
+		}
+	}
+	return ids
+}
+
+func TestRequestLogsAreCorrelated(t *testing.T) {
+	var mu sync.Mutex
+	var upstreamIDs []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		upstreamIDs = append(upstreamIDs, r.Header.Get(requestid.Header))
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+	apiRepo := repository.NewExchangeAPIRepository("", upstream.URL, newSnapshotRepository(t), logger)
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger)
+	router := api.NewRouter(exchangeService, logger)
+
+	w := errorRequest(router, "/api/v1/convert?from=USD&to=INR", map[string]string{requestid.Header: "corr-123"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "corr-123", w.Header().Get(requestid.Header))
+
+	ids := requestIDs(logs.All())
+	assert.Equal(t, "corr-123", ids["Rate cache miss"])
+	assert.Equal(t, "corr-123", ids["Upstream rates unavailable, using fallback rates"])
+	assert.Equal(t, "corr-123", ids["HTTP Request"])
+
+	mu.Lock()
+	assert.Equal(t, []string{"corr-123"}, upstreamIDs)
+	mu.Unlock()
+}
+
+func TestHandlerErrorLogsCarryRequestID(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(core)
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), failingRatesRepository{}, logger)
+	router := api.NewRouter(exchangeService, logger)
+
+	w := errorRequest(router, "/api/v1/convert?from=USD&to=INR", nil)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	generated := w.Header().Get(requestid.Header)
+	require.NotEmpty(t, generated)
+
+	failures := logs.FilterMessage("Conversion failed").All()
+	require.Len(t, failures, 1)
+	assert.Equal(t, generated, failures[0].ContextMap()["request_id"])
+	assert.Equal(t, generated, requestIDs(logs.All())["HTTP Request"])
+}
+
+func TestGRPCRequestIDPropagation(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(core)
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger)
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpcserver.New(exchangeService, logger, nil, grpcserver.RateLimit{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := exchangev1.NewExchangeServiceClient(conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "grpc-abc")
+	var header metadata.MD
+	_, err = client.Convert(ctx, &exchangev1.ConvertRequest{From: "USD", To: "INR"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grpc-abc"}, header.Get("x-request-id"))
+
+	calls := logs.FilterMessage("gRPC Request").All()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "grpc-abc", calls[0].ContextMap()["request_id"])
+	assert.Equal(t, "OK", calls[0].ContextMap()["code"])
+
+	_, err = client.ListCurrencies(context.Background(), &exchangev1.ListCurrenciesRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.True(t, requestid.Valid(first(header.Get("x-request-id"))))
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
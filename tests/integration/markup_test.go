@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var testMarkupProfiles = []domain.MarkupProfile{
+	{
+		Name:    "retail",
+		Default: domain.Markup{Percent: 1},
+		Rules:   []domain.MarkupRule{{Pairs: []string{"USDEUR"}, Markup: domain.Markup{BasisPoints: 50}}},
+	},
+	{Name: "institutional", Default: domain.Markup{BasisPoints: 10}},
+}
+
+func newMarkupRouter(t *testing.T, requireKeys bool) *gin.Engine {
+	t.Helper()
+
+	logger := zap.NewNop()
+	markups, err := service.NewMarkupService(testMarkupProfiles, "retail", logger)
+	require.NoError(t, err)
+
+	cacheRepo := repository.NewCacheRepository()
+	exchangeService := service.NewExchangeService(cacheRepo, newStubRatesRepository(), logger, service.WithMarkups(markups))
+
+	keyRepo, err := repository.NewAPIKeyRepository("")
+	require.NoError(t, err)
+	keyService := service.NewAPIKeyService(keyRepo, newUsageStore(t, ""), service.NewRateLimiter(repository.NewCacheRepository()), logger, 60, 1000)
+
+	return api.NewRouter(exchangeService, logger,
+		api.WithAPIKeys(keyService, requireKeys),
+		api.WithAdminToken(testAdminToken),
+		api.WithMarkups(markups),
+	)
+}
+
+func TestConvertReturnsBidAskPricing(t *testing.T) {
+	router := newMarkupRouter(t, false)
+
+	w := keyRequest(router, "/api/v1/convert?from=USD&to=INR&amount=100", "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp domain.ConversionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Pricing)
+
+	assert.Equal(t, 83.25, resp.Rate, "rate stays the mid rate")
+	assert.Equal(t, "retail", resp.Pricing.Profile)
+	assert.Equal(t, 100.0, resp.Pricing.MarkupBps)
+	assert.InDelta(t, 82.4175, resp.Pricing.BidRate, 1e-9)
+	assert.InDelta(t, 84.0825, resp.Pricing.AskRate, 1e-9)
+	assert.InDelta(t, 8325.0, resp.Pricing.GrossAmount, 1e-9)
+	assert.InDelta(t, 8241.75, resp.Amount, 1e-9)
+	assert.InDelta(t, 83.25, resp.Pricing.Fee, 1e-9)
+}
+
+func TestLatestReturnsBidAskTables(t *testing.T) {
+	router := newMarkupRouter(t, false)
+
+	w := keyRequest(router, "/api/v1/latest?base=USD", "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp domain.LatestRatesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, "retail", resp.Profile)
+	assert.Equal(t, 83.25, resp.Rates["INR"])
+	assert.InDelta(t, 82.4175, resp.Bid["INR"], 1e-9)
+	assert.InDelta(t, 84.0825, resp.Ask["INR"], 1e-9)
+	assert.InDelta(t, 0.85*0.995, resp.Bid["EUR"], 1e-9, "pair rule wins over the default")
+	assert.InDelta(t, 0.85*1.005, resp.Ask["EUR"], 1e-9)
+}
+
+func TestAPIKeyMarkupProfile(t *testing.T) {
+	router := newMarkupRouter(t, true)
+
+	institutional := createAPIKey(t, router, domain.APIKeyRequest{Name: "fund", MarkupProfile: "institutional"})
+	assert.Equal(t, "institutional", institutional.MarkupProfile)
+	standard := createAPIKey(t, router, domain.APIKeyRequest{Name: "app"})
+
+	convert := func(key string) domain.ConversionResponse {
+		w := keyRequest(router, "/api/v1/convert?from=USD&to=INR&amount=100", "X-API-Key", key)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp domain.ConversionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.NotNil(t, resp.Pricing)
+		return resp
+	}
+
+	resp := convert(institutional.Key)
+	assert.Equal(t, "institutional", resp.Pricing.Profile)
+	assert.InDelta(t, 8316.675, resp.Amount, 1e-9)
+
+	resp = convert(standard.Key)
+	assert.Equal(t, "retail", resp.Pricing.Profile)
+
+	w := adminRequest(router, "PUT", "/admin/keys/"+standard.ID, domain.APIKeyRequest{MarkupProfile: "institutional"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "institutional", convert(standard.Key).Pricing.Profile)
+}
+
+func TestAdminListsMarkupProfiles(t *testing.T) {
+	router := newMarkupRouter(t, false)
+
+	w := adminRequest(router, "GET", "/admin/markups", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Profiles []domain.MarkupProfile `json:"profiles"`
+		Default  string                 `json:"default"`
+		Count    int                    `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "retail", resp.Default)
+	assert.Equal(t, 2, resp.Count)
+	assert.Equal(t, "institutional", resp.Profiles[0].Name)
+
+	w = adminRequest(router, "GET", "/admin/markups/retail", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = adminRequest(router, "GET", "/admin/markups/missing", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,192 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/api/openapi"
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newSpecRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger)
+	quoteRepo, err := repository.NewQuoteRepository("")
+	require.NoError(t, err)
+	quoteService := service.NewQuoteService(exchangeService, quoteRepo, clock.System{}, time.Minute, logger)
+
+	return api.NewRouter(exchangeService, logger, api.WithQuotes(quoteService))
+}
+
+func TestOpenAPIDocumentIsServed(t *testing.T) {
+	router := newSpecRouter(t)
+
+	req, _ := http.NewRequest("GET", "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	doc, err := openapi3.NewLoader().LoadFromData(w.Body.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+	assert.NotNil(t, doc.Components.Schemas["ErrorResponse"])
+
+	req, _ = http.NewRequest("GET", "/docs", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/openapi.json")
+	assert.NotContains(t, w.Body.String(), "{{ASSETS}}")
+
+	req, _ = http.NewRequest("GET", "/docs/assets/swagger-ui-bundle.js", nil)
+	asset := httptest.NewRecorder()
+	router.ServeHTTP(asset, req)
+	if openapi.SwaggerAssetsBundled() {
+		assert.Contains(t, w.Body.String(), "/docs/assets/swagger-ui-bundle.js")
+		assert.NotContains(t, w.Body.String(), "unpkg.com")
+		assert.Equal(t, http.StatusOK, asset.Code)
+	} else {
+		assert.Contains(t, w.Body.String(), "swagger-ui-dist@"+openapi.SwaggerUIVersion+"/", "Swagger UI is pinned to an exact release")
+		assert.Equal(t, http.StatusNotFound, asset.Code)
+	}
+
+	req, _ = http.NewRequest("GET", "/docs/assets/README.md", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code, "only Swagger UI files are served")
+}
+
+func TestOpenAPIDocumentMatchesRoutes(t *testing.T) {
+	router := newSpecRouter(t)
+	doc := openapi.MustLoad()
+
+	ginParam := regexp.MustCompile(`:([A-Za-z_]+)`)
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1") && route.Path != "/convert" {
+			continue
+		}
+		path := ginParam.ReplaceAllString(route.Path, "{$1}")
+		registered[route.Method+" "+path] = true
+
+		item := doc.Paths.Find(path)
+		require.NotNil(t, item, "route %s %s is missing from the OpenAPI document", route.Method, path)
+		assert.NotNil(t, item.GetOperation(route.Method), "operation %s %s is missing from the OpenAPI document", route.Method, path)
+	}
+
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			assert.True(t, registered[method+" "+path], "documented operation %s %s has no route", method, path)
+		}
+	}
+}
+
+func TestOpenAPIRequestValidation(t *testing.T) {
+	router := newSpecRouter(t)
+
+	tests := []struct {
+		name    string
+		method  string
+		url     string
+		body    string
+		message string
+	}{
+		{"Missing to", "GET", "/api/v1/convert?from=USD", "", "parameter to"},
+		{"Malformed currency", "GET", "/api/v1/convert?from=US&to=INR", "", "parameter from"},
+		{"Non-numeric amount", "GET", "/api/v1/convert?from=USD&to=INR&amount=abc", "", "parameter amount"},
+		{"Malformed date", "GET", "/api/v1/historical?from=USD&to=INR&start_date=01-08-2025&end_date=2025-08-02", "", "parameter start_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp domain.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, domain.CodeValidation, resp.Error)
+			assert.Contains(t, resp.Message, tt.message)
+		})
+	}
+}
+
+func TestOpenAPIResponsesConformToSpec(t *testing.T) {
+	router := newSpecRouter(t)
+	doc := openapi.MustLoad()
+	specRouter, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	tests := []struct {
+		method string
+		url    string
+		body   string
+	}{
+		{"GET", "/api/v1/convert?from=USD&to=INR&amount=100", ""},
+		{"GET", "/api/v1/convert?from=USD&to=XYZ", ""},
+		{"GET", "/api/v1/latest?base=EUR", ""},
+		{"GET", "/api/v1/historical?from=USD&to=INR&start_date=" + yesterday + "&end_date=" + yesterday, ""},
+		{"GET", "/api/v1/currencies", ""},
+		{"POST", "/api/v1/convert/batch", `{"conversions":[{"from":"USD","to":"INR","amount":100},{"from":"USD","to":"XYZ"}]}`},
+		{"POST", "/api/v1/quotes", `{"from":"USD","to":"INR","amount":100}`},
+		{"POST", "/api/v1/quotes", `{"from":"USD","to":"XYZ"}`},
+		{"GET", "/api/v1/quotes/missing", ""},
+		{"POST", "/api/v1/quotes/missing/execute", ""},
+		{"GET", "/api/v1/fixings/2025-09-01", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			route, pathParams, err := specRouter.FindRoute(req)
+			require.NoError(t, err)
+
+			input := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{
+					Request:    req,
+					PathParams: pathParams,
+					Route:      route,
+				},
+				Status: w.Code,
+				Header: w.Header(),
+				Body:   io.NopCloser(bytes.NewReader(w.Body.Bytes())),
+				Options: &openapi3filter.Options{
+					IncludeResponseStatus: true,
+				},
+			}
+			assert.NoError(t, openapi3filter.ValidateResponse(context.Background(), input), w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newOverrideRouter(t *testing.T) (*gin.Engine, *stubRatesRepository) {
+	t.Helper()
+
+	logger := zap.NewNop()
+	overrideRepo, err := repository.NewOverrideRepository("")
+	require.NoError(t, err)
+	overrideService := service.NewOverrideService(overrideRepo, logger)
+
+	apiRepo := newStubRatesRepository()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger, service.WithOverrides(overrideService))
+
+	return api.NewRouter(exchangeService, logger,
+		api.WithAdminToken(testAdminToken),
+		api.WithOverrides(overrideService),
+	), apiRepo
+}
+
+func overrideRequest(router *gin.Engine, method, url, actor string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+
+	req, _ := http.NewRequest(method, url, &buf)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	if actor != "" {
+		req.Header.Set("X-Admin-User", actor)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateOverridesApplyBeforeMarketRates(t *testing.T) {
+	router, apiRepo := newOverrideRouter(t)
+
+	today := time.Now().Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	twoDaysAgo := time.Now().AddDate(0, 0, -2).Format("2006-01-02")
+
+	assert.Equal(t, 83.25, convertedRate(t, router, "USD", "INR"))
+
+	w := overrideRequest(router, "POST", "/admin/overrides", "alice", domain.RateOverrideRequest{
+		Pair: "USDINR", Rate: 80, StartDate: yesterday, EndDate: today, Reason: "board-approved contract rate",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created domain.RateOverride
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "alice", created.CreatedBy)
+
+	w = keyRequest(router, "/api/v1/convert?from=USD&to=INR&amount=10", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var conversion domain.ConversionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &conversion))
+	assert.Equal(t, 800.0, conversion.Amount)
+	assert.Equal(t, domain.SourceOverride, conversion.Source)
+
+	calls := apiRepo.callCount("GetLatestRate")
+	convertedRate(t, router, "USD", "INR")
+	assert.Equal(t, calls, apiRepo.callCount("GetLatestRate"))
+
+	w = keyRequest(router, "/api/v1/convert?from=USD&to=EUR", "", "")
+	var market domain.ConversionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &market))
+	assert.Empty(t, market.Source)
+
+	w = keyRequest(router, "/api/v1/latest?base=USD", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var latest domain.LatestRatesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &latest))
+	assert.Equal(t, 80.0, latest.Rates["INR"])
+	assert.Equal(t, 0.85, latest.Rates["EUR"])
+	assert.Equal(t, map[string]string{"INR": domain.SourceOverride}, latest.Sources)
+
+	w = keyRequest(router, "/api/v1/historical?from=USD&to=INR&start_date="+twoDaysAgo+"&end_date="+yesterday, "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var historical domain.HistoricalRatesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &historical))
+	assert.Equal(t, 80.0, historical.Rates[yesterday].Rate)
+	assert.Equal(t, domain.SourceOverride, historical.Rates[yesterday].Source)
+	assert.Equal(t, 83.25, historical.Rates[twoDaysAgo].Rate)
+	assert.Empty(t, historical.Rates[twoDaysAgo].Source)
+
+	w = overrideRequest(router, "DELETE", "/admin/overrides/"+created.ID+"?reason=contract+ended", "bob", nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, 83.25, convertedRate(t, router, "USD", "INR"))
+}
+
+func TestRateOverrideAdminEndpoints(t *testing.T) {
+	router, _ := newOverrideRouter(t)
+	req := domain.RateOverrideRequest{Pair: "EURUSD", Rate: 1.1, StartDate: "2025-01-01", EndDate: "2025-06-30", Reason: "hedge"}
+
+	w := overrideRequest(router, "POST", "/admin/overrides", "", req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = keyRequest(router, "/admin/overrides", "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = overrideRequest(router, "POST", "/admin/overrides", "alice", req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created domain.RateOverride
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	w = overrideRequest(router, "POST", "/admin/overrides", "alice", req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req.Rate = 1.12
+	req.Reason = "hedge re-priced"
+	w = overrideRequest(router, "PUT", "/admin/overrides/"+created.ID, "bob", req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var updated domain.RateOverride
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, 1.12, updated.Rate)
+	assert.Equal(t, "alice", updated.CreatedBy)
+	assert.Equal(t, "bob", updated.UpdatedBy)
+
+	w = overrideRequest(router, "GET", "/admin/overrides?pair=eurusd", "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	w = overrideRequest(router, "GET", "/admin/overrides/missing", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = overrideRequest(router, "DELETE", "/admin/overrides/"+created.ID, "carol", nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = overrideRequest(router, "GET", "/admin/overrides/audit?override_id="+created.ID, "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var audit struct {
+		Audit []domain.OverrideAuditEntry `json:"audit"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &audit))
+	require.Len(t, audit.Audit, 3)
+	assert.Equal(t, []string{"created", "updated", "deleted"}, []string{audit.Audit[0].Action, audit.Audit[1].Action, audit.Audit[2].Action})
+	assert.Equal(t, []string{"alice", "bob", "carol"}, []string{audit.Audit[0].Actor, audit.Audit[1].Actor, audit.Audit[2].Actor})
+	assert.Equal(t, "hedge re-priced", audit.Audit[1].Reason)
+}
@@ -0,0 +1,237 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type quoteFixture struct {
+	router  *gin.Engine
+	apiRepo *stubRatesRepository
+	clock   *clock.Fake
+}
+
+func newQuoteFixture(t *testing.T, store domain.QuoteRepository) *quoteFixture {
+	t.Helper()
+
+	logger := zap.NewNop()
+	apiRepo := newStubRatesRepository()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger)
+	fake := clock.NewFake(time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC))
+
+	quoteService := service.NewQuoteService(exchangeService, store, fake, 15*time.Minute, logger)
+
+	return &quoteFixture{
+		router:  api.NewRouter(exchangeService, logger, api.WithQuotes(quoteService)),
+		apiRepo: apiRepo,
+		clock:   fake,
+	}
+}
+
+func (f *quoteFixture) post(url string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+
+	req, _ := http.NewRequest("POST", url, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func (f *quoteFixture) createQuote(t *testing.T, req domain.QuoteRequest) domain.Quote {
+	t.Helper()
+
+	w := f.post("/api/v1/quotes", req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var quote domain.Quote
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &quote))
+	return quote
+}
+
+func TestQuoteLocksRateUntilExecuted(t *testing.T) {
+	f := newQuoteFixture(t, newQuoteStore(t, ""))
+
+	quote := f.createQuote(t, domain.QuoteRequest{From: "USD", To: "INR", Amount: 100})
+	assert.NotEmpty(t, quote.ID)
+	assert.Equal(t, "USDINR", quote.Pair)
+	assert.Equal(t, 83.25, quote.Rate)
+	assert.Equal(t, 100.0, quote.Amount)
+	assert.Equal(t, 8325.0, quote.ConvertedAmount)
+	assert.Equal(t, f.clock.Now().Add(15*time.Minute), quote.ExpiresAt)
+
+	f.apiRepo.set("USD", "INR", 90)
+	f.clock.Advance(14*time.Minute + 59*time.Second)
+
+	w := f.post("/api/v1/quotes/"+quote.ID+"/execute", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var executed domain.Quote
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &executed))
+	assert.Equal(t, 83.25, executed.Rate)
+	assert.Equal(t, 8325.0, executed.ConvertedAmount)
+	require.NotNil(t, executed.ExecutedAt)
+	assert.Equal(t, f.clock.Now(), *executed.ExecutedAt)
+
+	w = f.post("/api/v1/quotes/"+quote.ID+"/execute", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var resp domain.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, domain.CodeConflict, resp.Error)
+
+	req, _ := http.NewRequest("GET", "/api/v1/quotes/"+quote.ID, nil)
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"executed_at"`)
+}
+
+func TestQuoteExpires(t *testing.T) {
+	f := newQuoteFixture(t, newQuoteStore(t, ""))
+	quote := f.createQuote(t, domain.QuoteRequest{From: "EUR", To: "GBP"})
+	assert.Equal(t, 1.0, quote.Amount)
+
+	f.clock.Advance(15 * time.Minute)
+
+	w := f.post("/api/v1/quotes/"+quote.ID+"/execute", nil)
+	assert.Equal(t, http.StatusGone, w.Code)
+	var resp domain.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, domain.CodeExpired, resp.Error)
+
+	w = f.post("/api/v1/quotes/unknown/execute", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestQuoteValidation(t *testing.T) {
+	f := newQuoteFixture(t, newQuoteStore(t, ""))
+
+	w := f.post("/api/v1/quotes", domain.QuoteRequest{From: "USD", To: "XYZ"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = f.post("/api/v1/quotes", map[string]interface{}{"from": "USD", "to": "INR", "amount": -5})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func newQuoteStore(t *testing.T, dir string) *repository.QuoteRepository {
+	t.Helper()
+
+	store, err := repository.NewQuoteRepository(dir)
+	require.NoError(t, err)
+	return store
+}
+
+func TestQuoteSurvivesRestart(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "quotes")
+	first := newQuoteFixture(t, newQuoteStore(t, dir))
+
+	quote := first.createQuote(t, domain.QuoteRequest{From: "USD", To: "EUR", Amount: 10})
+
+	second := newQuoteFixture(t, newQuoteStore(t, dir))
+	w := second.post("/api/v1/quotes/"+quote.ID+"/execute", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	third := newQuoteFixture(t, newQuoteStore(t, dir))
+	w = third.post("/api/v1/quotes/"+quote.ID+"/execute", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestQuoteExecutesOnce(t *testing.T) {
+	store := newQuoteStore(t, "")
+	f := newQuoteFixture(t, store)
+	quote := f.createQuote(t, domain.QuoteRequest{From: "USD", To: "INR", Amount: 10})
+
+	var wg sync.WaitGroup
+	var executed atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := store.Execute(quote.ID, quote.CreatedAt); err == nil {
+				executed.Add(1)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrConflict)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), executed.Load())
+}
+
+func TestQuoteExecutesOnceAcrossReplicas(t *testing.T) {
+	dir := t.TempDir()
+	replicas := []*repository.QuoteRepository{newQuoteStore(t, dir), newQuoteStore(t, dir)}
+	f := newQuoteFixture(t, replicas[0])
+	quote := f.createQuote(t, domain.QuoteRequest{From: "USD", To: "INR", Amount: 10})
+
+	seen, err := replicas[1].Get(quote.ID)
+	require.NoError(t, err)
+	assert.Equal(t, quote.Rate, seen.Rate)
+
+	var wg sync.WaitGroup
+	var executed atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(store *repository.QuoteRepository) {
+			defer wg.Done()
+			if _, err := store.Execute(quote.ID, quote.CreatedAt); err == nil {
+				executed.Add(1)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrConflict)
+			}
+		}(replicas[i%2])
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), executed.Load())
+
+	for _, store := range replicas {
+		got, err := store.Get(quote.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, got.ExecutedAt)
+	}
+}
+
+func TestQuoteStoreRejectsExpiredExecute(t *testing.T) {
+	store := newQuoteStore(t, t.TempDir())
+	f := newQuoteFixture(t, store)
+	quote := f.createQuote(t, domain.QuoteRequest{From: "USD", To: "INR"})
+
+	_, err := store.Execute(quote.ID, quote.ExpiresAt)
+	assert.ErrorIs(t, err, domain.ErrExpired)
+
+	got, err := store.Get(quote.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.ExecutedAt)
+}
+
+func TestQuotesPrunedAfterRetention(t *testing.T) {
+	store := newQuoteStore(t, "")
+	f := newQuoteFixture(t, store)
+	old := f.createQuote(t, domain.QuoteRequest{From: "USD", To: "INR"})
+
+	f.clock.Advance(25 * time.Hour)
+	f.createQuote(t, domain.QuoteRequest{From: "USD", To: "INR"})
+
+	_, err := store.Get(old.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,67 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRefreshKeepsLastGoodRateOnAnomaly(t *testing.T) {
+	logger := zap.NewNop()
+	cacheRepo := repository.NewCacheRepository()
+	apiRepo := newStubRatesRepository()
+	anomalyRepo, err := repository.NewAnomalyRepository("", 0)
+	require.NoError(t, err)
+	guard := service.NewRateGuard(anomalyRepo, clock.System{}, service.GuardOptions{InverseTolerance: 0.05}, logger)
+	exchangeService := service.NewExchangeService(cacheRepo, apiRepo, logger, service.WithRateGuard(guard))
+
+	router := api.NewRouter(exchangeService, logger,
+		api.WithAdminToken(testAdminToken),
+		api.WithCacheAdmin(service.NewCacheAdminService(cacheRepo, exchangeService, logger)),
+		api.WithRateGuard(guard),
+	)
+
+	_, err = exchangeService.RefreshRates(context.Background(), "")
+	require.NoError(t, err)
+
+	apiRepo.set("USD", "JPY", 0)
+	w := adminRequest(router, "POST", "/admin/cache/refresh?base=USD", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, 110.50, convertedRate(t, router, "USD", "JPY"))
+
+	w = keyRequest(router, "/api/v1/latest?base=USD", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var latest domain.LatestRatesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &latest))
+	assert.Equal(t, 110.50, latest.Rates["JPY"])
+
+	w = adminRequest(router, "GET", "/admin/anomalies?pair=USDJPY", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Anomalies []domain.RateAnomaly `json:"anomalies"`
+		Count     int                  `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, 1, resp.Count)
+	assert.Equal(t, 2, resp.Anomalies[0].Count, "the refreshed table and the pair lookup each saw the bad tick")
+	assert.Equal(t, domain.AnomalyNonPositive, resp.Anomalies[0].Check)
+	assert.Equal(t, 110.50, resp.Anomalies[0].LastGood)
+
+	w = overrideRequest(router, "POST", "/admin/anomalies/"+resp.Anomalies[0].ID+"/review", "alice", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = overrideRequest(router, "POST", "/admin/anomalies/missing/review", "alice", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
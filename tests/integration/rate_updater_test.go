@@ -0,0 +1,203 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var updaterStart = time.Date(2025, 9, 1, 10, 30, 0, 0, time.UTC)
+
+type updaterFixture struct {
+	exchangeService *service.ExchangeService
+	runs            *repository.UpdateRunRepository
+	clock           *clock.Fake
+	cancel          context.CancelFunc
+	done            chan struct{}
+}
+
+func newUpdaterFixture(t *testing.T, apiRepo domain.ExchangeRepository, logger *zap.Logger, lastRun time.Time, groups ...domain.UpdateGroup) *updaterFixture {
+	t.Helper()
+
+	schedules, err := service.ParseUpdateGroups(groups)
+	require.NoError(t, err)
+	runs, err := repository.NewUpdateRunRepository("")
+	require.NoError(t, err)
+	if !lastRun.IsZero() {
+		for _, group := range groups {
+			require.NoError(t, runs.SetLastRun(group.Name, lastRun))
+		}
+	}
+
+	f := &updaterFixture{
+		runs:  runs,
+		clock: clock.NewFake(updaterStart),
+		done:  make(chan struct{}),
+	}
+	f.exchangeService = service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger,
+		service.WithUpdater(service.UpdaterOptions{Schedules: schedules, Runs: runs, Clock: f.clock}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	go func() {
+		f.exchangeService.StartRateUpdater(ctx)
+		close(f.done)
+	}()
+	t.Cleanup(f.stop)
+
+	return f
+}
+
+func (f *updaterFixture) stop() {
+	f.cancel()
+	<-f.done
+}
+
+// waitIdle waits until every group is waiting for its next run.
+func (f *updaterFixture) waitIdle(t *testing.T, groups int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return f.clock.Waiters() == groups }, time.Second, time.Millisecond)
+}
+
+func (f *updaterFixture) waitLastRun(t *testing.T, group string, want time.Time) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		last, _ := f.runs.LastRun(group)
+		return last.Equal(want)
+	}, time.Second, time.Millisecond)
+}
+
+func TestRateUpdaterRunsOnScheduleAfterRestart(t *testing.T) {
+	stub := newStubRatesRepository()
+	hourly := domain.UpdateGroup{Name: "majors", Cron: "0 * * * *", Bases: []string{"USD", "EUR"}}
+	lastRun := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	f := newUpdaterFixture(t, stub, zap.NewNop(), lastRun, hourly)
+
+	f.waitIdle(t, 1)
+	assert.Zero(t, stub.callCount("GetAllLatestRates"), "a restart within the period does not refresh")
+
+	f.clock.Set(time.Date(2025, 9, 1, 11, 0, 0, 0, time.UTC))
+	f.waitLastRun(t, "majors", time.Date(2025, 9, 1, 11, 0, 0, 0, time.UTC))
+	assert.Equal(t, 2, stub.callCount("GetAllLatestRates"))
+}
+
+func TestRateUpdaterCatchesUpMissedRunsOnce(t *testing.T) {
+	stub := newStubRatesRepository()
+	hourly := domain.UpdateGroup{Name: "majors", Cron: "@hourly", Bases: []string{"USD"}}
+	f := newUpdaterFixture(t, stub, zap.NewNop(), updaterStart.Add(-48*time.Hour), hourly)
+
+	f.waitLastRun(t, "majors", updaterStart)
+	f.waitIdle(t, 1)
+	assert.Equal(t, 1, stub.callCount("GetAllLatestRates"), "48 missed runs are caught up with one")
+}
+
+func TestRateUpdaterRefreshesPairGroups(t *testing.T) {
+	stub := newStubRatesRepository()
+	stub.set("USD", "INR", 84)
+	pairs := domain.UpdateGroup{Name: "inr", Cron: "*/15 * * * *", Pairs: []string{"USDINR"}}
+	f := newUpdaterFixture(t, stub, zap.NewNop(), time.Time{}, pairs)
+
+	f.waitLastRun(t, "inr", updaterStart)
+	assert.Equal(t, 1, stub.callCount("GetLatestRate"))
+	assert.Zero(t, stub.callCount("GetAllLatestRates"))
+
+	conversion, err := f.exchangeService.ConvertCurrency(context.Background(), &domain.ConversionRequest{From: "USD", To: "INR", Amount: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 84.0, conversion.Rate)
+	assert.Equal(t, 1, stub.callCount("GetLatestRate"), "the conversion is served from the refreshed cache")
+}
+
+// blockingRatesRepository holds table fetches until released.
+type blockingRatesRepository struct {
+	*stubRatesRepository
+	release chan struct{}
+	once    sync.Once
+}
+
+func (r *blockingRatesRepository) GetAllLatestRates(ctx context.Context, baseCurrency string) (map[string]float64, error) {
+	<-r.release
+	return r.stubRatesRepository.GetAllLatestRates(ctx, baseCurrency)
+}
+
+func (r *blockingRatesRepository) unblock() {
+	r.once.Do(func() { close(r.release) })
+}
+
+func TestRateUpdaterSkipsRunsWhileOneIsInProgress(t *testing.T) {
+	stub := newStubRatesRepository()
+	blocking := &blockingRatesRepository{stubRatesRepository: stub, release: make(chan struct{})}
+	core, logs := observer.New(zapcore.WarnLevel)
+	hourly := domain.UpdateGroup{Name: "majors", Cron: "@hourly", Bases: []string{"USD"}}
+	f := newUpdaterFixture(t, blocking, zap.New(core), time.Time{}, hourly)
+	t.Cleanup(blocking.unblock)
+
+	f.waitIdle(t, 1)
+	f.clock.Set(time.Date(2025, 9, 1, 11, 0, 0, 0, time.UTC))
+	f.waitIdle(t, 1)
+
+	skipped := logs.FilterMessage("Skipping scheduled refresh, previous run still in progress").All()
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "majors", skipped[0].ContextMap()["group"])
+
+	blocking.unblock()
+	f.waitLastRun(t, "majors", updaterStart)
+	assert.Equal(t, 1, stub.callCount("GetAllLatestRates"), "only the first run fetched")
+}
+
+func TestRateUpdaterReloadsSchedule(t *testing.T) {
+	stub := newStubRatesRepository()
+	hourly := domain.UpdateGroup{Name: "majors", Cron: "@hourly", Bases: []string{"USD"}}
+	f := newUpdaterFixture(t, stub, zap.NewNop(), updaterStart.Add(-10*time.Minute), hourly)
+	f.waitIdle(t, 1)
+
+	schedules, err := service.ParseUpdateGroups([]domain.UpdateGroup{
+		{Name: "inr", Cron: "*/15 * * * *", Pairs: []string{"USDINR"}},
+	})
+	require.NoError(t, err)
+	f.exchangeService.SetUpdateSchedules(schedules)
+
+	f.waitLastRun(t, "inr", updaterStart)
+	assert.Equal(t, 1, stub.callCount("GetLatestRate"), "the new group runs as after a restart")
+
+	// The stopped group's timer stays registered with the fake clock.
+	f.waitIdle(t, 2)
+	f.clock.Set(time.Date(2025, 9, 1, 11, 0, 0, 0, time.UTC))
+	f.waitLastRun(t, "inr", time.Date(2025, 9, 1, 10, 45, 0, 0, time.UTC))
+	assert.Zero(t, stub.callCount("GetAllLatestRates"), "the old group no longer runs")
+
+	status := f.exchangeService.UpdateStatus()
+	require.Len(t, status, 1)
+	assert.Equal(t, "inr", status[0].Name)
+}
+
+func TestCacheTTLsApplyToNewEntries(t *testing.T) {
+	cacheRepo := repository.NewCacheRepository()
+	exchangeService := service.NewExchangeService(cacheRepo, newStubRatesRepository(), zap.NewNop(),
+		service.WithCacheTTLs(service.CacheTTLs{Latest: 10 * time.Minute}))
+	ctx := context.Background()
+
+	_, err := exchangeService.GetLatestRates(ctx, "USD")
+	require.NoError(t, err)
+	entry, err := cacheRepo.Entry("latest_rates_USD")
+	require.NoError(t, err)
+	assert.InDelta(t, 600, entry.TTLSeconds, 1)
+
+	exchangeService.SetCacheTTLs(service.CacheTTLs{Latest: time.Minute})
+	_, err = exchangeService.GetLatestRates(ctx, "EUR")
+	require.NoError(t, err)
+	entry, err = cacheRepo.Entry("latest_rates_EUR")
+	require.NoError(t, err)
+	assert.InDelta(t, 60, entry.TTLSeconds, 1)
+}
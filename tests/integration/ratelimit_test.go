@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newRateLimitedRouter(limit int, costs map[string]int) *gin.Engine {
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger)
+	limiter := service.NewRateLimiter(repository.NewCacheRepository())
+
+	return api.NewRouter(exchangeService, logger, api.WithRateLimit(limiter, limit, time.Minute, costs))
+}
+
+func requestFrom(router *gin.Engine, url, ip string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", url, nil)
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitHeadersAndRejection(t *testing.T) {
+	router := newRateLimitedRouter(2, nil)
+
+	w := requestFrom(router, "/api/v1/currencies", "10.0.0.1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+
+	w = requestFrom(router, "/convert?from=USD&to=INR", "10.0.0.1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	w = requestFrom(router, "/api/v1/currencies", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.True(t, retryAfter > 0 && retryAfter <= 30)
+
+	w = requestFrom(router, "/api/v1/currencies", "10.0.0.2")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = requestFrom(router, "/health", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimitRouteCosts(t *testing.T) {
+	router := newRateLimitedRouter(10, map[string]int{"/api/v1/latest": 4})
+	start := time.Now().AddDate(0, 0, -4).Format("2006-01-02")
+	end := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	w := requestFrom(router, "/api/v1/historical?from=USD&to=INR&start_date="+start+"&end_date="+end, "10.0.0.3")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "6", w.Header().Get("RateLimit-Remaining"))
+
+	w = requestFrom(router, "/api/v1/latest?base=USD", "10.0.0.3")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Remaining"))
+
+	w = requestFrom(router, "/api/v1/latest?base=USD", "10.0.0.3")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestRateLimitCapsHistoricalRangeCost(t *testing.T) {
+	router := newRateLimitedRouter(1000, nil)
+
+	start := time.Now()
+	w := requestFrom(router, "/api/v1/historical?from=USD&to=INR&start_date=0001-01-01&end_date=9999-12-31", "10.0.0.4")
+	assert.Less(t, time.Since(start), time.Second)
+	assert.NotEqual(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, strconv.Itoa(1000-utils.MaxHistoryDays), w.Header().Get("RateLimit-Remaining"))
+
+	w = requestFrom(router, "/api/v1/historical?from=USD&to=INR&start_date=garbage&end_date=2025-08-01", "10.0.0.4")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, strconv.Itoa(1000-utils.MaxHistoryDays-1), w.Header().Get("RateLimit-Remaining"))
+}
@@ -0,0 +1,173 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/internal/telemetry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func newSpanExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	_, err := telemetry.Setup(context.Background(), "", "")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func spansByName(spans tracetest.SpanStubs) map[string][]tracetest.SpanStub {
+	byName := make(map[string][]tracetest.SpanStub)
+	for _, span := range spans {
+		byName[span.Name] = append(byName[span.Name], span)
+	}
+	return byName
+}
+
+func spanAttr(span tracetest.SpanStub, key string) attribute.Value {
+	for _, kv := range span.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracingAcrossLayers(t *testing.T) {
+	exporter := newSpanExporter(t)
+
+	var upstreamTraceparent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamTraceparent = r.Header.Get("traceparent")
+		fmt.Fprintf(w, `{"success":true,"historical":true,"date":%q,"timestamp":%d,"source":"USD","quotes":{"USDINR":83.1}}`,
+			r.URL.Query().Get("date"), time.Now().Unix())
+	}))
+	defer upstream.Close()
+
+	logger := zap.NewNop()
+	apiRepo := repository.NewExchangeAPIRepository("", upstream.URL, newSnapshotRepository(t), logger)
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger)
+	router := api.NewRouter(exchangeService, logger)
+
+	parent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	url := "/api/v1/historical?from=USD&to=INR&start_date=" + yesterday + "&end_date=" + yesterday
+
+	w := errorRequest(router, url, map[string]string{"traceparent": parent})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	spans := spansByName(exporter.GetSpans())
+	require.Len(t, spans["GET /api/v1/historical"], 1)
+	server := spans["GET /api/v1/historical"][0]
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.Equal(t, int64(http.StatusOK), spanAttr(server, "http.response.status_code").AsInt64())
+
+	require.Len(t, spans["ExchangeService.GetHistoricalRates"], 1)
+	svc := spans["ExchangeService.GetHistoricalRates"][0]
+	assert.Equal(t, server.SpanContext.SpanID(), svc.Parent.SpanID())
+
+	require.Len(t, spans["cache.get"], 1)
+	assert.False(t, spanAttr(spans["cache.get"][0], "cache.hit").AsBool())
+	require.Len(t, spans["cache.set"], 1)
+
+	require.Len(t, spans["GET /historical"], 1)
+	client := spans["GET /historical"][0]
+	assert.Equal(t, trace.SpanKindClient, client.SpanKind)
+	assert.Equal(t, server.SpanContext.TraceID(), client.SpanContext.TraceID())
+	assert.Contains(t, upstreamTraceparent, server.SpanContext.TraceID().String())
+
+	exporter.Reset()
+	w = errorRequest(router, url, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	spans = spansByName(exporter.GetSpans())
+	require.Len(t, spans["cache.get"], 1)
+	assert.True(t, spanAttr(spans["cache.get"][0], "cache.hit").AsBool())
+	assert.Empty(t, spans["GET /historical"])
+}
+
+func TestTracingRecordsErrors(t *testing.T) {
+	exporter := newSpanExporter(t)
+
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), failingRatesRepository{}, logger)
+	router := api.NewRouter(exchangeService, logger)
+
+	w := errorRequest(router, "/api/v1/convert?from=USD&to=INR", nil)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	spans := spansByName(exporter.GetSpans())
+	require.Len(t, spans["ExchangeService.ConvertCurrency"], 1)
+	convert := spans["ExchangeService.ConvertCurrency"][0]
+	assert.Equal(t, codes.Error, convert.Status.Code)
+	assert.NotEmpty(t, convert.Events)
+
+	require.Len(t, spans["GET /api/v1/convert"], 1)
+	assert.Equal(t, codes.Error, spans["GET /api/v1/convert"][0].Status.Code)
+}
+
+func TestTracingRedactsProviderAccessKey(t *testing.T) {
+	exporter := newSpanExporter(t)
+
+	var accessKey string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accessKey = r.URL.Query().Get("access_key")
+		fmt.Fprintf(w, `{"success":true,"timestamp":%d,"source":"USD","quotes":{"USDINR":83.1}}`, time.Now().Unix())
+	}))
+
+	logger := zap.NewNop()
+	apiRepo := repository.NewExchangeAPIRepository("provider-secret", upstream.URL, nil, logger)
+
+	_, err := apiRepo.GetLatestRate(context.Background(), "USD", "INR")
+	require.NoError(t, err)
+	assert.Equal(t, "provider-secret", accessKey, "the provider still receives the key")
+
+	upstream.Close()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger)
+	_, err = exchangeService.ConvertCurrency(context.Background(), &domain.ConversionRequest{From: "USD", To: "INR", Amount: 1})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "provider-secret")
+
+	spans := exporter.GetSpans()
+	require.NotEmpty(t, spans)
+	for _, span := range spans {
+		assert.NotContains(t, span.Status.Description, "provider-secret", span.Name)
+		for _, kv := range span.Attributes {
+			assert.NotContains(t, kv.Value.Emit(), "provider-secret", "%s %s", span.Name, kv.Key)
+		}
+		for _, event := range span.Events {
+			for _, kv := range event.Attributes {
+				assert.NotContains(t, kv.Value.Emit(), "provider-secret", "%s %s", span.Name, kv.Key)
+			}
+		}
+	}
+}
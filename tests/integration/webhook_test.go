@@ -0,0 +1,359 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type webhookFixture struct {
+	apiRepo         *stubRatesRepository
+	exchangeService *service.ExchangeService
+	webhookService  *service.WebhookService
+	router          *gin.Engine
+	updates         <-chan domain.RateUpdate
+}
+
+func newWebhookFixture(t *testing.T, maxAttempts int) *webhookFixture {
+	t.Helper()
+
+	logger := zap.NewNop()
+	apiRepo := newStubRatesRepository()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger)
+
+	webhookRepo, err := repository.NewWebhookRepository("", 0)
+	require.NoError(t, err)
+	webhookService := service.NewWebhookService(webhookRepo, logger, maxAttempts, 10*time.Millisecond)
+
+	updates, unsubscribe := exchangeService.SubscribeUpdates(64)
+	t.Cleanup(unsubscribe)
+
+	return &webhookFixture{
+		apiRepo:         apiRepo,
+		exchangeService: exchangeService,
+		webhookService:  webhookService,
+		router:          api.NewRouter(exchangeService, logger, api.WithWebhooks(webhookService), api.WithAdminToken(testAdminToken)),
+		updates:         updates,
+	}
+}
+
+// refresh runs one updater cycle and evaluates the published snapshots.
+func (f *webhookFixture) refresh(t *testing.T, expected int) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go f.exchangeService.StartRateUpdater(ctx)
+
+	for i := 0; i < expected; i++ {
+		select {
+		case update := <-f.updates:
+			f.webhookService.Evaluate(context.Background(), update)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for rate update")
+		}
+	}
+}
+
+func (f *webhookFixture) do(method, url string, body interface{}) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	}
+
+	req, _ := http.NewRequest(method, url, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestWebhookCRUD(t *testing.T) {
+	f := newWebhookFixture(t, 1)
+
+	w := f.do("POST", "/admin/webhooks", map[string]interface{}{
+		"pair":      "usdinr",
+		"condition": map[string]interface{}{"type": "above", "threshold": 84},
+		"url":       "http://example.com/hook",
+		"secret":    "s3cret",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created domain.WebhookSubscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "USDINR", created.Pair)
+	assert.Empty(t, created.Secret)
+
+	w = f.do("GET", "/admin/webhooks/"+created.ID, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = f.do("PUT", "/admin/webhooks/"+created.ID, map[string]interface{}{
+		"pair":      "USDINR",
+		"condition": map[string]interface{}{"type": "below", "threshold": 80},
+		"url":       "http://example.com/hook",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var updated domain.WebhookSubscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, "below", updated.Condition.Type)
+
+	w = f.do("GET", "/admin/webhooks", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	w = f.do("DELETE", "/admin/webhooks/"+created.ID, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = f.do("GET", "/admin/webhooks/"+created.ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhooksNeedAdminToken(t *testing.T) {
+	f := newWebhookFixture(t, 1)
+
+	for _, url := range []string{"/admin/webhooks", "/admin/webhooks/dead-letters"} {
+		req, _ := http.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		f.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, url)
+	}
+
+	for _, url := range []string{"/api/v1/webhooks", "/api/v1/webhooks/dead-letters"} {
+		req, _ := http.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		f.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code, url)
+	}
+}
+
+func TestWebhookValidation(t *testing.T) {
+	f := newWebhookFixture(t, 1)
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"Unsupported pair", map[string]interface{}{
+			"pair": "USDXYZ", "condition": map[string]interface{}{"type": "above", "threshold": 1},
+			"url": "http://example.com", "secret": "s"}},
+		{"Unknown condition", map[string]interface{}{
+			"pair": "USDINR", "condition": map[string]interface{}{"type": "sideways", "threshold": 1},
+			"url": "http://example.com", "secret": "s"}},
+		{"Relative URL", map[string]interface{}{
+			"pair": "USDINR", "condition": map[string]interface{}{"type": "above", "threshold": 1},
+			"url": "/hook", "secret": "s"}},
+		{"Missing secret", map[string]interface{}{
+			"pair": "USDINR", "condition": map[string]interface{}{"type": "above", "threshold": 1},
+			"url": "http://example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := f.do("POST", "/admin/webhooks", tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestWebhookDeliversSignedEventOnThresholdCross(t *testing.T) {
+	f := newWebhookFixture(t, 3)
+
+	var attempts atomic.Int32
+	received := make(chan domain.WebhookEvent, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+		if r.Header.Get("X-Webhook-Signature") != service.SignWebhookPayload("s3cret", timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if attempts.Add(1) < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var event domain.WebhookEvent
+		json.Unmarshal(body, &event)
+		received <- event
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	w := f.do("POST", "/admin/webhooks", map[string]interface{}{
+		"pair":      "USDINR",
+		"condition": map[string]interface{}{"type": "above", "threshold": 84},
+		"url":       receiver.URL,
+		"secret":    "s3cret",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	f.refresh(t, 5)
+
+	f.apiRepo.set("USD", "INR", 84.5)
+	f.refresh(t, 1)
+
+	select {
+	case event := <-received:
+		assert.Equal(t, "USDINR", event.Pair)
+		assert.Equal(t, 84.5, event.Rate)
+		assert.Equal(t, 83.25, event.ReferenceRate)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+
+	f.webhookService.Wait()
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestWebhookChangePercentAndDeadLetter(t *testing.T) {
+	f := newWebhookFixture(t, 2)
+
+	var attempts atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	w := f.do("POST", "/admin/webhooks", map[string]interface{}{
+		"pair":      "EURGBP",
+		"condition": map[string]interface{}{"type": "change_pct", "threshold": 5},
+		"url":       receiver.URL,
+		"secret":    "s3cret",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	f.refresh(t, 5)
+
+	f.apiRepo.set("EUR", "GBP", 0.88)
+	f.refresh(t, 1)
+	f.webhookService.Wait()
+	assert.Equal(t, int32(0), attempts.Load())
+
+	f.apiRepo.set("EUR", "GBP", 0.92)
+	f.refresh(t, 1)
+	f.webhookService.Wait()
+	assert.Equal(t, int32(2), attempts.Load())
+
+	w = f.do("GET", "/admin/webhooks/dead-letters", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		DeadLetters []domain.WebhookDeadLetter `json:"dead_letters"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.DeadLetters, 1)
+	assert.Equal(t, 2, resp.DeadLetters[0].Attempts)
+	assert.InDelta(t, 6.98, resp.DeadLetters[0].Event.ChangePercent, 0.01)
+}
+
+func TestWebhookFiresOnFirstSnapshotAfterRestart(t *testing.T) {
+	logger := zap.NewNop()
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+
+	received := make(chan domain.WebhookEvent, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event domain.WebhookEvent
+		json.NewDecoder(r.Body).Decode(&event)
+		received <- event
+	}))
+	defer receiver.Close()
+
+	webhookRepo, err := repository.NewWebhookRepository(path, 0)
+	require.NoError(t, err)
+	webhookService := service.NewWebhookService(webhookRepo, logger, 1, time.Millisecond)
+	_, err = webhookService.Create(&domain.WebhookSubscription{
+		Pair:      "USDINR",
+		Condition: domain.WebhookCondition{Type: service.ConditionAbove, Threshold: 84},
+		URL:       receiver.URL,
+		Secret:    "s3cret",
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	webhookService.Evaluate(ctx, domain.RateUpdate{BaseCurrency: "USD", Rates: map[string]float64{"INR": 83.25}, Timestamp: time.Now()})
+
+	reloaded, err := repository.NewWebhookRepository(path, 0)
+	require.NoError(t, err)
+	restarted := service.NewWebhookService(reloaded, logger, 1, time.Millisecond)
+	restarted.Evaluate(ctx, domain.RateUpdate{BaseCurrency: "USD", Rates: map[string]float64{"INR": 84.5}, Timestamp: time.Now()})
+	restarted.Wait()
+
+	select {
+	case event := <-received:
+		assert.Equal(t, 84.5, event.Rate)
+		assert.Equal(t, 83.25, event.ReferenceRate)
+	default:
+		t.Fatal("the crossing after the restart did not fire")
+	}
+}
+
+func TestWebhookDeadLetterReplay(t *testing.T) {
+	f := newWebhookFixture(t, 1)
+
+	var healthy atomic.Bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer receiver.Close()
+
+	w := f.do("POST", "/admin/webhooks", map[string]interface{}{
+		"pair":      "USDINR",
+		"condition": map[string]interface{}{"type": "above", "threshold": 84},
+		"url":       receiver.URL,
+		"secret":    "s3cret",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	f.refresh(t, 5)
+	f.apiRepo.set("USD", "INR", 84.5)
+	f.refresh(t, 1)
+	f.webhookService.Wait()
+
+	letters, err := f.webhookService.ListDeadLetters()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	id := letters[0].ID
+
+	w = f.do("POST", "/admin/webhooks/dead-letters/"+id+"/replay", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	letters, err = f.webhookService.ListDeadLetters()
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, 2, letters[0].Attempts)
+
+	healthy.Store(true)
+	w = f.do("POST", "/admin/webhooks/dead-letters/"+id+"/replay", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	letters, err = f.webhookService.ListDeadLetters()
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+
+	w = f.do("POST", "/admin/webhooks/dead-letters/"+id+"/replay", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
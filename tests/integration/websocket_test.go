@@ -0,0 +1,299 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/api"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/lifecycle"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubRatesRepository struct {
+	mu    sync.Mutex
+	rates map[string]map[string]float64
+	calls map[string]int
+}
+
+func newStubRatesRepository() *stubRatesRepository {
+	return &stubRatesRepository{
+		rates: map[string]map[string]float64{
+			"USD": {"INR": 83.25, "EUR": 0.85, "JPY": 110.50, "GBP": 0.73},
+			"EUR": {"USD": 1.18, "INR": 98.12, "JPY": 130.25, "GBP": 0.86},
+			"GBP": {"USD": 1.37, "INR": 114.05, "EUR": 1.16, "JPY": 151.38},
+			"INR": {"USD": 0.012, "EUR": 0.010, "JPY": 1.33, "GBP": 0.0088},
+			"JPY": {"USD": 0.0090, "EUR": 0.0077, "INR": 0.75, "GBP": 0.0066},
+		},
+		calls: make(map[string]int),
+	}
+}
+
+func (r *stubRatesRepository) callCount(method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls[method]
+}
+
+func (r *stubRatesRepository) set(from, to string, rate float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rates[from][to] = rate
+}
+
+func (r *stubRatesRepository) GetLatestRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls["GetLatestRate"]++
+	return r.rate(from, to), nil
+}
+
+func (r *stubRatesRepository) rate(from, to string) *domain.ExchangeRate {
+	return &domain.ExchangeRate{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         r.rates[from][to],
+		Timestamp:    time.Now(),
+		Date:         time.Now().Format("2006-01-02"),
+	}
+}
+
+func (r *stubRatesRepository) GetHistoricalRate(ctx context.Context, from, to, date string) (*domain.ExchangeRate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls["GetHistoricalRate"]++
+	rate := r.rate(from, to)
+	rate.Date = date
+	return rate, nil
+}
+
+func (r *stubRatesRepository) GetAllLatestRates(ctx context.Context, baseCurrency string) (map[string]float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls["GetAllLatestRates"]++
+	rates := make(map[string]float64)
+	for currency, rate := range r.rates[baseCurrency] {
+		rates[currency] = rate
+	}
+	return rates, nil
+}
+
+func dialWebSocket(t *testing.T, server *httptest.Server) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func readStreamMessage(t *testing.T, conn *websocket.Conn) domain.StreamMessage {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg domain.StreamMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+
+	return msg
+}
+
+func TestWebSocketSubscribeSnapshotAndUpdates(t *testing.T) {
+	logger := zap.NewNop()
+	apiRepo := newStubRatesRepository()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger)
+
+	server := httptest.NewServer(api.NewRouter(exchangeService, logger))
+	defer server.Close()
+
+	conn := dialWebSocket(t, server)
+
+	require.NoError(t, conn.WriteJSON(domain.StreamRequest{Action: "subscribe", Pairs: []string{"usdinr", "EURGBP"}}))
+	msg := readStreamMessage(t, conn)
+	assert.Equal(t, "subscribed", msg.Type)
+	assert.ElementsMatch(t, []string{"USDINR", "EURGBP"}, msg.Pairs)
+
+	require.NoError(t, conn.WriteJSON(domain.StreamRequest{Action: "snapshot"}))
+	msg = readStreamMessage(t, conn)
+	assert.Equal(t, "snapshot", msg.Type)
+	assert.Len(t, msg.Rates, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go exchangeService.StartRateUpdater(ctx)
+
+	seen := make(map[string]float64)
+	for len(seen) < 2 {
+		msg = readStreamMessage(t, conn)
+		require.Equal(t, "update", msg.Type)
+		for _, rate := range msg.Rates {
+			seen[rate.Pair] = rate.Rate
+		}
+	}
+	assert.Equal(t, 83.25, seen["USDINR"])
+	assert.Equal(t, 0.86, seen["EURGBP"])
+
+	require.NoError(t, conn.WriteJSON(domain.StreamRequest{Action: "unsubscribe", Pairs: []string{"EURGBP"}}))
+	msg = readStreamMessage(t, conn)
+	assert.Equal(t, "unsubscribed", msg.Type)
+}
+
+func TestWebSocketDeltaUpdatesOnlyCarryChangedPairs(t *testing.T) {
+	logger := zap.NewNop()
+	apiRepo := newStubRatesRepository()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), apiRepo, logger)
+
+	updates, cancelUpdates := exchangeService.SubscribeUpdates(16)
+	defer cancelUpdates()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go exchangeService.StartRateUpdater(ctx)
+	for i := 0; i < 5; i++ {
+		<-updates
+	}
+	cancel()
+
+	server := httptest.NewServer(api.NewRouter(exchangeService, logger))
+	defer server.Close()
+
+	conn := dialWebSocket(t, server)
+	require.NoError(t, conn.WriteJSON(domain.StreamRequest{Action: "subscribe", Pairs: []string{"USDINR", "USDEUR"}}))
+	assert.Equal(t, "subscribed", readStreamMessage(t, conn).Type)
+
+	apiRepo.set("USD", "INR", 84.10)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go exchangeService.StartRateUpdater(ctx)
+
+	msg := readStreamMessage(t, conn)
+	assert.Equal(t, "update", msg.Type)
+	require.Len(t, msg.Rates, 1)
+	assert.Equal(t, "USDINR", msg.Rates[0].Pair)
+	assert.Equal(t, 84.10, msg.Rates[0].Rate)
+}
+
+func TestWebSocketRejectsInvalidMessages(t *testing.T) {
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger)
+
+	server := httptest.NewServer(api.NewRouter(exchangeService, logger))
+	defer server.Close()
+
+	conn := dialWebSocket(t, server)
+
+	require.NoError(t, conn.WriteJSON(domain.StreamRequest{Action: "subscribe", Pairs: []string{"USDXYZ"}}))
+	msg := readStreamMessage(t, conn)
+	assert.Equal(t, "error", msg.Type)
+	assert.Equal(t, "invalid_pair", msg.Error)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("not json")))
+	msg = readStreamMessage(t, conn)
+	assert.Equal(t, "invalid_message", msg.Error)
+
+	require.NoError(t, conn.WriteJSON(domain.StreamRequest{Action: "dance"}))
+	msg = readStreamMessage(t, conn)
+	assert.Equal(t, "unknown_action", msg.Error)
+}
+
+func TestWebSocketConnectionLimit(t *testing.T) {
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger)
+
+	server := httptest.NewServer(api.NewRouter(exchangeService, logger, api.WithWebSocketLimits(1, 8)))
+	defer server.Close()
+
+	dialWebSocket(t, server)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws"
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	var body domain.ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, domain.CodeOverloaded, body.Error)
+}
+
+func TestWebSocketChecksOriginAgainstCORSAllowlist(t *testing.T) {
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger)
+
+	server := httptest.NewServer(api.NewRouter(exchangeService, logger, api.WithCORSOrigins([]string{"https://app.example.com"})))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws"
+	dial := func(origin string) (*http.Response, error) {
+		conn, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {origin}})
+		if err == nil {
+			conn.Close()
+		}
+		return resp, err
+	}
+
+	resp, err := dial("https://evil.example.com")
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	_, err = dial("https://app.example.com")
+	assert.NoError(t, err)
+
+	_, err = dial(server.URL)
+	assert.NoError(t, err, "same-origin pages are always allowed")
+
+	req := httptest.NewRequest("GET", "/api/v1/currencies", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w := httptest.NewRecorder()
+	server.Config.Handler.ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	req.Header.Set("Origin", "https://app.example.com")
+	w = httptest.NewRecorder()
+	server.Config.Handler.ServeHTTP(w, req)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestWebSocketStreamsCloseOnShutdown(t *testing.T) {
+	logger := zap.NewNop()
+	exchangeService := service.NewExchangeService(repository.NewCacheRepository(), newStubRatesRepository(), logger)
+	streams := lifecycle.NewConnections()
+
+	server := httptest.NewServer(api.NewRouter(exchangeService, logger, api.WithConnections(streams)))
+	defer server.Close()
+
+	conn := dialWebSocket(t, server)
+	require.Eventually(t, func() bool { return streams.Len() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, streams.Close(ctx))
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "got %v", err)
+	assert.Equal(t, 0, streams.Len())
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws"
+	late, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err == nil {
+		late.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _, err = late.ReadMessage()
+		late.Close()
+	}
+	assert.Error(t, err, "streams opened after shutdown began are closed at once")
+}
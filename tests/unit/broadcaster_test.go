@@ -0,0 +1,60 @@
+package unit
+
+import (
+	"testing"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateBroadcaster_DropsForSlowSubscribers(t *testing.T) {
+	broadcaster := service.NewRateBroadcaster()
+
+	fast, cancelFast := broadcaster.Subscribe(4)
+	defer cancelFast()
+	_, cancelSlow := broadcaster.Subscribe(1)
+	defer cancelSlow()
+
+	assert.Equal(t, 0, broadcaster.Publish(domain.RateUpdate{BaseCurrency: "USD"}))
+	assert.Equal(t, 1, broadcaster.Publish(domain.RateUpdate{BaseCurrency: "EUR"}))
+
+	assert.Equal(t, "USD", (<-fast).BaseCurrency)
+	assert.Equal(t, "EUR", (<-fast).BaseCurrency)
+}
+
+func TestRateBroadcaster_CancelClosesChannel(t *testing.T) {
+	broadcaster := service.NewRateBroadcaster()
+
+	updates, cancel := broadcaster.Subscribe(1)
+	assert.Equal(t, 1, broadcaster.Subscribers())
+
+	cancel()
+	cancel()
+
+	_, ok := <-updates
+	assert.False(t, ok)
+	assert.Equal(t, 0, broadcaster.Subscribers())
+	assert.Equal(t, 0, broadcaster.Publish(domain.RateUpdate{BaseCurrency: "USD"}))
+}
+
+func TestRateBroadcaster_QueuedSubscriberMissesNothing(t *testing.T) {
+	broadcaster := service.NewRateBroadcaster()
+
+	updates, cancel := broadcaster.SubscribeQueued()
+	assert.Equal(t, 1, broadcaster.Subscribers())
+
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, 0, broadcaster.Publish(domain.RateUpdate{BaseCurrency: "USD", Rates: map[string]float64{"INR": float64(i)}}))
+	}
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, float64(i), (<-updates).Rates["INR"])
+	}
+
+	cancel()
+	cancel()
+	_, ok := <-updates
+	assert.False(t, ok)
+	assert.Equal(t, 0, broadcaster.Subscribers())
+}
@@ -0,0 +1,186 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"exchange-rate-service/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, config.Default(), cfg)
+}
+
+func TestLoad_Layers(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+port: "9000"
+rate_limit: 50
+log_level: warn
+enabled_currencies: [usd, eur]
+consensus_providers:
+  ecb: 2
+`)
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("RATE_LIMIT", "75")
+	t.Setenv("LOG_LEVEL", "error")
+
+	cfg, err := config.Load([]string{"--log-level", "debug", "--auth-enabled"})
+	require.NoError(t, err)
+
+	assert.Equal(t, path, cfg.Path)
+	assert.Equal(t, "9000", cfg.Port, "the file overrides the default")
+	assert.Equal(t, 75, cfg.RateLimit, "the environment overrides the file")
+	assert.Equal(t, "debug", cfg.LogLevel, "flags override the environment")
+	assert.True(t, cfg.AuthEnabled)
+	assert.Equal(t, []string{"USD", "EUR"}, cfg.EnabledCurrencies)
+	assert.Equal(t, map[string]int{"ecb": 2}, cfg.ConsensusProviders)
+	assert.Equal(t, 120, config.Default().RateLimit)
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `
+update_cron = "@hourly"
+demand_min_score = 2.5
+rate_limit_costs = { convert = 2 }
+`)
+
+	cfg, err := config.Load([]string{"--config", path})
+	require.NoError(t, err)
+	assert.Equal(t, "@hourly", cfg.UpdateCron)
+	assert.Equal(t, 2.5, cfg.DemandMinScore)
+	assert.Equal(t, map[string]int{"convert": 2}, cfg.RateLimitCosts)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		env   map[string]string
+		args  []string
+		wants []string
+	}{
+		{
+			name:  "Malformed integer",
+			env:   map[string]string{"RATE_LIMIT": "lots"},
+			wants: []string{"environment variable RATE_LIMIT", `invalid integer "lots"`},
+		},
+		{
+			name:  "Malformed map entry",
+			env:   map[string]string{"RATE_LIMIT_COSTS": "convert"},
+			wants: []string{"RATE_LIMIT_COSTS", "expected key=value"},
+		},
+		{
+			name:  "Malformed flag",
+			args:  []string{"--auth-enabled=maybe"},
+			wants: []string{"flag --auth-enabled", `invalid boolean "maybe"`},
+		},
+		{
+			name:  "Unknown flag",
+			args:  []string{"--no-such-setting", "1"},
+			wants: []string{"no-such-setting"},
+		},
+		{
+			name:  "Unknown file key",
+			file:  "rate_limt: 10\n",
+			wants: []string{`unknown setting "rate_limt"`},
+		},
+		{
+			name:  "Wrong file type",
+			file:  "rate_limit: [1, 2]\n",
+			wants: []string{"rate_limit", "got a list"},
+		},
+		{
+			name: "Invalid values",
+			env: map[string]string{
+				"LOG_LEVEL":          "loud",
+				"RATE_LIMIT":         "0",
+				"FIXING_TIMEZONE":    "Mars/Olympus",
+				"ENABLED_CURRENCIES": "USD,XYZ",
+			},
+			wants: []string{
+				"log_level: must be one of debug, info, warn, error",
+				"rate_limit: must be positive",
+				`fixing_timezone: unknown timezone "Mars/Olympus"`,
+				`enabled_currencies: unsupported currency "XYZ"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append(args, "--config", writeConfigFile(t, "config.yaml", tt.file))
+			}
+
+			_, err := config.Load(args)
+			require.Error(t, err)
+			for _, want := range tt.wants {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestLoad_UnsupportedFileFormat(t *testing.T) {
+	path := writeConfigFile(t, "config.json", "{}")
+	_, err := config.Load([]string{"--config", path})
+	assert.ErrorContains(t, err, "unsupported format")
+}
+
+func TestChanged(t *testing.T) {
+	old := config.Default()
+	current := config.Default()
+	current.LogLevel = "debug"
+	current.Port = "9000"
+	current.EnabledCurrencies = []string{"USD"}
+
+	assert.Equal(t, []string{"port", "log_level", "enabled_currencies"}, config.Changed(old, current))
+	assert.Empty(t, config.Changed(old, config.Default()))
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "log_level: info\n")
+	args := []string{"--config", path}
+	cfg, err := config.Load(args)
+	require.NoError(t, err)
+
+	var applied []*config.Config
+	watcher := config.NewWatcher(cfg, args, zap.NewNop(), func(next *config.Config) error {
+		applied = append(applied, next)
+		return nil
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte("log_level: debug\ncache_expiration: 60\n"), 0o644))
+	require.NoError(t, watcher.Reload())
+	require.Len(t, applied, 1)
+	assert.Equal(t, "debug", applied[0].LogLevel)
+	assert.Equal(t, 60, applied[0].CacheExpiration)
+
+	require.NoError(t, os.WriteFile(path, []byte("log_level: debug\nport: \"9000\"\n"), 0o644))
+	require.NoError(t, watcher.Reload())
+	require.Len(t, applied, 2)
+	assert.Equal(t, cfg.Port, applied[1].Port, "a setting that needs a restart keeps its startup value")
+	assert.Equal(t, config.Default().CacheExpiration, applied[1].CacheExpiration, "reloadable settings follow the file")
+
+	require.NoError(t, os.WriteFile(path, []byte("log_level: loud\n"), 0o644))
+	assert.Error(t, watcher.Reload(), "an invalid file is rejected")
+	assert.Len(t, applied, 2, "and not applied")
+}
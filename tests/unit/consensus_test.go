@@ -0,0 +1,102 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fixedRates answers every request with the same quotes, or with err.
+type fixedRates struct {
+	rates map[string]float64
+	err   error
+}
+
+func (f *fixedRates) GetLatestRate(ctx context.Context, from, to string) (*domain.ExchangeRate, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &domain.ExchangeRate{FromCurrency: from, ToCurrency: to, Rate: f.rates[to], Timestamp: time.Now(), Date: "2025-09-01"}, nil
+}
+
+func (f *fixedRates) GetHistoricalRate(ctx context.Context, from, to, date string) (*domain.ExchangeRate, error) {
+	rate, err := f.GetLatestRate(ctx, from, to)
+	if err == nil {
+		rate.Date = date
+	}
+	return rate, err
+}
+
+func (f *fixedRates) GetAllLatestRates(ctx context.Context, baseCurrency string) (map[string]float64, error) {
+	return f.rates, f.err
+}
+
+func newConsensus(t *testing.T, options repository.ConsensusOptions, sources ...repository.ConsensusSource) *repository.ConsensusRepository {
+	t.Helper()
+	repo, err := repository.NewConsensusRepository(sources, options, zap.NewNop())
+	require.NoError(t, err)
+	return repo
+}
+
+func TestConsensus_RejectsOutliersAndBadTicks(t *testing.T) {
+	repo := newConsensus(t, repository.ConsensusOptions{MaxDeviation: 0.02},
+		repository.ConsensusSource{Name: "a", Repo: &fixedRates{rates: map[string]float64{"JPY": 147.0, "INR": 83.0}}},
+		repository.ConsensusSource{Name: "b", Repo: &fixedRates{rates: map[string]float64{"JPY": 147.4, "INR": 83.2}}},
+		repository.ConsensusSource{Name: "c", Repo: &fixedRates{rates: map[string]float64{"JPY": 0, "INR": 90.0}}},
+		repository.ConsensusSource{Name: "d", Repo: &fixedRates{rates: map[string]float64{"JPY": 147.2, "INR": 83.1}}},
+	)
+
+	rate, err := repo.GetLatestRate(context.Background(), "USD", "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, 147.2, rate.Rate)
+	require.NotNil(t, rate.Consensus)
+	assert.Equal(t, repository.ConsensusMedian, rate.Consensus.Method)
+	assert.Equal(t, 3, rate.Consensus.Sources)
+	assert.Equal(t, 4, rate.Consensus.Queried)
+	assert.Equal(t, []string{"c"}, rate.Consensus.Rejected)
+	assert.InDelta(t, 0.4, rate.Consensus.Spread, 1e-9)
+
+	rates, err := repo.GetAllLatestRates(context.Background(), "USD")
+	require.NoError(t, err)
+	assert.Equal(t, 147.2, rates["JPY"])
+	assert.Equal(t, 83.1, rates["INR"])
+}
+
+func TestConsensus_WeightedMean(t *testing.T) {
+	repo := newConsensus(t, repository.ConsensusOptions{Method: repository.ConsensusWeightedMean, MaxDeviation: 0.05},
+		repository.ConsensusSource{Name: "ecb", Repo: &fixedRates{rates: map[string]float64{"EUR": 0.86}}, Weight: 3},
+		repository.ConsensusSource{Name: "host", Repo: &fixedRates{rates: map[string]float64{"EUR": 0.84}}},
+	)
+
+	rate, err := repo.GetHistoricalRate(context.Background(), "USD", "EUR", "2025-09-01")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.855, rate.Rate, 1e-9)
+	assert.Equal(t, "2025-09-01", rate.Date)
+	assert.Equal(t, 2, rate.Consensus.Sources)
+}
+
+func TestConsensus_RequiresMinimumAgreement(t *testing.T) {
+	repo := newConsensus(t, repository.ConsensusOptions{MaxDeviation: 0.02, MinSources: 2},
+		repository.ConsensusSource{Name: "a", Repo: &fixedRates{rates: map[string]float64{"INR": 83.0}}},
+		repository.ConsensusSource{Name: "b", Repo: &fixedRates{err: errors.New("timeout")}},
+	)
+
+	_, err := repo.GetLatestRate(context.Background(), "USD", "INR")
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+
+	_, err = repo.GetAllLatestRates(context.Background(), "USD")
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+
+	_, err = repository.NewConsensusRepository(nil, repository.ConsensusOptions{MaxDeviation: 0.02}, zap.NewNop())
+	assert.Error(t, err)
+	_, err = repository.NewConsensusRepository([]repository.ConsensusSource{{Name: "a", Repo: &fixedRates{}}}, repository.ConsensusOptions{Method: "mode", MaxDeviation: 0.02}, zap.NewNop())
+	assert.Error(t, err)
+}
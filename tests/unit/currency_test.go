@@ -0,0 +1,28 @@
+package unit
+
+import (
+	"testing"
+
+	"exchange-rate-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetEnabledCurrencies(t *testing.T) {
+	t.Cleanup(func() { utils.SetEnabledCurrencies(nil) })
+
+	require.NoError(t, utils.SetEnabledCurrencies([]string{"usd", "EUR"}))
+	assert.Equal(t, []string{"EUR", "USD"}, utils.EnabledCurrencies())
+	assert.True(t, utils.IsValidCurrency("USD"))
+	assert.False(t, utils.IsValidCurrency("JPY"))
+
+	_, _, err := utils.ParsePair("USDJPY")
+	assert.Error(t, err, "pairs with a disabled currency are rejected")
+
+	assert.Error(t, utils.SetEnabledCurrencies([]string{"USD", "XYZ"}))
+	assert.Equal(t, []string{"EUR", "USD"}, utils.EnabledCurrencies(), "a rejected list leaves the current one")
+
+	require.NoError(t, utils.SetEnabledCurrencies(nil))
+	assert.Equal(t, []string{"EUR", "GBP", "INR", "JPY", "USD"}, utils.EnabledCurrencies())
+}
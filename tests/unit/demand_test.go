@@ -0,0 +1,84 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func demandKeys(stats []domain.DemandStat) []string {
+	keys := make([]string, 0, len(stats))
+	for _, stat := range stats {
+		keys = append(keys, stat.Key)
+	}
+	return keys
+}
+
+func TestDemandTracker_TopN(t *testing.T) {
+	clk := clock.NewFake(time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC))
+	demand := service.NewDemandTracker(clk, service.DemandOptions{TopPairs: 2, TopBases: 1, HalfLife: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		demand.RecordPair("USD", "INR")
+	}
+	demand.RecordPair("EUR", "GBP")
+	demand.RecordPair("EUR", "GBP")
+	demand.RecordPair("USD", "JPY")
+	demand.RecordBase("EUR")
+	demand.RecordBase("USD")
+	demand.RecordBase("USD")
+
+	hot := demand.HotSet()
+	assert.Equal(t, []string{"USDINR", "EURGBP"}, demandKeys(hot.Pairs))
+	assert.Equal(t, []string{"USD"}, demandKeys(hot.Bases))
+	assert.Equal(t, int64(3), hot.Pairs[0].Requests)
+	assert.InDelta(t, 3.0, hot.Pairs[0].Score, 1e-9)
+}
+
+func TestDemandTracker_Decay(t *testing.T) {
+	clk := clock.NewFake(time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC))
+	demand := service.NewDemandTracker(clk, service.DemandOptions{TopPairs: 2, HalfLife: time.Hour})
+
+	for i := 0; i < 4; i++ {
+		demand.RecordPair("USD", "INR")
+	}
+	clk.Advance(2 * time.Hour)
+	demand.RecordPair("EUR", "GBP")
+	demand.RecordPair("EUR", "GBP")
+
+	hot := demand.HotSet()
+	require.Len(t, hot.Pairs, 2)
+	assert.Equal(t, []string{"EURGBP", "USDINR"}, demandKeys(hot.Pairs), "recent requests outrank older ones")
+	assert.InDelta(t, 1.0, hot.Pairs[1].Score, 1e-9, "two half-lives quarter the score")
+
+	clk.Advance(24 * time.Hour)
+	assert.Empty(t, demand.HotSet().Pairs, "pairs nobody asks for are forgotten")
+}
+
+func TestDemandTracker_MinScore(t *testing.T) {
+	clk := clock.NewFake(time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC))
+	demand := service.NewDemandTracker(clk, service.DemandOptions{TopPairs: 5, HalfLife: time.Hour, MinScore: 2})
+
+	demand.RecordPair("USD", "INR")
+	demand.RecordPair("USD", "INR")
+	demand.RecordPair("EUR", "GBP")
+
+	assert.Equal(t, []string{"USDINR"}, demandKeys(demand.HotSet().Pairs))
+
+	clk.Advance(time.Minute)
+	assert.Empty(t, demand.HotSet().Pairs, "a decayed score falls below the minimum")
+}
+
+func TestParseUpdateGroups_DemandGroupNeedsNoTargets(t *testing.T) {
+	schedules, err := service.ParseUpdateGroups([]domain.UpdateGroup{service.DemandUpdateGroup("", "")})
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.True(t, schedules[0].Demand)
+	assert.Empty(t, schedules[0].Bases)
+}
@@ -0,0 +1,53 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"exchange-rate-service/internal/api/problem"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorTaxonomy(t *testing.T) {
+	upstream := fmt.Errorf("%w: %w", domain.ErrUpstreamUnavailable, errors.New("dial tcp 10.0.0.1:443: access_key=secret"))
+
+	tests := []struct {
+		name    string
+		err     error
+		code    string
+		status  int
+		message string
+	}{
+		{"Unsupported currency", domain.Errorf(domain.ErrUnsupportedCurrency, "unsupported base currency: XYZ"),
+			domain.CodeUnsupportedCurrency, http.StatusBadRequest, "unsupported base currency: XYZ"},
+		{"Date out of range", utils.ValidateDate("2000-01-01"),
+			domain.CodeDateOutOfRange, http.StatusBadRequest, "date cannot be more than 90 days in the past"},
+		{"Wrapped date error", fmt.Errorf("invalid start date: %w", utils.ValidateDate("01-08-2025")),
+			domain.CodeValidation, http.StatusBadRequest, "invalid start date: invalid date format, expected YYYY-MM-DD"},
+		{"Not found", domain.ErrNotFound, domain.CodeNotFound, http.StatusNotFound, "not found"},
+		{"Conflict", domain.Errorf(domain.ErrConflict, "quote already executed"), domain.CodeConflict, http.StatusConflict, "quote already executed"},
+		{"Expired", domain.Errorf(domain.ErrExpired, "quote expired"), domain.CodeExpired, http.StatusGone, "quote expired"},
+		{"Quota", domain.ErrQuotaExceeded, domain.CodeQuotaExceeded, http.StatusTooManyRequests, "daily quota exceeded"},
+		{"Upstream", upstream, domain.CodeUpstreamUnavailable, http.StatusServiceUnavailable, "upstream rate provider unavailable"},
+		{"Stale", domain.Errorf(domain.ErrStaleData, "rates are 3h old"), domain.CodeStaleData, http.StatusServiceUnavailable, "rates are 3h old"},
+		{"Timeout", context.DeadlineExceeded, domain.CodeTimeout, http.StatusGatewayTimeout, "request timed out"},
+		{"Unknown", errors.New("disk full"), domain.CodeInternal, http.StatusInternalServerError, "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, domain.ErrorCode(tt.err))
+			assert.Equal(t, tt.status, problem.Status(tt.err))
+			assert.Equal(t, tt.message, problem.Message(tt.err))
+		})
+	}
+
+	assert.True(t, errors.Is(domain.ErrUnsupportedCurrency, domain.ErrValidation))
+	assert.True(t, errors.Is(domain.ErrDateOutOfRange, domain.ErrValidation))
+}
@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFixingSchedule(t *testing.T) {
+	schedule, err := service.ParseFixingSchedule("16:00", "Europe/Paris")
+	require.NoError(t, err)
+	assert.Equal(t, 16, schedule.Hour)
+	assert.Equal(t, "Europe/Paris", schedule.Location.String())
+
+	_, err = service.ParseFixingSchedule("4pm", "Europe/Paris")
+	assert.Error(t, err)
+	_, err = service.ParseFixingSchedule("16:00", "Mars/Olympus")
+	assert.Error(t, err)
+}
+
+func TestFixingSchedule_Next(t *testing.T) {
+	schedule, err := service.ParseFixingSchedule("16:00", "Europe/Paris")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		after time.Time
+		want  time.Time
+	}{
+		{"Before the fixing in winter", time.Date(2025, 1, 15, 14, 59, 0, 0, time.UTC), time.Date(2025, 1, 15, 15, 0, 0, 0, time.UTC)},
+		{"At the fixing rolls to the next day", time.Date(2025, 1, 15, 15, 0, 0, 0, time.UTC), time.Date(2025, 1, 16, 15, 0, 0, 0, time.UTC)},
+		{"Summer time shifts the UTC instant", time.Date(2025, 7, 15, 9, 0, 0, 0, time.UTC), time.Date(2025, 7, 15, 14, 0, 0, 0, time.UTC)},
+		{"Across the spring clock change", time.Date(2025, 3, 29, 16, 0, 0, 0, time.UTC), time.Date(2025, 3, 30, 14, 0, 0, 0, time.UTC)},
+		{"Late evening UTC is already tomorrow in Paris", time.Date(2025, 1, 15, 23, 30, 0, 0, time.UTC), time.Date(2025, 1, 16, 15, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(schedule.Next(tt.after)), "got %s", schedule.Next(tt.after).UTC())
+		})
+	}
+}
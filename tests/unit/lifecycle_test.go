@@ -0,0 +1,162 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/lifecycle"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap"
+)
+
+type lifecycleEvents struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (e *lifecycleEvents) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, event)
+}
+
+func (e *lifecycleEvents) list() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.events...)
+}
+
+func (e *lifecycleEvents) worker(name string) lifecycle.Worker {
+	return lifecycle.Worker{Name: name, Run: func(ctx context.Context) error {
+		e.add("start " + name)
+		<-ctx.Done()
+		e.add("stop " + name)
+		return nil
+	}}
+}
+
+func TestManager_StopsInReverseOrder(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	events := &lifecycleEvents{}
+	manager := lifecycle.NewManager(zap.NewNop())
+	manager.Add(events.worker("janitor"))
+	manager.Add(events.worker("updater"))
+	manager.Add(events.worker("server"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	manager.Start(ctx)
+	require.Eventually(t, func() bool { return len(events.list()) == 3 }, time.Second, time.Millisecond)
+
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, events.list(), 3, "cancelling the start context does not stop workers")
+
+	require.NoError(t, manager.Stop(context.Background()))
+	assert.Equal(t, []string{"stop server", "stop updater", "stop janitor"}, events.list()[3:])
+}
+
+func TestManager_ReportsWorkersThatDoNotStop(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	release := make(chan struct{})
+	manager := lifecycle.NewManager(zap.NewNop())
+	manager.Go("stubborn", func(ctx context.Context) { <-release })
+	manager.Go("blocked", func(ctx context.Context) { <-release })
+	manager.Go("polite", func(ctx context.Context) { <-ctx.Done() })
+	manager.Start(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := manager.Stop(ctx)
+
+	var stopErr *lifecycle.StopError
+	require.ErrorAs(t, err, &stopErr)
+	assert.Equal(t, []string{"blocked", "stubborn"}, stopErr.Workers)
+
+	close(release)
+}
+
+func TestManager_StopCallsStopWithDeadline(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	stopped := make(chan struct{})
+	var deadline bool
+	manager := lifecycle.NewManager(zap.NewNop())
+	manager.Add(lifecycle.Worker{
+		Name: "server",
+		Run: func(ctx context.Context) error {
+			<-stopped
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			_, deadline = ctx.Deadline()
+			close(stopped)
+			return nil
+		},
+	})
+	manager.Start(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, manager.Stop(ctx))
+	assert.True(t, deadline)
+}
+
+func TestManager_RunShutsDownWhenAWorkerFails(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	events := &lifecycleEvents{}
+	boom := errors.New("address already in use")
+	manager := lifecycle.NewManager(zap.NewNop())
+	manager.Add(events.worker("updater"))
+	manager.Add(lifecycle.Worker{Name: "server", Run: func(ctx context.Context) error { return boom }})
+
+	err := manager.Run(context.Background(), time.Second)
+	require.ErrorIs(t, err, boom)
+	assert.ErrorContains(t, err, "server")
+	assert.Contains(t, events.list(), "stop updater")
+}
+
+func TestConnections_CloseClosesAndWaitsForTrackedConnections(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	connections := lifecycle.NewConnections()
+	finished := make(chan struct{})
+	closing := make(chan struct{})
+	done, ok := connections.Track(func() { close(closing) })
+	require.True(t, ok)
+	go func() {
+		<-closing
+		time.Sleep(20 * time.Millisecond)
+		done()
+		close(finished)
+	}()
+	assert.Equal(t, 1, connections.Len())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, connections.Close(ctx))
+	<-finished
+	assert.Equal(t, 0, connections.Len())
+
+	_, ok = connections.Track(func() {})
+	assert.False(t, ok)
+}
+
+func TestConnections_CloseGivesUpAtDeadline(t *testing.T) {
+	connections := lifecycle.NewConnections()
+	done, ok := connections.Track(func() {})
+	require.True(t, ok)
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, connections.Close(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,78 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMarkupService_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile domain.MarkupProfile
+	}{
+		{"Missing name", domain.MarkupProfile{Default: domain.Markup{BasisPoints: 10}}},
+		{"Bps and percent", domain.MarkupProfile{Name: "retail", Default: domain.Markup{BasisPoints: 10, Percent: 0.1}}},
+		{"Negative markup", domain.MarkupProfile{Name: "retail", Default: domain.Markup{Percent: -1}}},
+		{"Whole rate", domain.MarkupProfile{Name: "retail", Default: domain.Markup{Percent: 100}}},
+		{"Empty rule", domain.MarkupProfile{Name: "retail", Rules: []domain.MarkupRule{{Markup: domain.Markup{BasisPoints: 5}}}}},
+		{"Unsupported pair", domain.MarkupProfile{Name: "retail", Rules: []domain.MarkupRule{{Pairs: []string{"USDXYZ"}}}}},
+		{"Unsupported currency", domain.MarkupProfile{Name: "retail", Rules: []domain.MarkupRule{{Currencies: []string{"XYZ"}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.NewMarkupService([]domain.MarkupProfile{tt.profile}, "", zap.NewNop())
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := service.NewMarkupService(nil, "retail", zap.NewNop())
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestMarkupBps_RulePrecedence(t *testing.T) {
+	svc, err := service.NewMarkupService([]domain.MarkupProfile{{
+		Name:    "retail",
+		Default: domain.Markup{Percent: 1},
+		Rules: []domain.MarkupRule{
+			{Currencies: []string{"inr"}, Markup: domain.Markup{BasisPoints: 150}},
+			{Pairs: []string{"usdinr"}, Markup: domain.Markup{BasisPoints: 25}},
+		},
+	}}, "retail", zap.NewNop())
+	require.NoError(t, err)
+
+	profile := svc.Resolve(context.Background())
+	require.NotNil(t, profile)
+
+	assert.Equal(t, 25.0, service.MarkupBps(profile, "USD", "INR"))
+	assert.Equal(t, 25.0, service.MarkupBps(profile, "INR", "USD"))
+	assert.Equal(t, 150.0, service.MarkupBps(profile, "EUR", "INR"))
+	assert.Equal(t, 100.0, service.MarkupBps(profile, "EUR", "USD"))
+
+	bid, ask := service.Spread(80, 25)
+	assert.InDelta(t, 79.8, bid, 1e-9)
+	assert.InDelta(t, 80.2, ask, 1e-9)
+}
+
+func TestMarkupService_ResolveFallsBackToDefault(t *testing.T) {
+	svc, err := service.NewMarkupService([]domain.MarkupProfile{
+		{Name: "retail", Default: domain.Markup{Percent: 1}},
+		{Name: "institutional", Default: domain.Markup{BasisPoints: 5}},
+	}, "retail", zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Equal(t, "retail", svc.Resolve(context.Background()).Name)
+	assert.Equal(t, "institutional", svc.Resolve(service.WithMarkupProfile(context.Background(), "institutional")).Name)
+	assert.Equal(t, "retail", svc.Resolve(service.WithMarkupProfile(context.Background(), "retired")).Name)
+
+	noDefault, err := service.NewMarkupService([]domain.MarkupProfile{{Name: "retail"}}, "", zap.NewNop())
+	require.NoError(t, err)
+	assert.Nil(t, noDefault.Resolve(context.Background()))
+}
@@ -0,0 +1,103 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func observation(date string, rate float64, observedAt time.Time) domain.RateObservation {
+	return domain.RateObservation{
+		Pair:         "USDINR",
+		FromCurrency: "USD",
+		ToCurrency:   "INR",
+		Date:         date,
+		Rate:         rate,
+		ObservedAt:   observedAt,
+	}
+}
+
+func TestObservationRepository_AsOf(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rate_history.jsonl")
+	repo, err := repository.NewObservationRepository(path, 0)
+	require.NoError(t, err)
+
+	t0 := time.Date(2025, 9, 1, 16, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Record([]domain.RateObservation{observation("2025-09-01", 83.25, t0)}))
+	require.NoError(t, repo.Record([]domain.RateObservation{observation("2025-09-01", 83.25, t0.Add(time.Hour))}))
+	require.NoError(t, repo.Record([]domain.RateObservation{observation("2025-09-01", 83.40, t0.Add(24*time.Hour))}))
+	require.NoError(t, repo.Record([]domain.RateObservation{observation("2025-09-02", 83.10, t0.Add(25*time.Hour))}))
+
+	tests := []struct {
+		name string
+		date string
+		asOf time.Time
+		want float64
+		seen time.Time
+	}{
+		{"Before the revision", "2025-09-01", t0.Add(2 * time.Hour), 83.25, t0},
+		{"After the revision", "2025-09-01", t0.Add(48 * time.Hour), 83.40, t0.Add(24 * time.Hour)},
+		{"Latest date known at the time", "", t0.Add(24 * time.Hour), 83.40, t0.Add(24 * time.Hour)},
+		{"Latest date known later", "", t0.Add(48 * time.Hour), 83.10, t0.Add(25 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.AsOf("USD", "INR", tt.date, tt.asOf)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, found.Rate)
+			assert.True(t, tt.seen.Equal(found.ObservedAt), "an unchanged rate keeps its first observation time")
+		})
+	}
+
+	_, err = repo.AsOf("USD", "INR", "2025-09-01", t0.Add(-time.Second))
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	reloaded, err := repository.NewObservationRepository(path, 0)
+	require.NoError(t, err)
+	found, err := reloaded.AsOf("USD", "INR", "2025-09-01", t0.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 83.25, found.Rate)
+}
+
+func TestObservationRepository_AppendsAndDropsExpired(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rate_history.jsonl")
+	repo, err := repository.NewObservationRepository(path, 30*24*time.Hour)
+	require.NoError(t, err)
+
+	now := time.Now()
+	old := now.AddDate(0, 0, -31)
+	require.NoError(t, repo.Record([]domain.RateObservation{observation(old.Format("2006-01-02"), 83.25, old)}))
+	require.NoError(t, repo.Record([]domain.RateObservation{observation(now.Format("2006-01-02"), 83.40, now)}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"), "records are appended one per line")
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"pair":"USDINR","rate":8`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reloaded, err := repository.NewObservationRepository(path, 30*24*time.Hour)
+	require.NoError(t, err, "a torn last line is ignored")
+
+	_, err = reloaded.AsOf("USD", "INR", old.Format("2006-01-02"), now)
+	assert.ErrorIs(t, err, domain.ErrNotFound, "records past retention are dropped")
+	found, err := reloaded.AsOf("USD", "INR", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, 83.40, found.Rate)
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"), "loading rewrites the file without them")
+}
@@ -0,0 +1,150 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestOverrideService_Validation(t *testing.T) {
+	repo, err := repository.NewOverrideRepository("")
+	require.NoError(t, err)
+	svc := service.NewOverrideService(repo, zap.NewNop())
+
+	valid := domain.RateOverrideRequest{Pair: "usdinr", Rate: 83, StartDate: "2025-01-01", EndDate: "2025-03-31", Reason: "board resolution 12"}
+
+	tests := []struct {
+		name   string
+		mutate func(*domain.RateOverrideRequest)
+		actor  string
+	}{
+		{"Missing actor", func(r *domain.RateOverrideRequest) {}, ""},
+		{"Missing reason", func(r *domain.RateOverrideRequest) { r.Reason = " " }, "alice"},
+		{"Non-positive rate", func(r *domain.RateOverrideRequest) { r.Rate = 0 }, "alice"},
+		{"Unsupported pair", func(r *domain.RateOverrideRequest) { r.Pair = "USDXYZ" }, "alice"},
+		{"Same currency", func(r *domain.RateOverrideRequest) { r.Pair = "USDUSD" }, "alice"},
+		{"Malformed date", func(r *domain.RateOverrideRequest) { r.StartDate = "01-01-2025" }, "alice"},
+		{"Inverted range", func(r *domain.RateOverrideRequest) { r.EndDate = "2024-12-31" }, "alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.mutate(&req)
+			_, err := svc.Create(&req, tt.actor)
+			assert.ErrorIs(t, err, domain.ErrValidation)
+		})
+	}
+
+	created, err := svc.Create(&valid, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "USDINR", created.Pair)
+	assert.Equal(t, "alice", created.CreatedBy)
+}
+
+func TestOverrideService_RejectsOverlaps(t *testing.T) {
+	repo, err := repository.NewOverrideRepository("")
+	require.NoError(t, err)
+	svc := service.NewOverrideService(repo, zap.NewNop())
+
+	first, err := svc.Create(&domain.RateOverrideRequest{Pair: "USDINR", Rate: 83, StartDate: "2025-01-01", EndDate: "2025-01-31", Reason: "contract"}, "alice")
+	require.NoError(t, err)
+
+	_, err = svc.Create(&domain.RateOverrideRequest{Pair: "USDINR", Rate: 84, StartDate: "2025-01-31", EndDate: "2025-02-28", Reason: "contract"}, "alice")
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	_, err = svc.Create(&domain.RateOverrideRequest{Pair: "INRUSD", Rate: 0.012, StartDate: "2025-01-01", EndDate: "2025-01-31", Reason: "contract"}, "alice")
+	assert.NoError(t, err)
+
+	second, err := svc.Create(&domain.RateOverrideRequest{Pair: "USDINR", Rate: 84, StartDate: "2025-02-01", EndDate: "2025-02-28", Reason: "contract"}, "alice")
+	require.NoError(t, err)
+
+	_, err = svc.Update(second.ID, &domain.RateOverrideRequest{Pair: "USDINR", Rate: 84, StartDate: "2025-01-15", EndDate: "2025-02-28", Reason: "extend"}, "bob")
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	_, err = svc.Update(first.ID, &domain.RateOverrideRequest{Pair: "USDINR", Rate: 83.5, StartDate: "2025-01-01", EndDate: "2025-01-31", Reason: "corrected"}, "bob")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 83.5, svc.Lookup("USD", "INR", "2025-01-31").Rate)
+	assert.Equal(t, 84.0, svc.Lookup("USD", "INR", "2025-02-01").Rate)
+	assert.Nil(t, svc.Lookup("USD", "INR", "2025-03-01"))
+	assert.Nil(t, svc.Lookup("USD", "EUR", "2025-01-15"))
+}
+
+func TestOverrideRepository_PersistsAuditTrail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+
+	repo, err := repository.NewOverrideRepository(path)
+	require.NoError(t, err)
+	svc := service.NewOverrideService(repo, zap.NewNop())
+
+	created, err := svc.Create(&domain.RateOverrideRequest{Pair: "EURUSD", Rate: 1.1, StartDate: "2025-01-01", EndDate: "2025-12-31", Reason: "hedge"}, "alice")
+	require.NoError(t, err)
+	require.NoError(t, svc.Delete(created.ID, "bob", "hedge closed"))
+
+	reloaded, err := repository.NewOverrideRepository(path)
+	require.NoError(t, err)
+
+	_, err = reloaded.Get(created.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	audit, err := reloaded.ListAudit()
+	require.NoError(t, err)
+	require.Len(t, audit, 2)
+	assert.Equal(t, "created", audit[0].Action)
+	assert.Equal(t, "alice", audit[0].Actor)
+	assert.Equal(t, "deleted", audit[1].Action)
+	assert.Equal(t, "bob", audit[1].Actor)
+	assert.Equal(t, "hedge closed", audit[1].Reason)
+	assert.Equal(t, 1.1, audit[1].Override.Rate)
+}
+
+func TestOverrideService_FailedSaveLeavesNoChange(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "store")
+	repo, err := repository.NewOverrideRepository(filepath.Join(dir, "overrides.json"))
+	require.NoError(t, err)
+	svc := service.NewOverrideService(repo, zap.NewNop())
+
+	// A file where the store's directory should be makes every save fail.
+	require.NoError(t, os.WriteFile(dir, nil, 0o644))
+
+	_, err = svc.Create(&domain.RateOverrideRequest{Pair: "USDINR", Rate: 83, StartDate: "2025-01-01", EndDate: "2025-01-31", Reason: "contract"}, "alice")
+	require.Error(t, err)
+
+	overrides, err := svc.List("")
+	require.NoError(t, err)
+	assert.Empty(t, overrides)
+	audit, err := svc.Audit("")
+	require.NoError(t, err)
+	assert.Empty(t, audit)
+	assert.Nil(t, svc.Lookup("USD", "INR", "2025-01-15"))
+}
+
+func TestOverrideService_InversePairIsDerived(t *testing.T) {
+	repo, err := repository.NewOverrideRepository("")
+	require.NoError(t, err)
+	svc := service.NewOverrideService(repo, zap.NewNop())
+
+	created, err := svc.Create(&domain.RateOverrideRequest{Pair: "USDEUR", Rate: 0.8, StartDate: "2025-01-01", EndDate: "2025-01-31", Reason: "contract"}, "alice")
+	require.NoError(t, err)
+
+	inverse := svc.Lookup("EUR", "USD", "2025-01-15")
+	require.NotNil(t, inverse)
+	assert.Equal(t, created.ID, inverse.ID)
+	assert.Equal(t, "EURUSD", inverse.Pair)
+	assert.InDelta(t, 1.25, inverse.Rate, 1e-12)
+	assert.Nil(t, svc.Lookup("EUR", "USD", "2025-02-01"))
+
+	_, err = svc.Create(&domain.RateOverrideRequest{Pair: "EURUSD", Rate: 1.3, StartDate: "2025-01-10", EndDate: "2025-01-20", Reason: "contract"}, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1.3, svc.Lookup("EUR", "USD", "2025-01-15").Rate, "an override of the pair itself wins")
+	assert.Equal(t, 0.8, svc.Lookup("USD", "EUR", "2025-01-15").Rate)
+}
@@ -0,0 +1,209 @@
+package unit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/clock"
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newRateGuard(t *testing.T, options service.GuardOptions) (*service.RateGuard, *clock.Fake) {
+	t.Helper()
+
+	repo, err := repository.NewAnomalyRepository("", 0)
+	require.NoError(t, err)
+	fake := clock.NewFake(time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC))
+	return service.NewRateGuard(repo, fake, options, zap.NewNop()), fake
+}
+
+func TestRateGuard_RejectsNonPositiveRates(t *testing.T) {
+	guard, _ := newRateGuard(t, service.GuardOptions{})
+	ctx := context.Background()
+
+	filtered := guard.FilterRates(ctx, "USD", map[string]float64{"INR": 83.25, "JPY": 0})
+	assert.Equal(t, map[string]float64{"INR": 83.25}, filtered, "no last good JPY rate to fall back to")
+
+	guard.FilterRates(ctx, "USD", map[string]float64{"JPY": 147.2})
+	filtered = guard.FilterRates(ctx, "USD", map[string]float64{"JPY": -1})
+	assert.Equal(t, 147.2, filtered["JPY"], "the last good rate is kept")
+
+	anomalies, err := guard.List("usdjpy", false)
+	require.NoError(t, err)
+	require.Len(t, anomalies, 2)
+	assert.Equal(t, domain.AnomalyNonPositive, anomalies[0].Check)
+	assert.Equal(t, -1.0, anomalies[0].Rate)
+	assert.Equal(t, 147.2, anomalies[0].LastGood)
+}
+
+func TestRateGuard_ChecksInverseConsistency(t *testing.T) {
+	guard, _ := newRateGuard(t, service.GuardOptions{InverseTolerance: 0.05})
+	ctx := context.Background()
+
+	guard.FilterRates(ctx, "INR", map[string]float64{"USD": 0.012})
+
+	rate, ok := guard.FilterRate(ctx, "USD", "INR", 83.25)
+	assert.True(t, ok)
+	assert.Equal(t, 83.25, rate)
+
+	rate, ok = guard.FilterRate(ctx, "USD", "INR", 8.325)
+	assert.True(t, ok)
+	assert.Equal(t, 83.25, rate)
+
+	anomalies, err := guard.List("USDINR", false)
+	require.NoError(t, err)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, domain.AnomalyInverse, anomalies[0].Check)
+}
+
+func TestRateGuard_FlagsJumpsBeyondSigma(t *testing.T) {
+	guard, fake := newRateGuard(t, service.GuardOptions{MaxSigma: 4, MinHistory: 5})
+	ctx := context.Background()
+
+	for _, rate := range []float64{83.0, 83.2, 83.1, 83.3, 83.2, 83.4} {
+		_, ok := guard.FilterRate(ctx, "USD", "INR", rate)
+		require.True(t, ok)
+		fake.Advance(24 * time.Hour)
+	}
+
+	rate, _ := guard.FilterRate(ctx, "USD", "INR", 83.5)
+	assert.Equal(t, 83.5, rate, "an ordinary move passes")
+
+	rate, _ = guard.FilterRate(ctx, "USD", "INR", 95)
+	assert.Equal(t, 83.5, rate, "a jump keeps the last good rate")
+
+	anomalies, err := guard.List("", true)
+	require.NoError(t, err)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, domain.AnomalyJump, anomalies[0].Check)
+
+	_, err = guard.Review(anomalies[0].ID, "", false)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	reviewed, err := guard.Review(anomalies[0].ID, "alice", false)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", reviewed.ReviewedBy)
+
+	anomalies, err = guard.List("", true)
+	require.NoError(t, err)
+	assert.Empty(t, anomalies)
+}
+
+func seedGuardHistory(t *testing.T, guard *service.RateGuard, fake *clock.Fake) {
+	t.Helper()
+
+	ctx := context.Background()
+	for _, rate := range []float64{83.0, 83.2, 83.1, 83.3, 83.2, 83.4} {
+		_, ok := guard.FilterRate(ctx, "USD", "INR", rate)
+		require.True(t, ok)
+		_, ok = guard.FilterRate(ctx, "INR", "USD", 1/rate)
+		require.True(t, ok)
+		fake.Advance(24 * time.Hour)
+	}
+}
+
+func TestRateGuard_AcceptedReviewRebaselinesPair(t *testing.T) {
+	guard, fake := newRateGuard(t, service.GuardOptions{InverseTolerance: 0.05, MaxSigma: 4, MinHistory: 5})
+	seedGuardHistory(t, guard, fake)
+	ctx := context.Background()
+
+	rate, _ := guard.FilterRate(ctx, "USD", "INR", 95)
+	assert.Equal(t, 83.4, rate)
+	rate, _ = guard.FilterRate(ctx, "USD", "INR", 95)
+	assert.Equal(t, 83.4, rate, "without confirmation the move stays rejected")
+
+	anomalies, err := guard.List("USDINR", true)
+	require.NoError(t, err)
+	require.Len(t, anomalies, 1, "the repeated rate is counted, not recorded again")
+	assert.Equal(t, 2, anomalies[0].Count)
+
+	reviewed, err := guard.Review(anomalies[0].ID, "alice", true)
+	require.NoError(t, err)
+	assert.True(t, reviewed.Accepted)
+
+	rate, _ = guard.FilterRate(ctx, "USD", "INR", 95.1)
+	assert.Equal(t, 95.1, rate, "the pair is judged against the accepted rate")
+	rate, _ = guard.FilterRate(ctx, "INR", "USD", 1/95.1)
+	assert.Equal(t, 1/95.1, rate, "the inverse pair is re-baselined too")
+}
+
+func TestRateGuard_RejectsAcceptingNonPositiveRates(t *testing.T) {
+	guard, _ := newRateGuard(t, service.GuardOptions{})
+	guard.FilterRate(context.Background(), "USD", "JPY", 0)
+
+	anomalies, err := guard.List("USDJPY", false)
+	require.NoError(t, err)
+	require.Len(t, anomalies, 1)
+
+	_, err = guard.Review(anomalies[0].ID, "alice", true)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestRateGuard_AcceptsPersistentMoves(t *testing.T) {
+	guard, fake := newRateGuard(t, service.GuardOptions{InverseTolerance: 0.05, MaxSigma: 4, MinHistory: 5, ConfirmAfter: 3})
+	seedGuardHistory(t, guard, fake)
+	ctx := context.Background()
+
+	rate, _ := guard.FilterRate(ctx, "USD", "INR", 95)
+	assert.Equal(t, 83.4, rate)
+	rate, _ = guard.FilterRate(ctx, "USD", "INR", 110)
+	assert.Equal(t, 83.4, rate, "a different level starts the count again")
+	rate, _ = guard.FilterRate(ctx, "USD", "INR", 95)
+	assert.Equal(t, 83.4, rate)
+	rate, _ = guard.FilterRate(ctx, "USD", "INR", 95.2)
+	assert.Equal(t, 83.4, rate)
+
+	rate, ok := guard.FilterRate(ctx, "USD", "INR", 95.1)
+	assert.True(t, ok)
+	assert.Equal(t, 95.1, rate, "the third rate in a row at the new level is accepted")
+
+	rate, _ = guard.FilterRate(ctx, "INR", "USD", 1/95.1)
+	assert.Equal(t, 1/95.1, rate, "the inverse pair follows")
+
+	anomalies, err := guard.List("USDINR", false)
+	require.NoError(t, err)
+	assert.Len(t, anomalies, 3, "95 was rejected twice")
+}
+
+func TestAnomalyRepository_CountsRepeatsAndBoundsRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "anomalies.json")
+	repo, err := repository.NewAnomalyRepository(path, 2)
+	require.NoError(t, err)
+
+	start := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	record := func(id string, rate float64, at time.Time) *domain.RateAnomaly {
+		recorded, err := repo.Record(&domain.RateAnomaly{ID: id, Pair: "USDINR", Check: domain.AnomalyJump, Rate: rate, DetectedAt: at, LastSeenAt: at, Count: 1})
+		require.NoError(t, err)
+		return recorded
+	}
+
+	record("a", 95, start)
+	repeated := record("b", 95, start.Add(time.Minute))
+	assert.Equal(t, "a", repeated.ID)
+	assert.Equal(t, 2, repeated.Count)
+	assert.True(t, repeated.LastSeenAt.Equal(start.Add(time.Minute)))
+
+	record("c", 110, start.Add(2*time.Minute))
+	reviewedAt := start.Add(3 * time.Minute)
+	repeated.ReviewedAt = &reviewedAt
+	require.NoError(t, repo.Update(repeated))
+
+	record("d", 95, start.Add(4*time.Minute))
+
+	reloaded, err := repository.NewAnomalyRepository(path, 2)
+	require.NoError(t, err)
+	anomalies, err := reloaded.List()
+	require.NoError(t, err)
+	require.Len(t, anomalies, 2, "the reviewed anomaly is dropped first")
+	assert.Equal(t, "c", anomalies[0].ID)
+	assert.Equal(t, "d", anomalies[1].ID, "a reviewed anomaly is not counted against")
+	assert.Equal(t, 1, anomalies[1].Count)
+}
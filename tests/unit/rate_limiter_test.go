@@ -0,0 +1,78 @@
+package unit
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/repository"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_TakeAndRefill(t *testing.T) {
+	limiter := service.NewRateLimiter(repository.NewCacheRepository())
+	window := 200 * time.Millisecond
+
+	first := limiter.Take("client", 3, 4, window)
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 4, first.Limit)
+	assert.Equal(t, 1, first.Remaining)
+
+	second := limiter.Take("client", 2, 4, window)
+	assert.False(t, second.Allowed)
+	assert.True(t, second.RetryAfter > 0)
+	assert.True(t, second.RetryAfter <= window)
+
+	other := limiter.Take("other-client", 1, 4, window)
+	assert.True(t, other.Allowed)
+
+	time.Sleep(window)
+
+	refilled := limiter.Take("client", 4, 4, window)
+	assert.True(t, refilled.Allowed)
+	assert.Equal(t, 0, refilled.Remaining)
+}
+
+func TestRateLimiter_CostIsCappedAtLimit(t *testing.T) {
+	limiter := service.NewRateLimiter(repository.NewCacheRepository())
+
+	result := limiter.Take("client", 500, 10, time.Minute)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	unlimited := limiter.Take("client", 1, 0, time.Minute)
+	assert.True(t, unlimited.Allowed)
+}
+
+func TestRateLimiter_SharedCacheHoldsAcrossReplicas(t *testing.T) {
+	dir := t.TempDir()
+	var limiters []*service.RateLimiter
+	for i := 0; i < 2; i++ {
+		store, err := repository.NewSharedCacheRepository(dir)
+		require.NoError(t, err)
+		limiters = append(limiters, service.NewRateLimiter(store))
+	}
+
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(limiter *service.RateLimiter) {
+			defer wg.Done()
+			if limiter.Take("client", 1, 10, time.Hour).Allowed {
+				allowed.Add(1)
+			}
+		}(limiters[i%2])
+	}
+	wg.Wait()
+	assert.Equal(t, int32(10), allowed.Load())
+
+	restarted, err := repository.NewSharedCacheRepository(dir)
+	require.NoError(t, err)
+	assert.False(t, service.NewRateLimiter(restarted).Take("client", 1, 10, time.Hour).Allowed)
+	assert.True(t, limiters[0].Take("other-client", 1, 10, time.Hour).Allowed)
+}
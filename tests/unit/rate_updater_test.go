@@ -0,0 +1,60 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUpdateGroups_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		group domain.UpdateGroup
+	}{
+		{"Missing name", domain.UpdateGroup{Cron: "@hourly", Bases: []string{"USD"}}},
+		{"Malformed cron", domain.UpdateGroup{Name: "g", Cron: "every hour", Bases: []string{"USD"}}},
+		{"Seconds field", domain.UpdateGroup{Name: "g", Cron: "0 0 * * * *", Bases: []string{"USD"}}},
+		{"TZ prefix", domain.UpdateGroup{Name: "g", Cron: "CRON_TZ=Europe/Paris 0 16 * * *", Bases: []string{"USD"}}},
+		{"Unknown timezone", domain.UpdateGroup{Name: "g", Cron: "@hourly", Timezone: "Mars/Olympus", Bases: []string{"USD"}}},
+		{"Unsupported base", domain.UpdateGroup{Name: "g", Cron: "@hourly", Bases: []string{"XYZ"}}},
+		{"Malformed pair", domain.UpdateGroup{Name: "g", Cron: "@hourly", Pairs: []string{"USD"}}},
+		{"Nothing to refresh", domain.UpdateGroup{Name: "g", Cron: "@hourly"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ParseUpdateGroups([]domain.UpdateGroup{tt.group})
+			assert.Error(t, err)
+		})
+	}
+
+	group := domain.UpdateGroup{Name: "g", Cron: "@hourly", Bases: []string{"usd"}}
+	_, err := service.ParseUpdateGroups([]domain.UpdateGroup{group, group})
+	assert.ErrorContains(t, err, "duplicate")
+}
+
+func TestUpdateSchedule_NextInTimezone(t *testing.T) {
+	schedules, err := service.ParseUpdateGroups([]domain.UpdateGroup{
+		{Name: "ecb", Cron: "0 16 * * 1-5", Timezone: "Europe/Paris", Bases: []string{"EUR"}, Pairs: []string{"USDINR"}},
+		service.DefaultUpdateGroup("", ""),
+	})
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+
+	ecb := schedules[0]
+	assert.Equal(t, []string{"EUR"}, ecb.Bases)
+	assert.Equal(t, [][2]string{{"USD", "INR"}}, ecb.Pairs)
+
+	friday := time.Date(2025, 9, 5, 15, 0, 0, 0, time.UTC)
+	assert.True(t, time.Date(2025, 9, 8, 14, 0, 0, 0, time.UTC).Equal(ecb.Next(friday)), "after Friday's 16:00 Paris comes Monday's")
+
+	defaults := schedules[1]
+	assert.Equal(t, "default", defaults.Name)
+	assert.Len(t, defaults.Bases, 5)
+	assert.True(t, time.Date(2025, 9, 5, 16, 0, 0, 0, time.UTC).Equal(defaults.Next(friday)))
+}
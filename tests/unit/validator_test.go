@@ -34,6 +34,35 @@ func TestValidateDate(t *testing.T) {
 	}
 }
 
+func TestCountDays(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   string
+		end     string
+		want    int
+		wantErr bool
+	}{
+		{"Single day", "2025-08-01", "2025-08-01", 1, false},
+		{"Across a month", "2025-07-30", "2025-08-02", 4, false},
+		{"Capped", "2025-01-01", "2025-12-31", 90, false},
+		{"Widest dates", "0001-01-01", "9999-12-31", 90, false},
+		{"Reversed", "2025-08-02", "2025-08-01", 0, true},
+		{"Malformed", "01-08-2025", "2025-08-01", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, err := utils.CountDays(tt.start, tt.end, 90)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, days)
+		})
+	}
+}
+
 func TestIsValidCurrency(t *testing.T) {
 	tests := []struct {
 		currency string
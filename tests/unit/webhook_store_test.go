@@ -0,0 +1,79 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain"
+	"exchange-rate-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepository_PersistsAcrossReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+
+	repo, err := repository.NewWebhookRepository(path, 0)
+	require.NoError(t, err)
+
+	sub := &domain.WebhookSubscription{
+		ID:        "abc",
+		Pair:      "USDINR",
+		Condition: domain.WebhookCondition{Type: "above", Threshold: 84},
+		URL:       "http://example.com",
+		Secret:    "s3cret",
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, repo.Create(sub))
+	require.NoError(t, repo.AddDeadLetter(&domain.WebhookDeadLetter{ID: "dl", SubscriptionID: "abc"}))
+
+	reloaded, err := repository.NewWebhookRepository(path, 0)
+	require.NoError(t, err)
+
+	got, err := reloaded.Get("abc")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", got.Secret)
+
+	letters, err := reloaded.ListDeadLetters()
+	require.NoError(t, err)
+	assert.Len(t, letters, 1)
+
+	require.NoError(t, reloaded.Delete("abc"))
+	_, err = reloaded.Get("abc")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestWebhookRepository_KeepsStateAndCapsDeadLetters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+
+	repo, err := repository.NewWebhookRepository(path, 2)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(&domain.WebhookSubscription{ID: "abc", Pair: "USDINR"}))
+	require.NoError(t, repo.SaveStates(map[string]domain.WebhookState{"abc": {LastRate: 83.25, Day: "2025-09-01", DayRef: 83.1}}))
+	for _, id := range []string{"dl1", "dl2", "dl3"} {
+		require.NoError(t, repo.AddDeadLetter(&domain.WebhookDeadLetter{ID: id, SubscriptionID: "abc"}))
+	}
+
+	reloaded, err := repository.NewWebhookRepository(path, 2)
+	require.NoError(t, err)
+
+	states, err := reloaded.States()
+	require.NoError(t, err)
+	assert.Equal(t, 83.25, states["abc"].LastRate)
+
+	letters, err := reloaded.ListDeadLetters()
+	require.NoError(t, err)
+	require.Len(t, letters, 2, "the oldest dead letter is dropped")
+	assert.Equal(t, "dl2", letters[0].ID)
+
+	require.NoError(t, reloaded.DeleteDeadLetter("dl2"))
+	_, err = reloaded.GetDeadLetter("dl2")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	require.NoError(t, reloaded.Delete("abc"))
+	states, err = reloaded.States()
+	require.NoError(t, err)
+	assert.Empty(t, states, "a deleted subscription's state goes with it")
+}